@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverConstantPrefixes name the constant families the server defines
+// itself, which scripts may alias without defining them.
+var serverConstantPrefixes = []string{
+	"ATTR_", "BRAIN_", "CAN_", "DIR_", "FONT_", "LAYER_", "MEMORY_", "MT_",
+	"SKF_", "SPELLFLAG_", "STATF_", "TALKMODE_",
+}
+
+// aliasEntry is one old-name/new-name line of a [RESDEFNAME] or
+// [RES_RESDEFNAME] section, or a [DEFNAME] entry whose value is another
+// name. prefixed is set for [DEFNAME] entries, whose i_/c_/... targets are
+// already checked as references.
+type aliasEntry struct {
+	target   string
+	file     string
+	line     int
+	prefixed bool
+}
+
+// recordAlias remembers where an alias points; the alias name itself is
+// recorded as a defname so references to it resolve. [DEFNAME] values are
+// only taken as names when they are a single identifier with an underscore,
+// so one-word texts are left alone.
+func (idx *lintIndex) recordAlias(section string, fields []string, file string, lineNum int) {
+	if len(fields) < 2 || strings.ContainsAny(fields[1], "<>") {
+		return
+	}
+	entry := aliasEntry{target: strings.ToUpper(fields[1]), file: file, line: lineNum}
+	if section == "DEFNAME" {
+		if len(fields) != 2 || !isIdentifier(fields[1]) || !strings.Contains(fields[1], "_") {
+			return
+		}
+		entry.prefixed = true
+	}
+	name := strings.ToUpper(fields[0])
+	if _, ok := idx.aliases[name]; !ok {
+		idx.aliases[name] = entry
+	}
+}
+
+// findBrokenAliases follows every alias through aliases of aliases and
+// reports chains that loop or end in a name nothing defines.
+func findBrokenAliases(index *lintIndex) []lintIssue {
+	names := make([]string, 0, len(index.aliases))
+	for name := range index.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []lintIssue
+	for _, name := range names {
+		alias := index.aliases[name]
+		chain := []string{name}
+		seen := map[string]bool{name: true}
+		target := alias.target
+		looped := false
+		for {
+			next, ok := index.aliases[target]
+			if !ok {
+				break
+			}
+			chain = append(chain, target)
+			if seen[target] {
+				looped = true
+				break
+			}
+			seen[target] = true
+			target = next.target
+		}
+		if looped {
+			issues = appendError(issues, alias.file, alias.line, "ALIAS", fmt.Sprintf("ALIAS: alias chain %s loops and never reaches a definition.", strings.Join(chain, " -> ")))
+			continue
+		}
+		if aliasTargetDefined(index, target) || (alias.prefixed && len(chain) == 1 && hasReferencePrefix(target)) {
+			continue
+		}
+		if len(chain) == 1 {
+			issues = appendError(issues, alias.file, alias.line, "ALIAS", fmt.Sprintf("ALIAS: '%s' points to '%s', which is not defined anywhere.", name, target))
+			continue
+		}
+		chain = append(chain, target)
+		issues = appendError(issues, alias.file, alias.line, "ALIAS", fmt.Sprintf("ALIAS: '%s' resolves through %s to '%s', which is not defined anywhere.", name, strings.Join(chain, " -> "), target))
+	}
+	return issues
+}
+
+// aliasTargetDefined reports whether the final target of an alias chain is
+// a number, a server constant, a section ID or a DEFNAME.
+func aliasTargetDefined(index *lintIndex, target string) bool {
+	if _, ok := parseSphereInt(target); ok {
+		return true
+	}
+	for _, prefix := range serverConstantPrefixes {
+		if strings.HasPrefix(target, prefix) {
+			return true
+		}
+	}
+	if _, ok := index.ids[target]; ok {
+		return true
+	}
+	_, ok := index.defnames[target]
+	return ok
+}
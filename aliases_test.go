@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestLintAliasChains(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_lamp_dragon]",
+		"ID=01e7",
+		"[RESDEFNAME backward_compatibility_defs]",
+		"i_dragon_lamp_old i_dragon_lamp",
+		"i_dragon_lamp i_lamp_dragon",
+		"i_torch_old i_torch_new",
+		"i_torch_new i_torch_newer",
+		"i_loop_a i_loop_b",
+		"i_loop_b i_loop_a",
+		"i_plain_old 0a22",
+		"i_expr_old <DEF.I_LAMP>",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "aliases.scp", content)
+	assertHasMessage(t, errs, "ALIAS: alias chain I_LOOP_A -> I_LOOP_B -> I_LOOP_A loops and never reaches a definition.")
+	assertHasMessage(t, errs, "ALIAS: alias chain I_LOOP_B -> I_LOOP_A -> I_LOOP_B loops and never reaches a definition.")
+	assertHasMessage(t, errs, "ALIAS: 'I_TORCH_OLD' resolves through I_TORCH_OLD -> I_TORCH_NEW -> I_TORCH_NEWER to 'I_TORCH_NEWER', which is not defined anywhere.")
+	assertHasMessage(t, errs, "ALIAS: 'I_TORCH_NEW' points to 'I_TORCH_NEWER', which is not defined anywhere.")
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 alias errors, got %+v", errs)
+	}
+}
+
+func TestLintDefnameAliasTargets(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_sword_long]",
+		"ID=0f61",
+		"[DEFNAME weapons]",
+		"best_sword i_sword_long",
+		"starter_sword best_sword",
+		"old_sword starter_blade",
+		"guard_brain brain_guard",
+		"greeting Hello",
+		"broken_sword i_sword_broken",
+		"sword_color 021",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "defname_aliases.scp", content)
+	assertHasMessage(t, errs, "ALIAS: 'OLD_SWORD' points to 'STARTER_BLADE', which is not defined anywhere.")
+	assertHasMessage(t, errs, "UNDECLARED: 'I_SWORD_BROKEN' not defined as ITEMDEF")
+	if len(errs) != 2 || errs[0].line != 9 || errs[1].line != 6 {
+		t.Fatalf("expected errors on lines 9 and 6, got %+v", errs)
+	}
+}
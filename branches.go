@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ifChain follows the branches of an open IF block and the statements each
+// branch runs, nested blocks included.
+type ifChain struct {
+	branches []ifBranch
+}
+
+type ifBranch struct {
+	token      string
+	line       int
+	condition  string
+	statements []string
+}
+
+// newIfChain returns a chain for IF lines, or nil.
+func newIfChain(token, line string, lineNum int) *ifChain {
+	if token != "IF" {
+		return nil
+	}
+	return &ifChain{branches: []ifBranch{{token: token, line: lineNum, condition: normalizeCondition(line)}}}
+}
+
+// addBranch starts the ELIF/ELSEIF/ELSE branch found at lineNum, reporting
+// branches after the ELSE and an ELIF whose condition repeats an earlier one
+// of the chain: neither can ever be reached.
+func (c *ifChain) addBranch(token, line, file string, lineNum int) []lintIssue {
+	if c == nil {
+		return nil
+	}
+	branch := ifBranch{token: token, line: lineNum}
+	var issues []lintIssue
+	for _, prev := range c.branches {
+		if prev.token != "ELSE" {
+			continue
+		}
+		if token == "ELSE" {
+			issues = appendError(issues, file, lineNum, "BLOCK", fmt.Sprintf("BLOCK: second ELSE in the IF at line %d (first ELSE at line %d).", c.branches[0].line, prev.line))
+		} else {
+			issues = appendError(issues, file, lineNum, "BLOCK", fmt.Sprintf("BLOCK: '%s' after the ELSE at line %d of the IF at line %d.", token, prev.line, c.branches[0].line))
+		}
+		break
+	}
+	if token != "ELSE" && !randomConditionPattern.MatchString(line) {
+		branch.condition = normalizeCondition(line)
+		for _, prev := range c.branches {
+			if prev.condition != "" && prev.condition == branch.condition {
+				issues = appendError(issues, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s repeats the condition of the %s at line %d; this branch is unreachable.", token, prev.token, prev.line))
+				break
+			}
+		}
+	}
+	c.branches = append(c.branches, branch)
+	return issues
+}
+
+// conditionText returns the condition of an IF/ELIF/WHILE line.
+func conditionText(line string) string {
+	return strings.TrimSpace(line[len(firstField(line)):])
+}
+
+// normalizeCondition makes conditions comparable regardless of spacing,
+// letter case and enclosing parentheses.
+func normalizeCondition(line string) string {
+	cond := conditionText(line)
+	for len(cond) > 1 && cond[0] == '(' && cond[len(cond)-1] == ')' {
+		cond = strings.TrimSpace(cond[1 : len(cond)-1])
+	}
+	return strings.ToUpper(strings.Join(strings.Fields(cond), ""))
+}
+
+// noteBranchStatement adds a statement to the current branch of every open
+// IF. Branch and end keywords belong to the innermost block itself, so they
+// are only added to the blocks around it.
+func noteBranchStatement(stack []blockState, token, line string) {
+	open := stack
+	if len(open) > 0 && (normalizeEndToken(token) != "" || token == "ELSE" || token == "ELIF" || token == "ELSEIF") {
+		open = open[:len(open)-1]
+	}
+	for _, block := range open {
+		if c := block.chain; c != nil {
+			last := &c.branches[len(c.branches)-1]
+			last.statements = append(last.statements, line)
+		}
+	}
+}
+
+// finish reports an IF/ELSE pair whose branches run the same statements
+// once its ENDIF is reached.
+func (c *ifChain) finish(file string) []lintIssue {
+	if c == nil || len(c.branches) != 2 || c.branches[1].token != "ELSE" {
+		return nil
+	}
+	first, second := c.branches[0].statements, c.branches[1].statements
+	if len(first) == 0 || len(first) != len(second) {
+		return nil
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			return nil
+		}
+	}
+	return appendWarning(nil, file, c.branches[1].line, "LOGIC", fmt.Sprintf("LOGIC: ELSE branch runs the same statements as the IF at line %d; one of them was probably meant to differ.", c.branches[0].line))
+}
+
+// randomConditionPattern matches conditions that roll a random number, which
+// may legitimately repeat in a chain.
+var randomConditionPattern = regexp.MustCompile(`(?i)<\s*(?:R\d|R\(|RAND)`)
+
+var literalComparisonPattern = regexp.MustCompile(`^([-+]?\w+)\s*(==|!=|<=|>=|<|>)\s*([-+]?\w+)$`)
+
+// checkConstantCondition flags IF/ELIF conditions that are a bare number or
+// a comparison of two numbers, usually debugging toggles left behind.
+// WHILE 1 is a deliberate loop and is handled by the loop checks.
+func checkConstantCondition(token, line, file string, lineNum int) []lintIssue {
+	if token != "IF" && token != "ELIF" && token != "ELSEIF" {
+		return nil
+	}
+	cond := conditionText(line)
+	for len(cond) > 1 && cond[0] == '(' && cond[len(cond)-1] == ')' {
+		cond = strings.TrimSpace(cond[1 : len(cond)-1])
+	}
+	result := false
+	if n, ok := parseSphereInt(cond); ok {
+		result = n != 0
+	} else if match := literalComparisonPattern.FindStringSubmatch(cond); len(match) == 4 {
+		left, okLeft := parseSphereInt(match[1])
+		right, okRight := parseSphereInt(match[3])
+		if !okLeft || !okRight {
+			return nil
+		}
+		switch match[2] {
+		case "==":
+			result = left == right
+		case "!=":
+			result = left != right
+		case "<=":
+			result = left <= right
+		case ">=":
+			result = left >= right
+		case "<":
+			result = left < right
+		case ">":
+			result = left > right
+		}
+	} else {
+		return nil
+	}
+	outcome := "false; its branch never runs"
+	if result {
+		outcome = "true; the rest of the chain never runs"
+		if token == "IF" {
+			outcome = "true"
+		}
+	}
+	return appendWarning(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s condition '%s' is always %s.", token, cond, outcome))
+}
+
+// checkSingleEquals flags a bare '=' in an IF/ELIF/WHILE condition outside
+// <...> tokens and quoted text, a typo for '=='. The '>' that closes a
+// token is not an operator, so <LOCAL.X>=5 is reported as well.
+func checkSingleEquals(token, line, file string, lineNum int) []lintIssue {
+	if token != "IF" && token != "ELIF" && token != "ELSEIF" && token != "WHILE" {
+		return nil
+	}
+	cond := conditionText(line)
+	operator := false
+	for i := 0; i < len(cond); i++ {
+		switch ch := cond[i]; {
+		case ch == '<' && i+1 < len(cond) && isAngleTokenStart(cond[i+1]):
+			end, ok := scanAngleExpression(cond, i+1)
+			if !ok {
+				return nil
+			}
+			i = end
+			operator = false
+		case ch == '"':
+			if end := strings.IndexByte(cond[i+1:], '"'); end >= 0 {
+				i += end + 1
+			}
+			operator = false
+		case ch == '=':
+			if operator {
+				operator = false
+				continue
+			}
+			if i+1 < len(cond) && cond[i+1] == '=' {
+				i++
+				continue
+			}
+			return appendWarning(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s condition '%s' uses a single '='; use '==' to compare.", token, cond))
+		default:
+			operator = ch == '<' || ch == '>' || ch == '!'
+		}
+	}
+	return nil
+}
+
+// conditionTerm is one operand of a condition split at its top-level logical
+// and bitwise operators; op is the operator that follows it.
+type conditionTerm struct {
+	text string
+	op   string
+}
+
+// splitConditionTerms splits a condition at the |, ||, & and && operators
+// that are outside parentheses, <...> tokens and quoted text.
+func splitConditionTerms(cond string) []conditionTerm {
+	var terms []conditionTerm
+	start, depth := 0, 0
+	for i := 0; i < len(cond); i++ {
+		switch ch := cond[i]; {
+		case ch == '<' && i+1 < len(cond) && isAngleTokenStart(cond[i+1]):
+			end, ok := scanAngleExpression(cond, i+1)
+			if !ok {
+				return nil
+			}
+			i = end
+		case ch == '"':
+			if end := strings.IndexByte(cond[i+1:], '"'); end >= 0 {
+				i += end + 1
+			}
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case (ch == '|' || ch == '&') && depth == 0:
+			op := string(ch)
+			if i+1 < len(cond) && cond[i+1] == ch {
+				op += op
+			}
+			terms = append(terms, conditionTerm{text: strings.TrimSpace(cond[start:i]), op: op})
+			i += len(op) - 1
+			start = i + 1
+		}
+	}
+	return append(terms, conditionTerm{text: strings.TrimSpace(cond[start:])})
+}
+
+// isComparison reports whether a term compares two values, looking inside
+// enclosing parentheses but not inside <...> tokens or quoted text.
+func isComparison(term string) bool {
+	for i := 0; i < len(term); i++ {
+		switch ch := term[i]; {
+		case ch == '<' && i+1 < len(term) && isAngleTokenStart(term[i+1]):
+			end, ok := scanAngleExpression(term, i+1)
+			if !ok {
+				return false
+			}
+			i = end
+		case ch == '"':
+			if end := strings.IndexByte(term[i+1:], '"'); end >= 0 {
+				i += end + 1
+			}
+		case ch == '<' || ch == '>':
+			return true
+		case (ch == '=' || ch == '!') && i+1 < len(term) && term[i+1] == '=':
+			return true
+		}
+	}
+	return false
+}
+
+// isFlagConstant reports whether term names a bit of one of the flag tables.
+func isFlagConstant(term string) bool {
+	name := strings.ToUpper(strings.Trim(term, "() "))
+	for _, table := range []*flagTable{itemAttrFlags, charStatFlags, itemCanFlags, charCanFlags} {
+		if _, ok := table.bits[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConditionOperators flags IF/ELIF/WHILE conditions that join two
+// comparisons with a bitwise '|' or '&', where '||' or '&&' was meant, and
+// conditions that test a flag constant with '&&' instead of masking it
+// with '&'.
+func checkConditionOperators(token, line, file string, lineNum int) []lintIssue {
+	if token != "IF" && token != "ELIF" && token != "ELSEIF" && token != "WHILE" {
+		return nil
+	}
+	cond := conditionText(line)
+	terms := splitConditionTerms(cond)
+	var issues []lintIssue
+	for i := 0; i+1 < len(terms); i++ {
+		left, right := terms[i].text, terms[i+1].text
+		switch op := terms[i].op; op {
+		case "|", "&":
+			if isComparison(left) && isComparison(right) {
+				issues = appendWarning(issues, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s condition '%s' joins two comparisons with bitwise '%s'; use '%s%s'.", token, cond, op, op, op))
+			}
+		case "&&":
+			flag := ""
+			if isFlagConstant(right) && !isComparison(left) {
+				flag = right
+			} else if isFlagConstant(left) && !isComparison(right) {
+				flag = left
+			}
+			if flag != "" {
+				issues = appendWarning(issues, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s condition '%s' tests flag %s with '&&', which is true for any non-zero value; use '&' to mask the bit.", token, cond, flag))
+			}
+		}
+	}
+	return issues
+}
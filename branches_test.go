@@ -0,0 +1,185 @@
+package main
+
+import "testing"
+
+func TestLintIdenticalIfElseBranches(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_reward]",
+		"IF <SRC.KARMA> > 0",
+		"  IF <SRC.ISPLAYER>",
+		"    SRC.NEWGOLD 10",
+		"  ELSE",
+		"    SRC.NEWGOLD 10",
+		"  ENDIF",
+		"ELSE",
+		"  IF <SRC.ISPLAYER>",
+		"    SRC.NEWGOLD 10",
+		"  ELSE",
+		"    SRC.NEWGOLD 10",
+		"  ENDIF",
+		"ENDIF",
+		"IF <SRC.FAME> > 100",
+		"  SRC.NEWGOLD 5",
+		"ELIF <SRC.FAME> > 50",
+		"  SRC.NEWGOLD 5",
+		"ELSE",
+		"  SRC.NEWGOLD 5",
+		"ENDIF",
+		"IF <SRC.FAME> > 100",
+		"  SRC.NEWGOLD 5",
+		"ELSE",
+		"  SRC.NEWGOLD 1",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "branches.scp", content)
+	assertHasMessage(t, errs, "LOGIC: ELSE branch runs the same statements as the IF at line 3")
+	assertHasMessage(t, errs, "LOGIC: ELSE branch runs the same statements as the IF at line 2")
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 identical-branch warnings, got %+v", errs)
+	}
+	for _, e := range errs {
+		if e.severity != severityWarning {
+			t.Fatalf("expected warnings, got %+v", e)
+		}
+	}
+}
+
+func TestLintConstantConditions(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_debug]",
+		"IF 0",
+		"  SRC.SYSMESSAGE debug",
+		"ELIF (1)",
+		"  SRC.SYSMESSAGE always",
+		"ENDIF",
+		"IF 5 > 3",
+		"ENDIF",
+		"IF (2 == 03)",
+		"ENDIF",
+		"IF <SRC.FAME> > 3",
+		"ENDIF",
+		"WHILE 1",
+		"  RETURN",
+		"ENDWHILE",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "constant.scp", content)
+	assertHasMessage(t, errs, "LOGIC: IF condition '0' is always false; its branch never runs.")
+	assertHasMessage(t, errs, "LOGIC: ELIF condition '1' is always true; the rest of the chain never runs.")
+	assertHasMessage(t, errs, "LOGIC: IF condition '5 > 3' is always true.")
+	assertHasMessage(t, errs, "LOGIC: IF condition '2 == 03' is always false; its branch never runs.")
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 constant condition warnings, got %+v", errs)
+	}
+}
+
+func TestLintSingleEqualsInCondition(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_check]",
+		"IF <SRC.FAME>=5",
+		"ELIF (<SRC.KARMA> = 5)",
+		"ELIF <SRC.FAME> >= 5 && <SRC.KARMA> != 3",
+		"ELIF <SRC.FAME>==5 || <SRC.KARMA> <= 2",
+		"ELIF <STRMATCH(a=b,<SRC.NAME>)>",
+		"ELIF \"<SRC.NAME>\" == \"a=b\"",
+		"ENDIF",
+		"WHILE <SRC.FAME>=5",
+		"  RETURN",
+		"ENDWHILE",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "equals.scp", content)
+	assertHasMessage(t, errs, "LOGIC: IF condition '<SRC.FAME>=5' uses a single '='; use '==' to compare.")
+	assertHasMessage(t, errs, "LOGIC: ELIF condition '(<SRC.KARMA> = 5)' uses a single '='")
+	assertHasMessage(t, errs, "LOGIC: WHILE condition '<SRC.FAME>=5' uses a single '='")
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 single '=' warnings, got %+v", errs)
+	}
+}
+
+func TestLintDuplicateChainConditions(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_rank]",
+		"IF (<SRC.FAME> > 100)",
+		"  SRC.SYSMESSAGE famous",
+		"ELIF <SRC.KARMA> > 100",
+		"  SRC.SYSMESSAGE good",
+		"ELIF <src.fame>  >  100",
+		"  SRC.SYSMESSAGE unreachable",
+		"ELSE",
+		"  IF <SRC.KARMA> > 100",
+		"  ENDIF",
+		"ENDIF",
+		"IF <R3> == 1",
+		"  SRC.SYSMESSAGE one",
+		"ELIF <R3> == 1",
+		"  SRC.SYSMESSAGE another roll",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "chain.scp", content)
+	assertHasMessage(t, errs, "LOGIC: ELIF repeats the condition of the IF at line 2; this branch is unreachable.")
+	if len(errs) != 1 || errs[0].line != 6 {
+		t.Fatalf("expected one duplicate condition error on line 6, got %+v", errs)
+	}
+}
+
+func TestLintBranchOrder(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_order]",
+		"IF <SRC.FAME> > 100",
+		"  SRC.SYSMESSAGE famous",
+		"ELSE",
+		"  SRC.SYSMESSAGE unknown",
+		"ELIF <SRC.KARMA> > 100",
+		"  SRC.SYSMESSAGE good",
+		"ELSE",
+		"  SRC.SYSMESSAGE again",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "order.scp", content)
+	assertHasMessage(t, errs, "BLOCK: 'ELIF' after the ELSE at line 4 of the IF at line 2.")
+	assertHasMessage(t, errs, "BLOCK: second ELSE in the IF at line 2 (first ELSE at line 4).")
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 branch order errors, got %+v", errs)
+	}
+}
+
+func TestLintConditionOperators(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_check]",
+		"IF <SRC.FAME> > 100 | <SRC.KARMA> < 0",
+		"  RETURN 1",
+		"ELIF (<SRC.STR> >= 50) & (<SRC.DEX> >= 50)",
+		"  RETURN 2",
+		"ELIF <SRC.FLAGS> && statf_invisible",
+		"  RETURN 3",
+		"ELIF <SRC.FLAGS> & statf_invisible",
+		"  RETURN 4",
+		"ELIF <SRC.FAME> > 100 || <SRC.KARMA> < 0",
+		"  RETURN 5",
+		"ELIF (<SRC.FLAGS> & statf_hidden) && <SRC.KARMA> < 0",
+		"  RETURN 6",
+		"ELIF <SRC.MAXHITS> | 0100",
+		"  RETURN 7",
+		"ELIF \"<SRC.NAME>\" == \"a|b\"",
+		"  RETURN 8",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "operators.scp", content)
+	assertHasMessage(t, errs, "LOGIC: IF condition '<SRC.FAME> > 100 | <SRC.KARMA> < 0' joins two comparisons with bitwise '|'; use '||'.")
+	assertHasMessage(t, errs, "LOGIC: ELIF condition '(<SRC.STR> >= 50) & (<SRC.DEX> >= 50)' joins two comparisons with bitwise '&'; use '&&'.")
+	assertHasMessage(t, errs, "LOGIC: ELIF condition '<SRC.FLAGS> && statf_invisible' tests flag statf_invisible with '&&', which is true for any non-zero value; use '&' to mask the bit.")
+	if len(errs) != 3 || errs[0].line != 2 || errs[1].line != 4 || errs[2].line != 6 {
+		t.Fatalf("expected warnings on lines 2, 4 and 6, got %+v", errs)
+	}
+}
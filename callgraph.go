@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var expressionCallPattern = regexp.MustCompile(`<([A-Za-z_][A-Za-z0-9_.]*)`)
+
+// callEdge is a line of a FUNCTION or trigger body that may call a
+// FUNCTION. Whether the target is one is only known once every file is read.
+type callEdge struct {
+	target  string
+	file    string
+	line    int
+	guarded bool
+}
+
+// callNode is where a FUNCTION or trigger body starts.
+type callNode struct {
+	file string
+	line int
+}
+
+// callGraph links FUNCTION sections and trigger bodies to the names their
+// statements call. Functions are keyed by name and triggers by their
+// section and ON= line (ITEMDEF I_BELL ON=@DCLICK).
+type callGraph struct {
+	nodes map[string]callNode
+	edges map[string][]callEdge
+	// guards marks functions that already returned from inside a block, so
+	// later calls may be the recursive step after a base case.
+	guards map[string]bool
+}
+
+func newCallGraph() *callGraph {
+	return &callGraph{nodes: make(map[string]callNode), edges: make(map[string][]callEdge), guards: make(map[string]bool)}
+}
+
+// addNode registers the FUNCTION or trigger body starting at lineNum.
+func (g *callGraph) addNode(caller, file string, lineNum int) {
+	if _, ok := g.nodes[caller]; !ok && caller != "" {
+		g.nodes[caller] = callNode{file: file, line: lineNum}
+	}
+}
+
+// addLine records the calls made by a statement of caller. A call is
+// guarded when it sits inside a block or follows a RETURN inside one.
+func (g *callGraph) addLine(caller, token, line string, nested bool, file string, lineNum int) {
+	if caller == "" {
+		return
+	}
+	if token == "RETURN" && nested {
+		g.guards[caller] = true
+	}
+	guarded := nested || g.guards[caller]
+	for _, target := range calledNames(line) {
+		g.edges[caller] = append(g.edges[caller], callEdge{target: target, file: file, line: lineNum, guarded: guarded})
+	}
+}
+
+// calledNames returns the names a statement may call: its first word and
+// the <name> expressions it reads, using the last segment of object paths
+// (SRC.f_heal calls f_heal).
+func calledNames(line string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		segments := strings.Split(path, ".")
+		name := strings.ToUpper(segments[len(segments)-1])
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if first := firstField(line); first != "" {
+		if idx := strings.IndexAny(first, "=(<"); idx >= 0 {
+			first = first[:idx]
+		}
+		add(first)
+	}
+	for _, match := range expressionCallPattern.FindAllStringSubmatch(line, -1) {
+		add(match[1])
+	}
+	return names
+}
+
+// unguardedCall returns the first unguarded call from caller to callee.
+func (g *callGraph) unguardedCall(caller, callee string) (callEdge, bool) {
+	for _, edge := range g.edges[caller] {
+		if edge.target == callee && !edge.guarded {
+			return edge, true
+		}
+	}
+	return callEdge{}, false
+}
+
+// findRecursion reports FUNCTIONs that call themselves, directly or through
+// one or two other FUNCTIONs, without any guard on the way.
+func (g *callGraph) findRecursion(defs map[string]definitionLocation) []lintIssue {
+	isFunction := func(name string) bool {
+		_, ok := defs["FUNCTION "+name]
+		return ok
+	}
+	callers := make([]string, 0, len(g.edges))
+	for caller := range g.edges {
+		callers = append(callers, caller)
+	}
+	sort.Strings(callers)
+
+	var issues []lintIssue
+	reported := make(map[string]bool)
+	report := func(cycle []string, edge callEdge) {
+		key := strings.Join(cycle, " ")
+		if reported[key] {
+			return
+		}
+		reported[key] = true
+		if len(cycle) == 1 {
+			issues = appendError(issues, edge.file, edge.line, "RECURSION", fmt.Sprintf("RECURSION: FUNCTION %s calls itself without a guard (no enclosing IF or earlier RETURN in a block); the server aborts the script.", cycle[0]))
+			return
+		}
+		path := append(append([]string{}, cycle...), cycle[0])
+		issues = appendError(issues, edge.file, edge.line, "RECURSION", fmt.Sprintf("RECURSION: FUNCTION cycle %s without a guard; the server aborts the script.", strings.Join(path, " -> ")))
+	}
+
+	for _, a := range callers {
+		if !isFunction(a) {
+			continue
+		}
+		for _, ab := range g.edges[a] {
+			b := ab.target
+			if ab.guarded || !isFunction(b) {
+				continue
+			}
+			if b == a {
+				report([]string{a}, ab)
+				continue
+			}
+			if b < a {
+				continue
+			}
+			if _, ok := g.unguardedCall(b, a); ok {
+				report([]string{a, b}, ab)
+				continue
+			}
+			for _, bc := range g.edges[b] {
+				c := bc.target
+				if bc.guarded || c <= a || c == b || !isFunction(c) {
+					continue
+				}
+				if _, ok := g.unguardedCall(c, a); ok {
+					report([]string{a, b, c}, ab)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// functionCalls returns, for every node, the FUNCTIONs it calls, each once
+// and sorted.
+func (g *callGraph) functionCalls(defs map[string]definitionLocation) map[string][]string {
+	calls := make(map[string][]string)
+	for caller, edges := range g.edges {
+		seen := make(map[string]bool)
+		for _, edge := range edges {
+			if _, ok := defs["FUNCTION "+edge.target]; !ok || seen[edge.target] {
+				continue
+			}
+			seen[edge.target] = true
+			calls[caller] = append(calls[caller], edge.target)
+		}
+		sort.Strings(calls[caller])
+	}
+	return calls
+}
+
+// sortedNodes returns the node names in a stable order.
+func (g *callGraph) sortedNodes() []string {
+	names := make([]string, 0, len(g.nodes))
+	for name := range g.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeDOT prints the graph in Graphviz format; trigger bodies are boxes.
+func (g *callGraph) writeDOT(w io.Writer, defs map[string]definitionLocation) {
+	calls := g.functionCalls(defs)
+	fmt.Fprintln(w, "digraph calls {")
+	for _, name := range g.sortedNodes() {
+		shape := "ellipse"
+		if _, ok := defs["FUNCTION "+name]; !ok {
+			shape = "box"
+		}
+		fmt.Fprintf(w, "  %s [shape=%s];\n", strconv.Quote(name), shape)
+	}
+	for _, name := range g.sortedNodes() {
+		for _, target := range calls[name] {
+			fmt.Fprintf(w, "  %s -> %s;\n", strconv.Quote(name), strconv.Quote(target))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+type callGraphJSON struct {
+	Nodes []callNodeJSON `json:"nodes"`
+	Edges []callEdgeJSON `json:"edges"`
+}
+
+type callNodeJSON struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+type callEdgeJSON struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// writeJSON prints the graph as {"nodes": [...], "edges": [...]}.
+func (g *callGraph) writeJSON(w io.Writer, defs map[string]definitionLocation) error {
+	calls := g.functionCalls(defs)
+	out := callGraphJSON{Nodes: []callNodeJSON{}, Edges: []callEdgeJSON{}}
+	for _, name := range g.sortedNodes() {
+		node := g.nodes[name]
+		kind := "trigger"
+		if _, ok := defs["FUNCTION "+name]; ok {
+			kind = "function"
+		}
+		out.Nodes = append(out.Nodes, callNodeJSON{ID: name, Kind: kind, File: node.file, Line: node.line})
+		for _, target := range calls[name] {
+			out.Edges = append(out.Edges, callEdgeJSON{From: name, To: target})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// longestChains returns, for every node nobody calls, its longest call
+// chain when that chain is deeper than maxDepth calls. Cycles are cut where
+// they close; the recursion rule reports them.
+func (g *callGraph) longestChains(defs map[string]definitionLocation, maxDepth int) [][]string {
+	calls := g.functionCalls(defs)
+	called := make(map[string]bool)
+	for _, targets := range calls {
+		for _, target := range targets {
+			called[target] = true
+		}
+	}
+
+	memo := make(map[string][]string)
+	onPath := make(map[string]bool)
+	var longest func(name string) []string
+	longest = func(name string) []string {
+		if chain, ok := memo[name]; ok {
+			return chain
+		}
+		onPath[name] = true
+		var best []string
+		for _, target := range calls[name] {
+			if onPath[target] {
+				continue
+			}
+			if chain := longest(target); len(chain) > len(best) {
+				best = chain
+			}
+		}
+		onPath[name] = false
+		chain := append([]string{name}, best...)
+		memo[name] = chain
+		return chain
+	}
+
+	var chains [][]string
+	for _, name := range g.sortedNodes() {
+		if called[name] {
+			continue
+		}
+		if chain := longest(name); len(chain)-1 > maxDepth {
+			chains = append(chains, chain)
+		}
+	}
+	return chains
+}
+
+// runCallGraph implements "sphere-lint callgraph": it prints the
+// FUNCTION/trigger call graph as DOT or JSON and reports call chains deeper
+// than the configured maximum, exiting 1 if there are any.
+func runCallGraph(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint callgraph", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	format := flags.String("format", "dot", "output format: dot or json")
+	outPath := flags.String("out", "", "write the graph to this file instead of stdout")
+	maxDepth := flags.Int("max-depth", 0, "longest allowed call chain (default: maxCallDepth from the config)")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *format != "dot" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "callgraph: unknown -format %q (use dot or json)\n", *format)
+		return 2
+	}
+	if *maxDepth <= 0 {
+		*maxDepth = config.MaxCallDepth
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	var out io.Writer = os.Stdout
+	report := os.Stderr
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		out, report = file, os.Stdout
+	}
+	if *format == "json" {
+		if err := index.calls.writeJSON(out, index.defs); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	} else {
+		index.calls.writeDOT(out, index.defs)
+	}
+
+	chains := index.calls.longestChains(index.defs, *maxDepth)
+	for _, chain := range chains {
+		node := index.calls.nodes[chain[0]]
+		fmt.Fprintf(report, "DEPTH %s:%d: call chain of depth %d exceeds %d: %s\n", node.file, node.line, len(chain)-1, *maxDepth, strings.Join(chain, " -> "))
+	}
+	if len(walkIssues) > 0 || len(chains) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLintFunctionRecursion(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_loop]",
+		"SRC.SYSMESSAGE again",
+		"f_loop",
+		"[FUNCTION f_ping]",
+		"SRC.f_pong",
+		"[FUNCTION f_pong]",
+		"LOCAL.X=<f_ping>",
+		"[FUNCTION f_a]",
+		"f_b",
+		"[FUNCTION f_b]",
+		"f_c",
+		"[FUNCTION f_c]",
+		"f_a",
+		"[FUNCTION f_countdown]",
+		"IF <ARGN1> <= 0",
+		"  RETURN",
+		"ENDIF",
+		"f_countdown <EVAL <ARGN1>-1>",
+		"[FUNCTION f_walk]",
+		"IF <ARGN1> > 0",
+		"  f_walk <EVAL <ARGN1>-1>",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "recursion.scp", content)
+	assertHasMessage(t, errs, "RECURSION: FUNCTION F_LOOP calls itself without a guard")
+	assertHasMessage(t, errs, "RECURSION: FUNCTION cycle F_PING -> F_PONG -> F_PING without a guard")
+	assertHasMessage(t, errs, "RECURSION: FUNCTION cycle F_A -> F_B -> F_C -> F_A without a guard")
+	if len(errs) != 3 || errs[0].line != 9 || errs[1].line != 3 || errs[2].line != 5 {
+		t.Fatalf("expected 3 recursion errors on lines 9, 3 and 5, got %+v", errs)
+	}
+}
+
+func TestCallGraphExport(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	path := writeTempFile(t, dir, "calls.scp", joinLines(
+		"[ITEMDEF i_bell]",
+		"ON=@DClick",
+		"  f_ring",
+		"[FUNCTION f_ring]",
+		"SRC.SYSMESSAGE <f_sound>",
+		"[FUNCTION f_sound]",
+		"SOUND 0f5",
+		"[EOF]",
+	))
+	index := newLintIndex()
+	lintScriptFile(path, index)
+
+	var dot bytes.Buffer
+	index.calls.writeDOT(&dot, index.defs)
+	for _, want := range []string{
+		`"ITEMDEF I_BELL ON=@DCLICK" [shape=box];`,
+		`"F_RING" [shape=ellipse];`,
+		`"ITEMDEF I_BELL ON=@DCLICK" -> "F_RING";`,
+		`"F_RING" -> "F_SOUND";`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Fatalf("expected DOT output to contain %q, got:\n%s", want, dot.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := index.calls.writeJSON(&out, index.defs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var graph callGraphJSON
+	if err := json.Unmarshal(out.Bytes(), &graph); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 || graph.Nodes[0].Kind != "function" || graph.Nodes[2].Line != 2 {
+		t.Fatalf("unexpected JSON graph %+v", graph)
+	}
+
+	if chains := index.calls.longestChains(index.defs, 2); len(chains) != 0 {
+		t.Fatalf("expected no chain deeper than 2, got %v", chains)
+	}
+	chains := index.calls.longestChains(index.defs, 1)
+	if len(chains) != 1 || strings.Join(chains[0], " -> ") != "ITEMDEF I_BELL ON=@DCLICK -> F_RING -> F_SOUND" {
+		t.Fatalf("expected the trigger chain to exceed depth 1, got %v", chains)
+	}
+}
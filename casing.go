@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// declaredSpelling is how a definition writes its ID in its header.
+type declaredSpelling struct {
+	name string
+	definitionLocation
+}
+
+// recordSpelling keeps the first header spelling of a definition ID.
+func (idx *lintIndex) recordSpelling(name, file string, lineNum int) {
+	id := strings.ToUpper(name)
+	if _, ok := idx.spellings[id]; !ok {
+		idx.spellings[id] = declaredSpelling{name: name, definitionLocation: definitionLocation{file: file, line: lineNum}}
+	}
+}
+
+// findCasingMismatches reports prefixed references (i_, c_, f_, ...) whose
+// letter case differs from the definition header. The server does not mind,
+// but a case-sensitive search for the defined name misses them.
+func findCasingMismatches(index *lintIndex) []lintIssue {
+	var issues []lintIssue
+	seen := make(map[string]bool)
+	for _, ref := range index.references {
+		declared, ok := index.spellings[ref.id]
+		if ref.text == "" || !ok || ref.text == declared.name {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d:%s", ref.file, ref.line, ref.text)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		issues = appendWarning(issues, ref.file, ref.line, "STYLE", fmt.Sprintf("STYLE: '%s' is written '%s' where it is defined (%s:%d)", ref.text, declared.name, declared.file, declared.line))
+	}
+	return issues
+}
+
+// collectSpellings reads the header spelling of every tracked definition
+// under root, for the format command's -casing fixer. Unreadable files and
+// walk errors are left to the formatting pass, which reports them.
+func collectSpellings(root string) map[string]string {
+	spellings := make(map[string]string)
+	walkScripts(root, func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			match := defHeaderPattern.FindStringSubmatch(cleanLine(line))
+			if len(match) != 3 || !trackDefTypes[strings.ToUpper(match[1])] {
+				continue
+			}
+			name := firstField(match[2])
+			if _, ok := spellings[strings.ToUpper(name)]; !ok && name != "" {
+				spellings[strings.ToUpper(name)] = name
+			}
+		}
+	})
+	return spellings
+}
+
+// fixReferenceCasing rewrites the prefixed references of content to the
+// spelling of their definition. Comments are left as they are.
+func fixReferenceCasing(content string, spellings map[string]string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		code, comment := line, ""
+		if idx := commentIndex(line); idx >= 0 {
+			code, comment = line[:idx], line[idx:]
+		}
+		for _, pattern := range refPatterns {
+			code = pattern.re.ReplaceAllStringFunc(code, func(match string) string {
+				if name, ok := spellings[strings.ToUpper(match)]; ok {
+					return name
+				}
+				return match
+			})
+		}
+		lines[i] = code + comment
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLintReferenceCasing(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_sword_long]",
+		"ID=0f61",
+		"[FUNCTION f_Arm]",
+		"SRC.NEWITEM i_Sword_Long",
+		"NEW.EQUIP",
+		"SERV.NEWITEM i_sword_long",
+		"[EVENTS e_guard]",
+		"ON=@Create",
+		"f_arm // calls F_ARM",
+		"f_Arm",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "casing.scp", content), "mixed-case references without the reference-casing rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"reference-casing"}
+		})
+		errs := lintFromContent(t, "casing.scp", content)
+		assertHasMessage(t, errs, "STYLE: 'i_Sword_Long' is written 'i_sword_long' where it is defined (casing.scp:1)")
+		assertHasMessage(t, errs, "STYLE: 'f_arm' is written 'f_Arm' where it is defined (casing.scp:3)")
+		if len(errs) != 2 || errs[0].line != 4 || errs[1].line != 9 {
+			t.Fatalf("expected warnings on lines 4 and 9, got %+v", errs)
+		}
+	})
+}
+
+func TestRunFormatCasing(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "defs.scp", joinLines("[ITEMDEF i_sword_long]", "ID=0f61", "[EOF]"))
+	path := writeTempFile(t, dir, "uses.scp", joinLines("[FUNCTION f_arm]", "SRC.NEWITEM I_SWORD_LONG // I_SWORD_LONG", "[EOF]"))
+
+	if code := runFormat([]string{"-check"}); code != 0 {
+		t.Fatalf("expected casing to be left alone without -casing, got %d", code)
+	}
+	if code := runFormat([]string{"-casing"}); code != 0 {
+		t.Fatalf("expected formatting to succeed, got %d", code)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if want := joinLines("[FUNCTION f_arm]", "SRC.NEWITEM i_sword_long // I_SWORD_LONG", "[EOF]"); string(data) != want {
+		t.Fatalf("unexpected formatted file: %q", data)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// catalogEntry is one item, NPC or spell of the catalogue.
+type catalogEntry struct {
+	Name        string `json:"name"`
+	Defname     string `json:"defname"`
+	Type        string `json:"type"`
+	Category    string `json:"category,omitempty"`
+	Subsection  string `json:"subsection,omitempty"`
+	Description string `json:"description,omitempty"`
+	Resources   string `json:"resources,omitempty"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+}
+
+// scriptCatalog groups the entries the way the wiki shows them.
+type scriptCatalog struct {
+	Items  []catalogEntry `json:"items"`
+	NPCs   []catalogEntry `json:"npcs"`
+	Spells []catalogEntry `json:"spells"`
+}
+
+// newCatalogEntry describes s. Base definitions with a hex ID are known by
+// their first DEFNAME; an NPC's type is the body it is based on.
+func newCatalogEntry(s *symbolInfo) catalogEntry {
+	defname := s.ID
+	if isHexDigits(s.ID) && len(s.Defnames) > 0 {
+		defname = s.Defnames[0]
+	}
+	typ := s.Properties["TYPE"]
+	if s.Type == "CHARDEF" {
+		typ = s.Properties["ID"]
+	}
+	return catalogEntry{
+		Name:        s.Properties["NAME"],
+		Defname:     defname,
+		Type:        typ,
+		Category:    s.Properties["CATEGORY"],
+		Subsection:  s.Properties["SUBSECTION"],
+		Description: s.Properties["DESCRIPTION"],
+		Resources:   s.Properties["RESOURCES"],
+		File:        s.File,
+		Line:        s.Line,
+	}
+}
+
+// buildCatalog collects the items, NPCs and spells of index, sorted by type
+// and ID.
+func buildCatalog(index *lintIndex) scriptCatalog {
+	catalog := scriptCatalog{Items: []catalogEntry{}, NPCs: []catalogEntry{}, Spells: []catalogEntry{}}
+	for _, s := range sortedSymbols(index) {
+		switch s.Type {
+		case "ITEMDEF":
+			catalog.Items = append(catalog.Items, newCatalogEntry(s))
+		case "CHARDEF":
+			catalog.NPCs = append(catalog.NPCs, newCatalogEntry(s))
+		case "SPELL":
+			catalog.Spells = append(catalog.Spells, newCatalogEntry(s))
+		}
+	}
+	return catalog
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value string) string {
+	return strings.ReplaceAll(strings.TrimSpace(value), "|", `\|`)
+}
+
+// writeCatalogMarkdown prints one table per group, skipping empty groups.
+func writeCatalogMarkdown(w io.Writer, catalog scriptCatalog) {
+	fmt.Fprintln(w, "# Script Catalogue")
+	groups := []struct {
+		title   string
+		entries []catalogEntry
+	}{{"Items", catalog.Items}, {"NPCs", catalog.NPCs}, {"Spells", catalog.Spells}}
+	for _, group := range groups {
+		if len(group.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n## %s\n\n", group.title)
+		fmt.Fprintln(w, "| Name | Defname | Type | Category | Resources | Source |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- |")
+		for _, e := range group.entries {
+			category := e.Category
+			if e.Subsection != "" {
+				category = strings.TrimPrefix(category+" / "+e.Subsection, " / ")
+			}
+			fmt.Fprintf(w, "| %s | `%s` | %s | %s | %s | %s:%d |\n", markdownCell(e.Name), e.Defname, markdownCell(e.Type), markdownCell(category), markdownCell(e.Resources), e.File, e.Line)
+		}
+	}
+}
+
+// runCatalog implements "sphere-lint catalog": it prints the items, NPCs
+// and spells of the scripts as Markdown or JSON, so a wiki can be rebuilt
+// from them.
+func runCatalog(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint catalog", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	format := flags.String("format", "markdown", "output format: markdown or json")
+	outPath := flags.String("out", "", "write the catalogue to this file instead of stdout")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "catalog: unknown -format %q (use markdown or json)\n", *format)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		out = file
+	}
+	catalog := buildCatalog(index)
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(catalog); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	} else {
+		writeCatalogMarkdown(out, catalog)
+	}
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
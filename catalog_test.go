@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBuildCatalog(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	lintScriptFile(writeTempFile(t, dir, "catalog.scp", joinLines(
+		"[ITEMDEF 0f61]",
+		"DEFNAME=i_sword_long",
+		"NAME=long sword",
+		"TYPE=t_weapon_sword",
+		"CATEGORY=Weapons",
+		"SUBSECTION=Swords",
+		"RESOURCES=14 i_ingot_iron",
+		"[CHARDEF c_dragon_fire]",
+		"ID=c_dragon",
+		"NAME=Fire | Dragon",
+		"[SPELL 4]",
+		"DEFNAME=s_heal",
+		"NAME=Heal",
+		"RESOURCES=i_reag_ginseng",
+		"[FUNCTION f_test]",
+		"SERV.LOG hi",
+		"[EOF]",
+	)), index)
+
+	catalog := buildCatalog(index)
+	if len(catalog.Items) != 1 || len(catalog.NPCs) != 1 || len(catalog.Spells) != 1 {
+		t.Fatalf("expected one entry per group, got %+v", catalog)
+	}
+	if item := catalog.Items[0]; item.Defname != "i_sword_long" || item.Type != "t_weapon_sword" || item.Resources != "14 i_ingot_iron" {
+		t.Fatalf("unexpected item entry %+v", item)
+	}
+	if spell := catalog.Spells[0]; spell.Defname != "s_heal" || spell.Name != "Heal" {
+		t.Fatalf("unexpected spell entry %+v", spell)
+	}
+
+	var out bytes.Buffer
+	writeCatalogMarkdown(&out, catalog)
+	for _, want := range []string{
+		"## Items",
+		"| long sword | `i_sword_long` | t_weapon_sword | Weapons / Swords | 14 i_ingot_iron | catalog.scp:1 |",
+		"| Fire \\| Dragon | `c_dragon_fire` | c_dragon |  |  | catalog.scp:8 |",
+		"## Spells",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in the catalogue:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// invisibleRunes are characters that editors show as nothing (or as a
+	// plain space) but that become part of an identifier.
+	invisibleRunes = map[rune]string{
+		0x00A0: "no-break space",
+		0x00AD: "soft hyphen",
+		0x200B: "zero width space",
+		0x200C: "zero width non-joiner",
+		0x200D: "zero width joiner",
+		0x2060: "word joiner",
+		0xFEFF: "zero width no-break space",
+	}
+
+	// confusableRunes maps Cyrillic and Greek letters onto the ASCII letters
+	// they are indistinguishable from in most fonts.
+	confusableRunes = map[rune]rune{
+		'а': 'a', 'в': 'B', 'е': 'e', 'к': 'k', 'м': 'M', 'н': 'H', 'о': 'o',
+		'р': 'p', 'с': 'c', 'т': 'T', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j',
+		'ѕ': 's', 'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H',
+		'О': 'O', 'Р': 'P', 'С': 'C', 'Т': 'T', 'Х': 'X', 'І': 'I', 'Ј': 'J',
+		'Ѕ': 'S', 'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I',
+		'Κ': 'K', 'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y',
+		'Χ': 'X', 'ο': 'o', 'ν': 'v',
+	}
+)
+
+// checkHeaderChars reports the first non-ASCII character of a section
+// header: the server looks definitions up by their ASCII name, so a header
+// that only looks right can never be referenced.
+func checkHeaderChars(raw, file string, lineNum int) []lintIssue {
+	line := stripLineComment(raw)
+	for i, r := range line {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		return []lintIssue{{file: file, line: lineNum, col: utf8.RuneCountInString(raw[:i]) + 1, kind: "CHARSET",
+			msg: fmt.Sprintf("CHARSET: section header %s contains %s", strconv.QuoteToASCII(strings.TrimSpace(line)), describeRune(r))}}
+	}
+	return nil
+}
+
+// checkIdentifierChars reports identifiers holding non-ASCII characters in
+// a script line. The first word (command, property or defname) and words
+// with an underscore are identifiers; other words are only reported when
+// they mix ASCII with invisible or look-alike characters, so translated
+// text is left alone.
+func checkIdentifierChars(raw, file string, lineNum int) []lintIssue {
+	line := stripLineComment(raw)
+	var issues []lintIssue
+	first := true
+	for start := 0; start < len(line); {
+		r, size := utf8.DecodeRuneInString(line[start:])
+		if !isIdentifierRune(r) {
+			start += size
+			continue
+		}
+		end := start
+		for end < len(line) {
+			r, size := utf8.DecodeRuneInString(line[end:])
+			if !isIdentifierRune(r) {
+				break
+			}
+			end += size
+		}
+		word := line[start:end]
+		if offset, r := suspiciousRune(word, first); offset >= 0 {
+			issues = append(issues, lintIssue{file: file, line: lineNum, col: utf8.RuneCountInString(raw[:start+offset]) + 1, kind: "CHARSET",
+				msg: fmt.Sprintf("CHARSET: identifier %s contains %s", strconv.QuoteToASCII(word), describeRune(r))})
+		}
+		first = false
+		start = end
+	}
+	return issues
+}
+
+// suspiciousRune returns the byte offset and value of the character that
+// makes word a broken identifier, or -1.
+func suspiciousRune(word string, identifier bool) (int, rune) {
+	hasASCII, offset := false, -1
+	var found rune
+	tricky := false
+	for i, r := range word {
+		if r < utf8.RuneSelf {
+			if r == '_' {
+				identifier = true
+			} else if r != '.' {
+				hasASCII = true
+			}
+			continue
+		}
+		// A no-break space is ordinary typography in text, so only zero
+		// width characters and look-alikes count against plain words.
+		_, invisible := invisibleRunes[r]
+		invisible = invisible && r != 0x00A0
+		_, confusable := confusableRunes[r]
+		if offset < 0 || ((invisible || confusable) && !tricky) {
+			offset, found = i, r
+			tricky = invisible || confusable
+		}
+	}
+	if offset < 0 || !(identifier || (tricky && hasASCII)) {
+		return -1, 0
+	}
+	return offset, found
+}
+
+func isIdentifierRune(r rune) bool {
+	if _, ok := invisibleRunes[r]; ok {
+		return true
+	}
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func describeRune(r rune) string {
+	if name, ok := invisibleRunes[r]; ok {
+		return fmt.Sprintf("U+%04X (%s)", r, name)
+	}
+	if ascii, ok := confusableRunes[r]; ok {
+		return fmt.Sprintf("U+%04X, which looks like '%c' but is not ASCII", r, ascii)
+	}
+	return fmt.Sprintf("non-ASCII character U+%04X", r)
+}
+
+func stripLineComment(line string) string {
+	if idx := commentIndex(line); idx >= 0 {
+		return line[:idx]
+	}
+	return line
+}
+
+// controlCharNames names the control characters that commonly end up in
+// scripts from bad exports.
+var controlCharNames = map[byte]string{
+	0x00: "NUL",
+	0x0B: "vertical tab",
+	0x0C: "form feed",
+	0x0D: "carriage return",
+	0x1A: "substitute (Ctrl-Z)",
+	0x1B: "escape",
+	0x7F: "delete",
+}
+
+// checkControlChars reports the first raw control character other than tab
+// in a line; the server truncates lines at NULs and chokes on the others.
+func checkControlChars(raw, file string, lineNum int) []lintIssue {
+	for i := 0; i < len(raw); i++ {
+		b := raw[i]
+		if (b >= 0x20 && b != 0x7F) || b == '\t' {
+			continue
+		}
+		name := controlCharNames[b]
+		if name == "" {
+			name = "control character"
+		}
+		return []lintIssue{{file: file, line: lineNum, col: utf8.RuneCountInString(raw[:i]) + 1, kind: "CHARSET",
+			msg: fmt.Sprintf("CHARSET: raw %s (U+%04X) in line; the server may cut the line short here", name, b)}}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestLintIdentifierCharacters(t *testing.T) {
+	t.Run("Reported", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_sword\u200b]",
+			"ID=0f5e",
+			"[FUNCTION f_test]",
+			"SERV.NEWITEM i_sw\u043erd",
+			"IF <SRC.T\u0410G.LEVEL>",
+			"ENDIF",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "charset.scp", content)
+		assertHasMessage(t, errs, `CHARSET: section header "[ITEMDEF i_sword\u200b]" contains U+200B (zero width space)`)
+		assertHasMessage(t, errs, `CHARSET: identifier "i_sw\u043erd" contains U+043E, which looks like 'o' but is not ASCII`)
+		assertHasMessage(t, errs, `CHARSET: identifier "SRC.T\u0410G.LEVEL" contains U+0410`)
+		var cols []int
+		for _, e := range errs {
+			if e.kind == "CHARSET" {
+				cols = append(cols, e.col)
+			}
+		}
+		if len(cols) != 3 || cols[0] != 17 || cols[1] != 18 || cols[2] != 10 {
+			t.Fatalf("expected CHARSET columns [17 18 10], got %v (%+v)", cols, errs)
+		}
+	})
+
+	t.Run("TranslatedText", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_epee]",
+			"ID=0f5e",
+			"NAME=épée longue",
+			"[FUNCTION f_greet]",
+			"SAY привет, café !",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "charset.scp", content), "non-ASCII text in values")
+	})
+}
+
+func TestLintControlCharacters(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_test]",
+		"SAY hello\x00world",
+		"\tSAY\x0bagain",
+		"SAY tabs\tare fine",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "control.scp", content)
+	assertHasMessage(t, errs, "CHARSET: raw NUL (U+0000) in line")
+	assertHasMessage(t, errs, "CHARSET: raw vertical tab (U+000B) in line")
+	if len(errs) != 2 || errs[0].line != 2 || errs[0].col != 10 || errs[1].line != 3 || errs[1].col != 5 {
+		t.Fatalf("expected control characters at 2:10 and 3:5, got %+v", errs)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// scriptCommandNames lists the built-in verbs and properties a statement
+// may start with, beyond the keywords, objects and properties other rules
+// already know about. Skill names are properties too (MAGERY=100.0). The
+// list is not complete yet, so the unknown-commands rule is opt-in.
+const scriptCommandNames = `
+ACTARG1 ACTARG2 ACTARG3 ACTDIFF ACTION ACTP ACTPRV ADD ADDBUFF ADDCLILOC
+ADDCONTEXTENTRY ADDITEM ADDNPC ADDSPELL AFK ALLSKILLS AMOUNT ANIM ARGN ARGN1
+ARGN2 ARGN3 ARGS ARMOR ARROWQUEST ATTACK ATTR BANK BARK BODY BOUNCE BOW BREATH
+BUY CALL CAN CANMAKE CANUSE CAST CLEARCTAGS CLEARTAGS CLICK CLIENTVERSION
+COLOR CONSUME CONT CONTAINER CONTROL CREATE CRIMINAL CURE DAM DAMAGE DCLICK
+DEFNAME DELETE DESC DEX DIALOG DIALOGCLOSE DIR DISCONNECT DISMOUNT DISPID DROP
+DUPE DUPEITEM DUPELIST DYE EDIT EFFECT EMOTE EMOTEACT EQUIP EQUIPARMOR
+EQUIPHALO EQUIPWEAPON EVENTS EXP EXTRACT FACE FAME FIX FIXWEIGHT FLAGS FLEE
+FLIP FOLLOW FONT FOOD FORGIVE GM GO GOCHAR GOCHARID GOCLI GOITEMID GONAME
+GOPLACE GOSOCK GOTYPE GOUID HEAL HEAR HEARALL HITPOINTS HITS HOME HOMEDIST HUE
+HUNGRY I ICON ID INFO INPDLG INT INVIS INVUL ITEM ITEMNEWBIE JAIL KARMA KICK
+KILL LAYER LEVEL LIGHT LINK LOG LUCK MAKEITEM MANA MAXFOOD MAXHITS MAXMANA
+MAXSTAM MAXWEIGHT MEMORY MENU MESSAGE MESSAGEUA MODAR MODDEX MODINT MODSTR
+MORE MORE1 MORE2 MOREM MOREP MOREX MOREY MOREZ MOUNT MOVE MOVENEAR MOVENEARZ
+MOVETO MUSIC NAME NEW NEWBIESKILL NEWDUPE NEWGOLD NEWITEM NEWLOOT NEWNPC
+NIGHTSIGHT NOTOCLEAR NOTOUPDATE NPC NPCFLAGS NUDGEDOWN NUDGEUP OBJ OBODY ODEX
+OINT OPENPAPERDOLL OSKIN OSTR OWNER P PACK PAGE PARDON POISON POLY PRICE
+PRIVSET PROMPTCONSOLE PROMPTCONSOLEU RELEASE REMOVE REMOVEBUFF REMOVEFROMVIEW
+RESCOLD RESCOUNT RESEND RESENDTOOLTIP RESENERGY RESFIRE RESOURCES RESPHYSICAL
+RESPOISON RESTEST RESURRECT REVEAL SALUTE SAY SAYU SAYUA SDIALOG SELL SERIAL
+SHOW SKILL SKILLGAIN SKILLMENU SKILLUSEQUICK SKIN SLEEP SMSG SMSGU SOUND SPEAK
+SPEAKU SPEAKUA SPEECH SPEECHCOLOR SPEED SPELLEFFECT STAM STONE STR SUICIDE
+SUMMONCAGE SUMMONTO SYSMESSAGE SYSMESSAGEF SYSMESSAGELOC SYSMESSAGELOCEX
+SYSMESSAGEUA TAGLIST TARGET TARGETCLOSE TARGETF TARGETFG TARGETFW TARGETG
+TARGETW TDATA1 TDATA2 TDATA3 TDATA4 TELE TEVENTS TIMER TIMERD TIMERF TIMERFMS
+TIMERMS TITLE TRIGGER TRY TRYP TRYSRC TRYSRV TYPE UNDERWEAR UNEQUIP UPDATE
+UPDATEX USE USEITEM VALUE WAKE WEIGHT Z
+FINDCONT FINDID FINDLAYER FINDTYPE MEMORYFIND MEMORYFINDTYPE
+ALCHEMY ANATOMY ANIMALLORE ARCHERY ARMSLORE BEGGING BLACKSMITHING BOWCRAFT
+BUSHIDO CAMPING CARPENTRY CARTOGRAPHY CHIVALRY COOKING DETECTINGHIDDEN
+ENTICEMENT EVALUATINGINTEL FENCING FISHING FOCUS FORENSICS HEALING HERDING
+HIDING IMBUING INSCRIPTION ITEMID LOCKPICKING LUMBERJACKING MACEFIGHTING
+MAGERY MAGICRESISTANCE MEDITATION MINING MUSICIANSHIP MYSTICISM NECROMANCY
+NINJITSU PARRYING PEACEMAKING POISONING PROVOCATION REMOVETRAP SNOOPING
+SPELLWEAVING SPIRITSPEAK STEALING STEALTH SWORDSMANSHIP TACTICS TAILORING
+TAMING TASTEID THROWING TINKERING TRACKING VETERINARY WRESTLING
+`
+
+var (
+	scriptCommands = make(map[string]bool)
+
+	// flowKeywords are the statements of the block structure.
+	flowKeywords = map[string]bool{
+		"BEGIN": true, "BREAK": true, "CONTINUE": true, "ELIF": true, "ELSE": true,
+		"ELSEIF": true, "END": true, "ENDDO": true, "ENDFOR": true, "ENDIF": true,
+		"ENDWHILE": true, "RETURN": true,
+	}
+
+	commandTokenPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+	refObjectPattern    = regexp.MustCompile(`^(?i:REF\d+|UID|[0-9][0-9a-f]*)$`)
+)
+
+func init() {
+	for _, name := range strings.Fields(scriptCommandNames) {
+		scriptCommands[name] = true
+	}
+	for _, names := range []map[string]bool{numericProperties, readOnlyProperties, variableNamespaces, scriptObjects, flowKeywords} {
+		for name := range names {
+			scriptCommands[name] = true
+		}
+	}
+	for name := range blockStartToEnd {
+		scriptCommands[name] = true
+	}
+	for _, d := range deprecations {
+		if !d.trigger {
+			scriptCommands[d.name] = true
+		}
+	}
+}
+
+// commandUse is a statement whose first token is not a known command. It is
+// only reported once every file is read, since it may call a FUNCTION.
+type commandUse struct {
+	file string
+	line int
+	name string
+}
+
+// recordCommandUse collects the first token of a statement in a trigger or
+// function body when it is not a built-in verb, property, object or
+// configured custom verb. Lines that start with an expression are skipped.
+func (idx *lintIndex) recordCommandUse(line, file string, lineNum int) {
+	name := strings.ToUpper(commandTokenPattern.FindString(line))
+	if name == "" {
+		return
+	}
+	if isScriptVerb(name) || intrinsicNamespaces[name] || refObjectPattern.MatchString(name) || hasReferencePrefix(name) {
+		return
+	}
+	idx.commandUses = append(idx.commandUses, commandUse{file: file, line: lineNum, name: name})
+}
+
+// isScriptVerb reports whether name is a built-in command or one of the
+// configured custom verbs.
+func isScriptVerb(name string) bool {
+	if scriptCommands[strings.ToUpper(name)] {
+		return true
+	}
+	for _, verb := range config.Verbs {
+		if strings.EqualFold(verb, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findUnknownCommands reports the recorded statements whose first token is
+// not a FUNCTION of the scripts either.
+func findUnknownCommands(index *lintIndex) []lintIssue {
+	var issues []lintIssue
+	for _, use := range index.commandUses {
+		if _, ok := index.defs["FUNCTION "+use.name]; ok {
+			continue
+		}
+		msg := fmt.Sprintf("UNKNOWN: '%s' is not a known command, property or FUNCTION.", use.name)
+		if suggestion := closestIntrinsic(use.name, scriptCommands); suggestion != "" {
+			msg += fmt.Sprintf(" Did you mean '%s'?", suggestion)
+		}
+		issues = appendWarning(issues, use.file, use.line, "UNKNOWN", msg)
+	}
+	return issues
+}
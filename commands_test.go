@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestLintUnknownCommands(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_bell]",
+		"ID=0a0e",
+		"CUSTOMPROP=1",
+		"ON=@DClick",
+		"  sysmesage Ding!",
+		"  SRC.SYSMESSAGE Ding!",
+		"  helper",
+		"  MYVERB 1",
+		"  COLOR=0481",
+		"  REF1=<SRC.UID>",
+		"  <SRC.UID>.REMOVE",
+		"  CALL helper",
+		"  SRC.AFK",
+		"[FUNCTION helper]",
+		"  SERV.LOG done",
+		"  frobnicate",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "commands.scp", content), "unknown commands without the unknown-commands rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"unknown-commands"} })
+		errs := lintFromContent(t, "commands.scp", content)
+		assertHasMessage(t, errs, "UNKNOWN: 'SYSMESAGE' is not a known command, property or FUNCTION. Did you mean 'SYSMESSAGE'?")
+		assertHasMessage(t, errs, "UNKNOWN: 'MYVERB' is not a known command, property or FUNCTION.")
+		assertHasMessage(t, errs, "UNKNOWN: 'FROBNICATE' is not a known command, property or FUNCTION.")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 unknown command warnings, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredVerbs", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"unknown-commands"}
+			cfg.Verbs = []string{"myverb", "FROBNICATE"}
+		})
+
+		errs := lintFromContent(t, "commands.scp", content)
+		if len(errs) != 1 {
+			t.Fatalf("expected configured verbs to be accepted, got %+v", errs)
+		}
+	})
+	t.Run("StockVerbs", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"unknown-commands"} })
+		stock := joinLines(
+			"[CHARDEF c_guard]",
+			"ON=@Create",
+			"  NPCFLAGS=0",
+			"  MAXFOOD=10",
+			"  HITPOINTS=100",
+			"  MAXWEIGHT=400",
+			"  LUCK=5",
+			"  RESFIRE=10",
+			"  RESCOLD=10",
+			"  RESPOISON=10",
+			"  RESENERGY=10",
+			"  RESPHYSICAL=10",
+			"ON=@SpellEffect",
+			"  ACTARG1=1",
+			"  ACTARG2=2",
+			"  ACTP=<SRC.P>",
+			"  ACTPRV=0",
+			"  CAST 4",
+			"  REVEAL",
+			"  HUNGRY",
+			"  CONTROL",
+			"  ARROWQUEST 1",
+			"  STONE 1",
+			"  CALL f_done",
+			"[FUNCTION f_done]",
+			"  SRC.AFK",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "stock.scp", stock), "stock-pack verbs and properties")
+	})
+}
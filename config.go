@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const defaultConfigName = ".sphere-lint.json"
+
+type lintConfig struct {
+	SpeechPrefix     string                `json:"speechPrefix"`
+	Maps             map[int]mapSize       `json:"maps"`
+	TargetVersion    string                `json:"targetVersion"`
+	Enable           []string              `json:"enable"`
+	SharedTriggers   []string              `json:"sharedTriggers"`
+	DefnamePrefixes  map[string]string     `json:"defnamePrefixes"`
+	PropertyRanges   map[string]valueRange `json:"propertyRanges"`
+	IDRanges         map[string]valueRange `json:"idRanges"`
+	StatLimits       map[string]int        `json:"statLimits"`
+	Encoding         string                `json:"encoding"`
+	Intrinsics       []string              `json:"intrinsics"`
+	Verbs            []string              `json:"verbs"`
+	GMScriptDirs     []string              `json:"gmScriptDirs"`
+	MaxCallDepth     int                   `json:"maxCallDepth"`
+	MaxSkills        int                   `json:"maxSkills"`
+	MaxMessageLength int                   `json:"maxMessageLength"`
+}
+
+// valueRange is an inclusive range of allowed values.
+type valueRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// optionalRules are heuristic checks that only run when listed in the
+// config's enable list.
+var optionalRules = map[string]string{
+	"dangerous-commands":  "privileged account, character and server commands used outside the configured gmScriptDirs",
+	"defname-prefix":      "definition names must start with the prefix configured for their section type",
+	"duplicate-sections":  "sections whose body is identical to another section's, header aside",
+	"implausible-stats":   "CHARDEF STR/DEX/INT/HITS values of 0 or above the configured statLimits",
+	"missing-name":        "ITEMDEF/CHARDEF sections with neither NAME= nor an ID= naming another defname",
+	"mixed-indent":        "indentation that mixes tabs and spaces within one section or trigger body",
+	"numeric-references":  "numeric item and character IDs in ITEM=, CONTAINER=, ID=, SPAWN members and dialog tilepics with no ITEMDEF/CHARDEF of that ID",
+	"reference-casing":    "prefixed references whose letter case differs from the definition header",
+	"tag-typos":           "TAG names read once that are a small edit away from a common TAG name",
+	"trailing-whitespace": "spaces or tabs at the end of a line",
+	"unknown-commands":    "statements whose first word is not a known command, property, object, FUNCTION or configured verb",
+	"utf8-bom":            "a UTF-8 byte order mark at the start of a file",
+}
+
+var (
+	config = defaultConfig()
+
+	speechPatternPrefix string
+	speechPattern       *regexp.Regexp
+)
+
+func defaultConfig() lintConfig {
+	return lintConfig{
+		SpeechPrefix:     "spk_",
+		TargetVersion:    "56d",
+		Encoding:         "utf-8",
+		MaxCallDepth:     10,
+		MaxSkills:        58,
+		MaxMessageLength: 128,
+		Maps: map[int]mapSize{
+			0: {Width: 6144, Height: 4096},
+			1: {Width: 6144, Height: 4096},
+			2: {Width: 2304, Height: 1600},
+			3: {Width: 2560, Height: 2048},
+			4: {Width: 1448, Height: 1448},
+			5: {Width: 1280, Height: 4096},
+		},
+		DefnamePrefixes: map[string]string{
+			"CHARDEF":    "c_",
+			"DIALOG":     "d_",
+			"EVENTS":     "e_",
+			"FUNCTION":   "f_",
+			"ITEMDEF":    "i_",
+			"MENU":       "m_",
+			"REGIONTYPE": "r_",
+			"SPAWN":      "spawn_",
+			"SPELL":      "s_",
+			"TYPEDEF":    "t_",
+		},
+		PropertyRanges: map[string]valueRange{
+			"FAME":  {Min: 0, Max: 10000},
+			"KARMA": {Min: -10000, Max: 10000},
+		},
+		IDRanges: map[string]valueRange{
+			"ANIM":  {Min: 0, Max: 0xFF},
+			"SOUND": {Min: 0, Max: 0x7FF},
+		},
+		StatLimits: map[string]int{
+			"DEX":  1000,
+			"HITS": 10000,
+			"INT":  1000,
+			"STR":  1000,
+		},
+	}
+}
+
+// loadConfig reads a JSON config file on top of the defaults. When path is
+// empty the default config file in the scripts root is used if it exists.
+func loadConfig(path string) (lintConfig, error) {
+	cfg := defaultConfig()
+	explicit := path != ""
+	if !explicit {
+		path = filepath.Join(scriptsRoot, defaultConfigName)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if !isKnownVersion(cfg.TargetVersion) {
+		return cfg, fmt.Errorf("%s: unknown targetVersion %q", path, cfg.TargetVersion)
+	}
+	cfg.Encoding = strings.ToLower(cfg.Encoding)
+	if !scriptEncodings[cfg.Encoding] {
+		return cfg, fmt.Errorf("%s: unknown encoding %q (use utf-8 or cp1252)", path, cfg.Encoding)
+	}
+	if cfg.MaxSkills <= 0 {
+		return cfg, fmt.Errorf("%s: maxSkills must be positive, got %d", path, cfg.MaxSkills)
+	}
+	if cfg.MaxMessageLength <= 0 {
+		return cfg, fmt.Errorf("%s: maxMessageLength must be positive, got %d", path, cfg.MaxMessageLength)
+	}
+	for defType, prefix := range cfg.DefnamePrefixes {
+		if upper := strings.ToUpper(defType); upper != defType {
+			delete(cfg.DefnamePrefixes, defType)
+			cfg.DefnamePrefixes[upper] = prefix
+		}
+	}
+	for key, r := range cfg.PropertyRanges {
+		if upper := strings.ToUpper(key); upper != key {
+			delete(cfg.PropertyRanges, key)
+			cfg.PropertyRanges[upper] = r
+		}
+	}
+	for i, dir := range cfg.GMScriptDirs {
+		cfg.GMScriptDirs[i] = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	}
+	for key, r := range cfg.IDRanges {
+		if upper := strings.ToUpper(key); upper != key {
+			delete(cfg.IDRanges, key)
+			cfg.IDRanges[upper] = r
+		}
+	}
+	for key, limit := range cfg.StatLimits {
+		if upper := strings.ToUpper(key); upper != key {
+			delete(cfg.StatLimits, key)
+			cfg.StatLimits[upper] = limit
+		}
+	}
+	for _, rule := range cfg.Enable {
+		if _, ok := optionalRules[rule]; !ok {
+			return cfg, fmt.Errorf("%s: unknown rule %q in enable", path, rule)
+		}
+	}
+	return cfg, nil
+}
+
+// speechReferencePattern returns the reference pattern for the configured
+// SPEECH prefix, or nil when speech prefix matching is disabled.
+func speechReferencePattern() *regexp.Regexp {
+	prefix := strings.TrimSpace(config.SpeechPrefix)
+	if prefix == "" {
+		return nil
+	}
+	if speechPattern == nil || speechPatternPrefix != prefix {
+		speechPattern = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(prefix) + `[a-z0-9_]+\b`)
+		speechPatternPrefix = prefix
+	}
+	return speechPattern
+}
+
+// ruleEnabled reports whether an optional rule is switched on in the config.
+func ruleEnabled(rule string) bool {
+	for _, enabled := range config.Enable {
+		if enabled == rule {
+			return true
+		}
+	}
+	return false
+}
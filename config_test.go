@@ -0,0 +1,112 @@
+package main
+
+import "testing"
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("MissingDefaultFile", func(t *testing.T) {
+		withTempScriptsDir(t)
+
+		cfg, err := loadConfig("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.SpeechPrefix != "spk_" {
+			t.Fatalf("expected default speech prefix, got %q", cfg.SpeechPrefix)
+		}
+	})
+
+	t.Run("DefaultFileInScriptsRoot", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		writeTempFile(t, dir, defaultConfigName, `{"speechPrefix": "talk_"}`)
+
+		cfg, err := loadConfig("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.SpeechPrefix != "talk_" {
+			t.Fatalf("expected configured speech prefix, got %q", cfg.SpeechPrefix)
+		}
+	})
+
+	t.Run("MapsMergeWithDefaults", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		path := writeTempFile(t, dir, "maps.json", `{"maps": {"0": {"width": 7168, "height": 4096}, "7": {"width": 1000, "height": 800}}}`)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Maps[0].Width != 7168 || cfg.Maps[7].Height != 800 {
+			t.Fatalf("expected configured maps, got %+v", cfg.Maps)
+		}
+		if cfg.Maps[2].Width != 2304 {
+			t.Fatalf("expected default map 2 to be kept, got %+v", cfg.Maps[2])
+		}
+	})
+
+	t.Run("MissingExplicitFile", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+
+		if _, err := loadConfig(dir + "/missing.json"); err == nil {
+			t.Fatalf("expected error for missing explicit config")
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		path := writeTempFile(t, dir, "bad.json", `{"speechPrefix": `)
+
+		if _, err := loadConfig(path); err == nil {
+			t.Fatalf("expected error for invalid config")
+		}
+	})
+
+	t.Run("DefnamePrefixesMergeWithDefaults", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		path := writeTempFile(t, dir, "prefixes.json", `{"defnamePrefixes": {"areadef": "a_", "ITEMDEF": "it_"}}`)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.DefnamePrefixes["AREADEF"] != "a_" || cfg.DefnamePrefixes["ITEMDEF"] != "it_" || cfg.DefnamePrefixes["CHARDEF"] != "c_" {
+			t.Fatalf("expected merged prefixes, got %v", cfg.DefnamePrefixes)
+		}
+	})
+
+	t.Run("EnableRules", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		path := writeTempFile(t, dir, "rules.json", `{"enable": ["tag-typos"]}`)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Enable) != 1 || cfg.Enable[0] != "tag-typos" {
+			t.Fatalf("expected enabled rules, got %v", cfg.Enable)
+		}
+
+		path = writeTempFile(t, dir, "bad_rules.json", `{"enable": ["no-such-rule"]}`)
+		if _, err := loadConfig(path); err == nil {
+			t.Fatalf("expected error for unknown rule")
+		}
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		path := writeTempFile(t, dir, "encoding.json", `{"encoding": "CP1252"}`)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Encoding != "cp1252" {
+			t.Fatalf("expected cp1252 encoding, got %q", cfg.Encoding)
+		}
+
+		path = writeTempFile(t, dir, "bad_encoding.json", `{"encoding": "latin-9"}`)
+		if _, err := loadConfig(path); err == nil {
+			t.Fatalf("expected error for unknown encoding")
+		}
+	})
+}
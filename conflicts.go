@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packConflict is a name two packs both define.
+type packConflict struct {
+	kind   string
+	name   string
+	first  definitionLocation
+	second definitionLocation
+}
+
+// sharedNames returns the keys of a and b that are in both, sorted.
+func sharedNames(a, b map[string]definitionLocation) []string {
+	var names []string
+	for name := range a {
+		if _, ok := b[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findPackConflicts returns the definitions, numeric IDs and DEFNAMEs that
+// both indexes define. Definitions with a hex ID ([ITEMDEF 0f61]) are
+// numeric IDs: loading both packs overrides one of the base objects.
+func findPackConflicts(a, b *lintIndex) []packConflict {
+	var conflicts []packConflict
+	for _, key := range sharedNames(a.defs, b.defs) {
+		kind := "definition"
+		if _, id, _ := strings.Cut(key, " "); isHexDigits(id) {
+			kind = "numeric ID"
+		}
+		conflicts = append(conflicts, packConflict{kind: kind, name: key, first: a.defs[key], second: b.defs[key]})
+	}
+	for _, name := range sharedNames(a.defnames, b.defnames) {
+		conflicts = append(conflicts, packConflict{kind: "DEFNAME", name: name, first: a.defnames[name], second: b.defnames[name]})
+	}
+	return conflicts
+}
+
+// writePackConflicts prints one line per conflict with its location in both
+// packs.
+func writePackConflicts(w io.Writer, conflicts []packConflict, dirA, dirB string) {
+	where := func(dir string, loc definitionLocation) string {
+		return fmt.Sprintf("%s:%d", path.Join(filepath.ToSlash(dir), loc.file), loc.line)
+	}
+	for _, c := range conflicts {
+		fmt.Fprintf(w, "CONFLICT %s %s: %s and %s\n", c.kind, c.name, where(dirA, c.first), where(dirB, c.second))
+	}
+}
+
+// runConflicts implements "sphere-lint conflicts PACK_A PACK_B": it indexes
+// both packs on their own and lists what they both define, to judge whether
+// an addon can be dropped into a shard. It exits 1 if there is a conflict.
+func runConflicts(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint conflicts", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in PACK_A)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: sphere-lint conflicts [-config file] PACK_A PACK_B")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	dirA, dirB := flags.Arg(0), flags.Arg(1)
+	for _, dir := range []string{dirA, dirB} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "conflicts: %s is not a directory\n", dir)
+			return 2
+		}
+	}
+
+	prevRoot := scriptsRoot
+	scriptsRoot = dirA
+	err := setupConfig(*configPath, "")
+	scriptsRoot = prevRoot
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	indexA, _ := scanTree(dirA)
+	indexB, _ := scanTree(dirB)
+	conflicts := findPackConflicts(indexA, indexB)
+	writePackConflicts(os.Stdout, conflicts, dirA, dirB)
+
+	fmt.Println("---------------------------------------------")
+	fmt.Printf("Conflicts: %d\n", len(conflicts))
+	if len(conflicts) > 0 {
+		return 1
+	}
+	return 0
+}
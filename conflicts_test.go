@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindPackConflicts(t *testing.T) {
+	shard, addon := t.TempDir(), t.TempDir()
+	writeTempFile(t, shard, "items.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"[ITEMDEF 0f62]",
+		"DEFNAME=i_spear_base",
+		"[DEFNAME colors]",
+		"color_fire 0481",
+		"[EOF]",
+	))
+	writeTempFile(t, addon, "addon.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"[ITEMDEF i_sword_ice]",
+		"ID=0f61",
+		"[ITEMDEF 0f62]",
+		"NAME=spear",
+		"[DEFNAME addon_colors]",
+		"color_fire 0482",
+		"color_ice 0480",
+		"[EOF]",
+	))
+
+	indexA, _ := scanTree(shard)
+	indexB, _ := scanTree(addon)
+	var out bytes.Buffer
+	writePackConflicts(&out, findPackConflicts(indexA, indexB), "shard", "addon/")
+	want := joinLines(
+		"CONFLICT numeric ID ITEMDEF 0F62: shard/items.scp:3 and addon/addon.scp:5",
+		"CONFLICT definition ITEMDEF I_SWORD_FIRE: shard/items.scp:1 and addon/addon.scp:1",
+		"CONFLICT DEFNAME COLOR_FIRE: shard/items.scp:6 and addon/addon.scp:8",
+	)
+	if out.String() != want {
+		t.Fatalf("unexpected conflicts:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunConflicts(t *testing.T) {
+	shard, addon := t.TempDir(), t.TempDir()
+	writeTempFile(t, shard, "a.scp", joinLines("[FUNCTION f_test]", "SERV.LOG a", "[EOF]"))
+	writeTempFile(t, addon, "b.scp", joinLines("[FUNCTION f_addon]", "SERV.LOG b", "[EOF]"))
+	if code := runConflicts([]string{shard, addon}); code != 0 {
+		t.Fatalf("expected packs without conflicts to exit 0, got %d", code)
+	}
+	writeTempFile(t, addon, "c.scp", joinLines("[FUNCTION f_test]", "SERV.LOG c", "[EOF]"))
+	if code := runConflicts([]string{shard, addon}); code != 1 {
+		t.Fatalf("expected a conflict to exit 1, got %d", code)
+	}
+	if code := runConflicts([]string{shard, shard + "/missing"}); code != 2 {
+		t.Fatalf("expected a missing pack to exit 2, got %d", code)
+	}
+}
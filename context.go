@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// triggerContexts lists, for triggers whose context is well known, the
+	// context objects and arguments the server fills in. References to any
+	// other context object resolve to nothing and log a console error at
+	// runtime; unsupplied arguments silently read as 0 or empty.
+	triggerContexts = map[string][]string{
+		"CREATE":      {},
+		"TIMER":       {},
+		"CLICK":       {"SRC"},
+		"DCLICK":      {"SRC", "ACT"},
+		"STEP":        {"SRC"},
+		"EQUIP":       {"SRC"},
+		"UNEQUIP":     {"SRC"},
+		"LOGIN":       {"SRC"},
+		"LOGOUT":      {"SRC"},
+		"DESTROY":     {"SRC"},
+		"ITEMCLICK":   {"SRC", "ACT"},
+		"ITEMDCLICK":  {"SRC", "ACT"},
+		"ITEMSTEP":    {"SRC", "ACT"},
+		"DROPON_ITEM": {"SRC", "ACT"},
+		"DROPON_SELF": {"SRC", "ACT"},
+		"ITEMEQUIP":   {"SRC", "ACT", "ARGO"},
+		"ITEMUNEQUIP": {"SRC", "ACT", "ARGO"},
+		"HIT":         {"SRC", "ACT", "ARGO", "ARGN1", "ARGN2", "ARGN3"},
+		"GETHIT":      {"SRC", "ACT", "ARGO", "ARGN1", "ARGN2", "ARGN3"},
+		"SPELLCAST":   {"SRC", "ARGO", "ARGN1", "ARGN2", "ARGN3"},
+		"SPELLEFFECT": {"SRC", "ARGO", "ARGN1", "ARGN2", "ARGN3"},
+		"HEAR":        {"SRC", "ARGS", "ARGN1"},
+		"SEEHEAR":     {"SRC", "ARGS", "ARGN1"},
+	}
+
+	// contextArguments are the trigger arguments checked against
+	// triggerContexts, with what they read as when not supplied.
+	contextArguments = map[string]string{
+		"ARGN1": "0",
+		"ARGN2": "0",
+		"ARGN3": "0",
+		"ARGS":  "an empty string",
+	}
+
+	contextObjectPattern   = regexp.MustCompile(`(?i)(?:^|[^A-Za-z0-9_.])(ACT|ARGO|SRC)(?:\.[A-Za-z_]|>)`)
+	contextArgumentPattern = regexp.MustCompile(`(?i)<[dh]?(ARGN[1-3]|ARGS)>`)
+)
+
+// triggerContext tracks which context objects and arguments are usable in
+// the body of the current trigger.
+type triggerContext struct {
+	trigger  string
+	valid    map[string]bool
+	reported map[string]bool
+}
+
+// beginTriggerContext returns the context of an ON=@Trigger line, or nil when
+// the trigger is not in triggerContexts.
+func beginTriggerContext(line string) *triggerContext {
+	match := triggerNamePattern.FindStringSubmatch(line)
+	if len(match) != 2 {
+		return nil
+	}
+	objects, ok := triggerContexts[strings.ToUpper(match[1])]
+	if !ok {
+		return nil
+	}
+	ctx := &triggerContext{trigger: "@" + match[1], valid: make(map[string]bool), reported: make(map[string]bool)}
+	for _, obj := range objects {
+		ctx.valid[obj] = true
+	}
+	return ctx
+}
+
+// check warns once per trigger about each context object or argument the
+// line uses that is unset in the trigger. Assigning it (ACT=<UID>, ARGN1=5)
+// makes it usable for the rest of the body.
+func (c *triggerContext) check(line, file string, lineNum int) []lintIssue {
+	if c == nil {
+		return nil
+	}
+	if key, _, ok := splitAssignment(line); ok && (key == "ACT" || key == "ARGO" || contextArguments[key] != "") {
+		c.valid[key] = true
+		return nil
+	}
+	var issues []lintIssue
+	for _, match := range contextObjectPattern.FindAllStringSubmatch(line, -1) {
+		obj := strings.ToUpper(match[1])
+		if c.valid[obj] || c.reported[obj] {
+			continue
+		}
+		c.reported[obj] = true
+		issues = appendWarning(issues, file, lineNum, "CONTEXT", fmt.Sprintf("CONTEXT: %s is not set in %s; references to it resolve to nothing.", obj, c.trigger))
+	}
+	for _, match := range contextArgumentPattern.FindAllStringSubmatch(line, -1) {
+		arg := strings.ToUpper(match[1])
+		if c.valid[arg] || c.reported[arg] {
+			continue
+		}
+		c.reported[arg] = true
+		issues = appendWarning(issues, file, lineNum, "CONTEXT", fmt.Sprintf("CONTEXT: %s is not supplied by %s; it always reads as %s.", arg, c.trigger, contextArguments[arg]))
+	}
+	return issues
+}
@@ -0,0 +1,89 @@
+package main
+
+import "testing"
+
+func TestLintTriggerContextObjects(t *testing.T) {
+	t.Run("UnsetObjects", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@Create",
+			"ACT.REMOVE",
+			"ACT.NAME=again",
+			"SRC.SYSMESSAGE <ARGO.NAME>",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "context.scp", content)
+		assertHasMessage(t, errs, "CONTEXT: ACT is not set in @Create; references to it resolve to nothing.")
+		assertHasMessage(t, errs, "CONTEXT: SRC is not set in @Create")
+		assertHasMessage(t, errs, "CONTEXT: ARGO is not set in @Create")
+		count := 0
+		for _, e := range errs {
+			if e.kind == "CONTEXT" {
+				count++
+				if e.severity != severityWarning {
+					t.Fatalf("expected CONTEXT issues to be warnings, got %+v", e)
+				}
+			}
+		}
+		if count != 3 {
+			t.Fatalf("expected 3 CONTEXT warnings, got %d: %+v", count, errs)
+		}
+	})
+
+	t.Run("ValidObjects", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_test]",
+			"ON=@ItemEquip",
+			"SRC.SYSMESSAGE <ACT.NAME> <ARGO.NAME>",
+			"ON=@Timer",
+			"ACT=<UID>",
+			"ACT.SAY Act now",
+			"LOCAL.SRC.X=1",
+			"ON=@Hit",
+			"ACT.NAME=x",
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"ACT.REMOVE",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "context_ok.scp", content), "valid context objects")
+	})
+
+	t.Run("UnsuppliedArguments", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@Create",
+			"MORE1=<ARGN1>",
+			"NAME=<ARGS>",
+			"ON=@DClick",
+			"ARGN1=1",
+			"SRC.SYSMESSAGE <dARGN1>",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "context_args.scp", content)
+		assertHasMessage(t, errs, "CONTEXT: ARGN1 is not supplied by @Create; it always reads as 0.")
+		assertHasMessage(t, errs, "CONTEXT: ARGS is not supplied by @Create; it always reads as an empty string.")
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 CONTEXT warnings, got %+v", errs)
+		}
+	})
+
+	t.Run("SuppliedArguments", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_test]",
+			"ON=@GetHit",
+			"ARGN1=<EVAL <ARGN1>/2>",
+			"ON=@Hear",
+			"IF (STRMATCH(*hello*,<ARGS>))",
+			"ENDIF",
+			"ON=@UnknownTrigger",
+			"SRC.SYSMESSAGE <ARGN2>",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "context_args_ok.scp", content), "supplied arguments")
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// minCopyLines is the smallest body the duplicate-sections rule compares;
+// shorter sections are identical too often to be worth reporting.
+const minCopyLines = 3
+
+// sectionBodies remembers the first section seen with each body hash.
+type sectionBodies struct {
+	first map[[sha256.Size]byte]*sectionBody
+}
+
+func newSectionBodies() *sectionBodies {
+	return &sectionBodies{first: make(map[[sha256.Size]byte]*sectionBody)}
+}
+
+// sectionBody hashes the lines of one section below its header, with
+// comments and surrounding whitespace removed.
+type sectionBody struct {
+	label string
+	file  string
+	line  int
+	lines int
+	hash  hash.Hash
+	seen  *sectionBodies
+}
+
+func (b *sectionBodies) begin(label, file string, lineNum int) *sectionBody {
+	return &sectionBody{label: label, file: file, line: lineNum, hash: sha256.New(), seen: b}
+}
+
+func (s *sectionBody) add(line string) {
+	if s == nil {
+		return
+	}
+	s.hash.Write([]byte(line))
+	s.hash.Write([]byte{'\n'})
+	s.lines++
+}
+
+// finish reports the section when an earlier one had the same body.
+func (s *sectionBody) finish() []lintIssue {
+	if s == nil || s.lines < minCopyLines {
+		return nil
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], s.hash.Sum(nil))
+	first, ok := s.seen.first[sum]
+	if !ok {
+		s.seen.first[sum] = s
+		return nil
+	}
+	return appendWarning(nil, s.file, s.line, "DUPLICATE", fmt.Sprintf("DUPLICATE: %s has the same %d-line body as %s (%s:%d).", s.label, s.lines, first.label, first.file, first.line))
+}
+
+// checkFileCopy reports a script whose content, without comments, blank
+// lines and indentation, matches a file read earlier. Merged packs often
+// carry the same script twice under different names.
+func (idx *lintIndex) checkFileCopy(content, file string) []lintIssue {
+	sum := sha256.New()
+	lines := 0
+	for _, line := range strings.Split(content, "\n") {
+		if cleaned := cleanLine(line); cleaned != "" {
+			sum.Write([]byte(cleaned))
+			sum.Write([]byte{'\n'})
+			lines++
+		}
+	}
+	if lines < minCopyLines {
+		return nil
+	}
+	var key [sha256.Size]byte
+	copy(key[:], sum.Sum(nil))
+	first, ok := idx.files[key]
+	if !ok {
+		idx.files[key] = file
+		return nil
+	}
+	return appendWarning(nil, file, 1, "DUPLICATE", fmt.Sprintf("DUPLICATE: %s has the same content as %s; remove one copy.", file, first))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintDuplicateSections(t *testing.T) {
+	body := []string{
+		"ID=0e21",
+		"NAME=bandage",
+		"ON=@DClick // heal",
+		"  SRC.HITS += 5",
+		"  REMOVE",
+	}
+	first := joinLines(append(append([]string{"[ITEMDEF i_bandage]"}, body...), "[EOF]")...)
+	second := joinLines(append(append(append([]string{"[ITEMDEF i_bandage_clean]"}, body...), "[ITEMDEF i_other]", "ID=0e22"), "[EOF]")...)
+
+	lintBoth := func(t *testing.T) []lintIssue {
+		dir := withTempScriptsDir(t)
+		index := newLintIndex()
+		errs := lintScriptFile(writeTempFile(t, dir, "a.scp", first), index)
+		errs = append(errs, lintScriptFile(writeTempFile(t, dir, "b.scp", second), index)...)
+		return append(errs, lintIndexIssues(index)...)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintBoth(t), "copied sections without the duplicate-sections rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"duplicate-sections"}
+		})
+		errs := lintBoth(t)
+		assertHasMessage(t, errs, "DUPLICATE: ITEMDEF I_BANDAGE_CLEAN has the same 5-line body as ITEMDEF I_BANDAGE (a.scp:1).")
+		if len(errs) != 1 || errs[0].file != "b.scp" || errs[0].line != 1 {
+			t.Fatalf("expected one warning at b.scp:1, got %+v", errs)
+		}
+	})
+}
+
+func TestLintFileCopies(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, dir, "heal.scp", joinLines(
+		"// healing",
+		"[FUNCTION f_heal]",
+		"IF <SRC.ISPLAYER>",
+		"\tSRC.HITS += 5",
+		"ENDIF",
+		"[EOF]",
+	)), index)
+	errs = append(errs, lintScriptFile(writeTempFile(t, dir, "heal_old.scp", joinLines(
+		"[FUNCTION f_heal] // merged from the old pack",
+		"IF <SRC.ISPLAYER>",
+		"    SRC.HITS += 5",
+		"ENDIF",
+		"",
+		"[EOF]",
+	)), index)...)
+	errs = append(errs, lintScriptFile(writeTempFile(t, dir, "tiny.scp", joinLines("[EOF]")), index)...)
+	errs = append(errs, lintScriptFile(writeTempFile(t, dir, "tiny_copy.scp", joinLines("[EOF]")), index)...)
+
+	assertHasMessage(t, errs, "DUPLICATE: heal_old.scp has the same content as heal.scp; remove one copy.")
+	copies := 0
+	for _, e := range errs {
+		if strings.Contains(e.msg, "same content") {
+			copies++
+			if e.file != "heal_old.scp" || e.line != 1 || e.severity != severityWarning {
+				t.Fatalf("expected the copy warning at heal_old.scp:1, got %+v", e)
+			}
+		}
+	}
+	if copies != 1 {
+		t.Fatalf("expected only heal_old.scp to be reported, got %+v", errs)
+	}
+}
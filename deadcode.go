@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// deadCodeTypes are the definitions whose uses the reference collector
+// sees; other sections, such as SKILL or AREADEF, are used by the server
+// itself.
+var deadCodeTypes = map[string]bool{
+	"CHARDEF": true, "DIALOG": true, "EVENTS": true, "FUNCTION": true,
+	"ITEMDEF": true, "MENU": true, "SPAWN": true, "TEMPLATE": true,
+	"TYPEDEF": true,
+}
+
+// deadCode is one finding of "sphere-lint deadcode" with an estimate of
+// the lines removing it would save.
+type deadCode struct {
+	file  string
+	line  int
+	lines int
+	what  string
+}
+
+// usedNames returns the IDs referenced from outside their own section,
+// including the FUNCTIONs called by name.
+func usedNames(index *lintIndex) map[string]map[string]bool {
+	users := make(map[string]map[string]bool)
+	use := func(id, from string) {
+		if users[id] == nil {
+			users[id] = make(map[string]bool)
+		}
+		users[id][from] = true
+	}
+	for _, ref := range index.references {
+		use(ref.id, ref.from)
+	}
+	for caller, edges := range index.calls.edges {
+		for _, edge := range edges {
+			use(edge.target, caller)
+		}
+	}
+	return users
+}
+
+// usedElsewhere reports whether one of names is used by a section other
+// than the one labelled label (FUNCTION F_X, or its triggers).
+func usedElsewhere(users map[string]map[string]bool, names []string, label string) bool {
+	caller := strings.TrimPrefix(label, "FUNCTION ")
+	for _, name := range names {
+		for from := range users[strings.ToUpper(name)] {
+			if from != label && from != caller && !strings.HasPrefix(from, label+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findDeadCode returns the definitions nothing references, the FUNCTIONs
+// nothing calls and the unreachable statements among issues. Definitions
+// with hex IDs and IDs without a reference prefix are left out: they are
+// used by number or by names the collector cannot see.
+func findDeadCode(index *lintIndex, issues []lintIssue) []deadCode {
+	users := usedNames(index)
+	var found []deadCode
+	for _, s := range index.symbols {
+		if !deadCodeTypes[s.Type] || isHexDigits(s.ID) || (s.Type != "FUNCTION" && !hasReferencePrefix(s.ID)) {
+			continue
+		}
+		label := s.Type + " " + strings.ToUpper(s.ID)
+		if usedElsewhere(users, append([]string{s.ID}, s.Defnames...), label) {
+			continue
+		}
+		lines := s.end - s.Line + 1
+		if lines < 1 {
+			lines = 1
+		}
+		what := fmt.Sprintf("%s %s is never referenced", s.Type, s.ID)
+		if s.Type == "FUNCTION" {
+			what = fmt.Sprintf("FUNCTION %s is never called", s.ID)
+		}
+		found = append(found, deadCode{file: s.File, line: s.Line, lines: lines, what: what})
+	}
+	for _, issue := range issues {
+		if strings.Contains(issue.msg, "unreachable") {
+			found = append(found, deadCode{file: issue.file, line: issue.line, lines: 1, what: strings.TrimSuffix(strings.TrimPrefix(issue.msg, issue.kind+": "), ".")})
+		}
+	}
+	return found
+}
+
+// writeDeadCode prints the findings grouped by file, the files with the
+// most removable lines first and the largest findings first within a file.
+// It returns the estimated number of removable lines.
+func writeDeadCode(w io.Writer, found []deadCode) int {
+	byFile := make(map[string][]deadCode)
+	totals := make(map[string]int)
+	for _, d := range found {
+		byFile[d.file] = append(byFile[d.file], d)
+		totals[d.file] += d.lines
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if totals[files[i]] != totals[files[j]] {
+			return totals[files[i]] > totals[files[j]]
+		}
+		return files[i] < files[j]
+	})
+
+	total := 0
+	for _, file := range files {
+		entries := byFile[file]
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].lines != entries[j].lines {
+				return entries[i].lines > entries[j].lines
+			}
+			return entries[i].line < entries[j].line
+		})
+		fmt.Fprintf(w, "%s: ~%d removable line(s)\n", file, totals[file])
+		for _, d := range entries {
+			fmt.Fprintf(w, "  %s:%d: %s (%d line(s))\n", d.file, d.line, d.what, d.lines)
+		}
+		total += totals[file]
+	}
+	fmt.Fprintf(w, "Total: ~%d removable line(s) in %d file(s)\n", total, len(files))
+	return total
+}
+
+// runDeadCode implements "sphere-lint deadcode": it reports unreferenced
+// definitions, uncalled FUNCTIONs and unreachable statements with the lines
+// removing them would save, and exits 1 if there are any. Commands typed in
+// game (.f_name) and server settings that name EVENTS are not seen, so the
+// report lists candidates to review, not code to delete blindly.
+func runDeadCode(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint deadcode", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	var issues []lintIssue
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		issues = append(issues, lintScriptFile(path, index)...)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+	issues = append(issues, lintIndexIssues(index)...)
+
+	if writeDeadCode(os.Stdout, findDeadCode(index, issues)) > 0 {
+		return 1
+	}
+	if len(walkIssues) > 0 {
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFindDeadCode(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, dir, "items.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"EVENTS=e_burn",
+		"[ITEMDEF i_sword_old]",
+		"DEFNAME=i_sword_older",
+		"ID=0f61",
+		"ON=@Create",
+		"NEWITEM i_sword_old",
+		"[ITEMDEF 0f62]",
+		"NAME=base",
+		"[EVENTS e_burn]",
+		"ON=@Timer",
+		"f_burn",
+		"[EOF]",
+	)), index)
+	errs = append(errs, lintScriptFile(writeTempFile(t, dir, "funcs.scp", joinLines(
+		"[FUNCTION f_burn]",
+		"SRC.NEWITEM i_sword_fire",
+		"RETURN 1",
+		"SERV.LOG never",
+		"[FUNCTION f_recursive]",
+		"IF <ARGN1>",
+		"  f_recursive <EVAL <ARGN1>-1>",
+		"ENDIF",
+		"[FUNCTION cleanup]",
+		"SERV.LOG unused",
+		"[EOF]",
+	)), index)...)
+	errs = append(errs, lintIndexIssues(index)...)
+
+	var out bytes.Buffer
+	total := writeDeadCode(&out, findDeadCode(index, errs))
+	want := joinLines(
+		"funcs.scp: ~7 removable line(s)",
+		"  funcs.scp:5: FUNCTION f_recursive is never called (4 line(s))",
+		"  funcs.scp:9: FUNCTION cleanup is never called (2 line(s))",
+		"  funcs.scp:4: unreachable statement after RETURN at line 3 (1 line(s))",
+		"items.scp: ~5 removable line(s)",
+		"  items.scp:4: ITEMDEF i_sword_old is never referenced (5 line(s))",
+		"Total: ~12 removable line(s) in 2 file(s)",
+	)
+	if got := out.String(); strings.TrimSpace(got) != strings.TrimSpace(want) || total != 12 {
+		t.Fatalf("unexpected report (%d lines):\n%s\nwant:\n%s", total, got, want)
+	}
+}
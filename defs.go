@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// symbolMatches reports whether the ID or a DEFNAME of s matches the glob
+// pattern, ignoring case. Names with a reference prefix also match without
+// it, so "dragon*" finds c_dragon_fire.
+func symbolMatches(s *symbolInfo, pattern string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, name := range append([]string{s.ID}, s.Defnames...) {
+		name = strings.ToLower(name)
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if _, rest, found := strings.Cut(name, "_"); found && hasReferencePrefix(name) {
+			if ok, _ := path.Match(pattern, rest); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findSymbols returns the definitions of defType (any type when empty)
+// matching pattern (all when empty), sorted by type and ID.
+func findSymbols(index *lintIndex, defType, pattern string) []*symbolInfo {
+	var found []*symbolInfo
+	for _, s := range sortedSymbols(index) {
+		if defType != "" && s.Type != defType {
+			continue
+		}
+		if pattern != "" && !symbolMatches(s, pattern) {
+			continue
+		}
+		found = append(found, s)
+	}
+	return found
+}
+
+// writeDefinitions prints one file:line per definition with its type, ID and
+// DEFNAMEs.
+func writeDefinitions(w io.Writer, symbols []*symbolInfo) {
+	for _, s := range symbols {
+		fmt.Fprintf(w, "%s:%d: %s %s", s.File, s.Line, s.Type, s.ID)
+		if len(s.Defnames) > 0 {
+			fmt.Fprintf(w, " (DEFNAME %s)", strings.Join(s.Defnames, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// runDefs implements "sphere-lint defs": it lists the definitions of a type
+// whose ID or DEFNAME matches a glob pattern. Like grep, it exits 1 when
+// nothing matches.
+func runDefs(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint defs", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	defType := flags.String("type", "", "only list definitions of this type, such as CHARDEF")
+	pattern := flags.String("match", "", "only list definitions whose ID or DEFNAME matches this glob, such as 'dragon*'")
+	flags.Parse(args)
+
+	if _, err := path.Match(*pattern, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "defs: invalid -match %q: %v\n", *pattern, err)
+		return 2
+	}
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	symbols := findSymbols(index, strings.ToUpper(*defType), *pattern)
+	writeDefinitions(os.Stdout, symbols)
+	if len(walkIssues) > 0 {
+		return 2
+	}
+	if len(symbols) == 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindSymbols(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	lintScriptFile(writeTempFile(t, dir, "npcs.scp", joinLines(
+		"[CHARDEF c_dragon_fire]",
+		"DEFNAME=c_wyrm_red",
+		"ID=c_dragon",
+		"NAME=Fire Dragon",
+		"[CHARDEF c_orc]",
+		"ID=c_man",
+		"NAME=Orc",
+		"[ITEMDEF i_dragon_scale]",
+		"ID=026b4",
+		"[EOF]",
+	)), index)
+
+	var out bytes.Buffer
+	writeDefinitions(&out, findSymbols(index, "CHARDEF", "dragon*"))
+	if want := "npcs.scp:1: CHARDEF c_dragon_fire (DEFNAME c_wyrm_red)\n"; out.String() != want {
+		t.Fatalf("unexpected listing:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if found := findSymbols(index, "", "*dragon*"); len(found) != 2 {
+		t.Fatalf("expected the dragon of every type, got %+v", found)
+	}
+	if found := findSymbols(index, "", "WYRM_*"); len(found) != 1 || found[0].ID != "c_dragon_fire" {
+		t.Fatalf("expected DEFNAMEs to match, got %+v", found)
+	}
+	if found := findSymbols(index, "CHARDEF", ""); len(found) != 2 {
+		t.Fatalf("expected every CHARDEF without -match, got %+v", found)
+	}
+}
+
+func TestRunDefs(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "a.scp", joinLines("[ITEMDEF i_gold]", "ID=0eed", "[EOF]"))
+	if code := runDefs([]string{"-type", "itemdef", "-match", "gold"}); code != 0 {
+		t.Fatalf("expected a match to exit 0, got %d", code)
+	}
+	if code := runDefs([]string{"-type", "CHARDEF"}); code != 1 {
+		t.Fatalf("expected no match to exit 1, got %d", code)
+	}
+	if code := runDefs([]string{"-match", "[gold"}); code != 2 {
+		t.Fatalf("expected a bad pattern to exit 2, got %d", code)
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var dialogCallPattern = regexp.MustCompile(`(?i)^\s*(?:[a-z0-9_.]+\.)?(DIALOG|SDIALOG)\s+(\S+)(?:\s+(\S+))?`)
+
+// dialogInfo gathers what is known about one dialog across its layout,
+// TEXT and BUTTON sections, which may live in different files.
+type dialogInfo struct {
+	text        *definitionLocation
+	textLines   int
+	textUses    []dialogIndexUse
+	pages       map[int]bool
+	pageTargets []dialogIndexUse
+	buttonIDs   []dialogIndexUse
+	buttons     *definitionLocation
+	handlers    [][2]int
+	anyButton   bool
+}
+
+type dialogIndexUse struct {
+	file  string
+	line  int
+	value int
+}
+
+// gumpCommand describes the arguments of a dialog layout primitive: the
+// first numeric arguments must be numbers or <expressions>, and the total
+// argument count must lie within min..max (max < 0 means unbounded text).
+type gumpCommand struct {
+	numeric int
+	min     int
+	max     int
+}
+
+var gumpCommands = map[string]gumpCommand{
+	"BUTTON":            {numeric: 7, min: 7, max: 7},
+	"BUTTONTILEART":     {numeric: 11, min: 11, max: 11},
+	"CHECKBOX":          {numeric: 6, min: 6, max: 6},
+	"CHECKERTRANS":      {numeric: 4, min: 4, max: 4},
+	"CROPTEXT":          {numeric: 6, min: 6, max: 6},
+	"DCROPTEXT":         {numeric: 5, min: 5, max: -1},
+	"DHTMLGUMP":         {numeric: 6, min: 6, max: -1},
+	"DORIGIN":           {numeric: 2, min: 2, max: 2},
+	"DTEXT":             {numeric: 3, min: 3, max: -1},
+	"DTEXTENTRY":        {numeric: 6, min: 6, max: -1},
+	"DTEXTENTRYLIMITED": {numeric: 7, min: 7, max: -1},
+	"GROUP":             {numeric: 1, min: 1, max: 1},
+	"GUMPPIC":           {numeric: 3, min: 3, max: 4},
+	"GUMPPICTILED":      {numeric: 5, min: 5, max: 5},
+	"HTMLGUMP":          {numeric: 7, min: 7, max: 7},
+	"NOCLOSE":           {numeric: 0, min: 0, max: 0},
+	"NODISPOSE":         {numeric: 0, min: 0, max: 0},
+	"NOMOVE":            {numeric: 0, min: 0, max: 0},
+	"NORESIZE":          {numeric: 0, min: 0, max: 0},
+	"PAGE":              {numeric: 1, min: 1, max: 1},
+	"RADIO":             {numeric: 6, min: 6, max: 6},
+	"RESIZEPIC":         {numeric: 5, min: 5, max: 5},
+	"TEXT":              {numeric: 4, min: 4, max: 4},
+	"TEXTENTRY":         {numeric: 7, min: 7, max: 7},
+	"TEXTENTRYLIMITED":  {numeric: 8, min: 8, max: 8},
+	"TILEPIC":           {numeric: 3, min: 3, max: 3},
+	"TILEPICHUE":        {numeric: 4, min: 4, max: 4},
+	"TOOLTIP":           {numeric: 1, min: 1, max: -1},
+	"XMFHTMLGUMP":       {numeric: 7, min: 7, max: 7},
+	"XMFHTMLGUMPCOLOR":  {numeric: 8, min: 8, max: 8},
+	"XMFHTMLTOK":        {numeric: 8, min: 8, max: -1},
+}
+
+// gumpTextIndexArg maps the primitives that reference a line of the TEXT
+// section to the (zero-based) position of that index argument.
+var gumpTextIndexArg = map[string]int{
+	"CROPTEXT":         5,
+	"HTMLGUMP":         4,
+	"TEXT":             3,
+	"TEXTENTRY":        6,
+	"TEXTENTRYLIMITED": 6,
+}
+
+// dialogSection splits a DIALOG header into the dialog ID and its section
+// kind ("" for the layout, otherwise TEXT or BUTTON).
+func dialogSection(defArgs string) (string, string) {
+	fields := strings.Fields(defArgs)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	kind := ""
+	if len(fields) > 1 {
+		kind = strings.ToUpper(fields[1])
+	}
+	return strings.ToUpper(fields[0]), kind
+}
+
+func (idx *lintIndex) dialog(id string) *dialogInfo {
+	info, ok := idx.dialogs[id]
+	if !ok {
+		info = &dialogInfo{}
+		idx.dialogs[id] = info
+	}
+	return info
+}
+
+// recordGumpLine remembers the TEXT indexes, pages and buttons of a layout
+// line so they can be checked once every file has been read.
+func (info *dialogInfo) recordGumpLine(line, file string, lineNum int) {
+	token := firstToken(line)
+	name := strings.ToUpper(token)
+	args := splitGumpArgs(strings.TrimSpace(line[len(token):]))
+	literal := func(pos int) (int, bool) {
+		if pos >= len(args) {
+			return 0, false
+		}
+		return parseSphereInt(args[pos])
+	}
+
+	if pos, ok := gumpTextIndexArg[name]; ok {
+		if value, ok := literal(pos); ok {
+			info.textUses = append(info.textUses, dialogIndexUse{file: file, line: lineNum, value: value})
+		}
+		return
+	}
+	switch name {
+	case "PAGE":
+		if value, ok := literal(0); ok {
+			if info.pages == nil {
+				info.pages = make(map[int]bool)
+			}
+			info.pages[value] = true
+		}
+	case "BUTTON", "BUTTONTILEART":
+		buttonType, ok := literal(4)
+		if !ok {
+			return
+		}
+		if buttonType == 0 {
+			if page, ok := literal(5); ok {
+				info.pageTargets = append(info.pageTargets, dialogIndexUse{file: file, line: lineNum, value: page})
+			}
+			return
+		}
+		if id, ok := literal(6); ok && id != 0 {
+			info.buttonIDs = append(info.buttonIDs, dialogIndexUse{file: file, line: lineNum, value: id})
+		}
+	}
+}
+
+// recordButtonHandler registers an ON=N, ON=N M or ON=@AnyButton line of the
+// BUTTON section.
+func (info *dialogInfo) recordButtonHandler(line string) {
+	_, value, ok := splitAssignment(line)
+	if !ok {
+		return
+	}
+	if strings.HasPrefix(value, "@") {
+		if strings.EqualFold(value, "@ANYBUTTON") {
+			info.anyButton = true
+		}
+		return
+	}
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+	if len(fields) == 0 {
+		return
+	}
+	low, ok := parseSphereInt(fields[0])
+	if !ok {
+		return
+	}
+	high := low
+	if len(fields) > 1 {
+		if v, ok := parseSphereInt(fields[1]); ok {
+			high = v
+		}
+	}
+	info.handlers = append(info.handlers, [2]int{low, high})
+}
+
+func (info *dialogInfo) handlesButton(id int) bool {
+	if info.anyButton {
+		return true
+	}
+	for _, h := range info.handlers {
+		if id >= h[0] && id <= h[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func findDialogIssues(dialogs map[string]*dialogInfo) []lintIssue {
+	ids := make([]string, 0, len(dialogs))
+	for id := range dialogs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var issues []lintIssue
+	for _, id := range ids {
+		info := dialogs[id]
+		for _, use := range info.textUses {
+			if info.text == nil {
+				issues = appendError(issues, use.file, use.line, "DIALOG", fmt.Sprintf("DIALOG: text index %d used but %s has no TEXT section", use.value, id))
+				continue
+			}
+			if use.value < 0 || use.value >= info.textLines {
+				issues = appendError(issues, use.file, use.line, "DIALOG", fmt.Sprintf("DIALOG: text index %d out of range for %s (TEXT section at %s:%d has %d lines)", use.value, id, info.text.file, info.text.line, info.textLines))
+			}
+		}
+		for _, use := range info.pageTargets {
+			if !info.pages[use.value] {
+				issues = appendError(issues, use.file, use.line, "DIALOG", fmt.Sprintf("DIALOG: button targets page %d but %s has no 'page %d'", use.value, id, use.value))
+			}
+		}
+		for _, use := range info.buttonIDs {
+			if info.buttons == nil {
+				issues = appendError(issues, use.file, use.line, "DIALOG", fmt.Sprintf("DIALOG: button %d emitted but %s has no BUTTON section", use.value, id))
+				continue
+			}
+			if !info.handlesButton(use.value) {
+				issues = appendError(issues, use.file, use.line, "DIALOG", fmt.Sprintf("DIALOG: button %d has no ON=%d handler in %s BUTTON section (%s:%d)", use.value, use.value, id, info.buttons.file, info.buttons.line))
+			}
+		}
+	}
+	return issues
+}
+
+// validateGumpLine checks a dialog layout line whose first token is a known
+// gump primitive. Other lines (script logic, the origin line) are ignored.
+func validateGumpLine(line, file string, lineNum int) []lintIssue {
+	token := firstToken(line)
+	name := strings.ToUpper(token)
+	spec, ok := gumpCommands[name]
+	if !ok {
+		return nil
+	}
+	args := splitGumpArgs(strings.TrimSpace(line[len(token):]))
+	name = strings.ToLower(name)
+	if len(args) < spec.min || (spec.max >= 0 && len(args) > spec.max) {
+		return appendError(nil, file, lineNum, "DIALOG", fmt.Sprintf("DIALOG: '%s' expects %s, got %d", name, describeGumpArity(spec), len(args)))
+	}
+	var issues []lintIssue
+	for i := 0; i < spec.numeric && i < len(args); i++ {
+		if !isGumpNumber(args[i]) {
+			issues = appendError(issues, file, lineNum, "DIALOG", fmt.Sprintf("DIALOG: '%s' argument %d '%s' is not numeric", name, i+1, args[i]))
+		}
+	}
+	if name == "gumppic" && len(args) == 4 && !hasPrefixFold(args[3], "HUE=") {
+		issues = appendError(issues, file, lineNum, "DIALOG", fmt.Sprintf("DIALOG: 'gumppic' argument 4 '%s' must be hue=N", args[3]))
+	}
+	return issues
+}
+
+func describeGumpArity(spec gumpCommand) string {
+	switch {
+	case spec.max < 0:
+		return fmt.Sprintf("at least %d arguments", spec.min)
+	case spec.min == spec.max:
+		return fmt.Sprintf("%d arguments", spec.min)
+	default:
+		return fmt.Sprintf("%d-%d arguments", spec.min, spec.max)
+	}
+}
+
+// splitGumpArgs splits on whitespace outside <...> expressions so that
+// "<EVAL <X> + 1>" counts as a single argument.
+func splitGumpArgs(value string) []string {
+	var args []string
+	depth := 0
+	start := -1
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '<':
+			depth++
+		case ch == '>' && depth > 0:
+			depth--
+		case (ch == ' ' || ch == '\t') && depth == 0:
+			if start >= 0 {
+				args = append(args, value[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		args = append(args, value[start:])
+	}
+	return args
+}
+
+// isGumpNumber accepts numeric literals, optionally prefixed by the
+// relative-position markers '*', '+' and '-', and <expressions>.
+func isGumpNumber(arg string) bool {
+	arg = strings.TrimLeft(arg, "*+-")
+	if arg == "" {
+		return false
+	}
+	if strings.HasPrefix(arg, "<") {
+		return true
+	}
+	_, ok := parseSphereInt(arg)
+	return ok
+}
+
+// checkDialogCall validates DIALOG/SDIALOG d_x [page] calls: the page must
+// be a number, and dialogs without the d_ prefix are recorded as DIALOG
+// references so renamed dialogs are caught as well.
+func checkDialogCall(line, file string, lineNum int, references *[]referenceUse) []lintIssue {
+	match := dialogCallPattern.FindStringSubmatch(line)
+	if len(match) != 4 {
+		return nil
+	}
+	verb, id, page := strings.ToUpper(match[1]), match[2], match[3]
+	var issues []lintIssue
+	if page != "" && !strings.HasPrefix(page, "<") {
+		if _, ok := parseSphereInt(page); !ok {
+			issues = appendError(issues, file, lineNum, "DIALOG", fmt.Sprintf("DIALOG: page argument '%s' of %s %s must be a number", page, verb, id))
+		}
+	}
+	if isIdentifier(id) && !hasReferencePrefix(id) {
+		*references = append(*references, referenceUse{
+			file:     file,
+			line:     lineNum,
+			defTypes: []string{"DIALOG"},
+			id:       strings.ToUpper(id),
+		})
+	}
+	return issues
+}
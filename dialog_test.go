@@ -0,0 +1,251 @@
+package main
+
+import "testing"
+
+func TestLintDialogLayout(t *testing.T) {
+	t.Run("ValidLayout", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"nomove",
+			"page 0",
+			"resizepic 0 0 5054 300 200",
+			"gumppic 10 10 1234 hue=33",
+			"button 20 *30 4005 4007 1 0 1",
+			"textentry 20 60 200 20 0 1 0",
+			"checkbox 20 90 210 211 0 2",
+			"croptext 20 120 100 20 0 1",
+			"dtext 20 150 <SRC.COLOR> Hello there",
+			"dhtmlgump 20 170 200 40 0 0 <SRC.NAME> says hi",
+			"IF (<SRC.ISGM>)",
+			"  gumppic <EVAL <LOCAL.X> + 10> 10 1234",
+			"ENDIF",
+			"[DIALOG d_test TEXT]",
+			"Name",
+			"Title",
+			"[DIALOG d_test BUTTON]",
+			"ON=1",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_valid.scp", content), "valid dialog layout")
+	})
+
+	t.Run("WrongArgumentCount", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"resizepic 0 0 5054 300",
+			"button 20 30 4005 4007 1 0 1 9",
+			"dtext 20 150",
+			"nomove 1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_arity.scp", content)
+		assertHasMessage(t, errs, "DIALOG: 'resizepic' expects 5 arguments, got 4")
+		assertHasMessage(t, errs, "DIALOG: 'button' expects 7 arguments, got 8")
+		assertHasMessage(t, errs, "DIALOG: 'dtext' expects at least 3 arguments, got 2")
+		assertHasMessage(t, errs, "DIALOG: 'nomove' expects 0 arguments, got 1")
+	})
+
+	t.Run("NonNumericArguments", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"gumppic 10 ten 1234",
+			"gumppic 10 10 1234 33",
+			"checkbox 20 90 210 211 on 2",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_numeric.scp", content)
+		assertHasMessage(t, errs, "DIALOG: 'gumppic' argument 2 'ten' is not numeric")
+		assertHasMessage(t, errs, "DIALOG: 'gumppic' argument 4 '33' must be hue=N")
+		assertHasMessage(t, errs, "DIALOG: 'checkbox' argument 5 'on' is not numeric")
+	})
+
+	t.Run("OnlyLayoutSection", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test BUTTON]",
+			"ON=1",
+			"page 1 2 3",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_button_section.scp", content), "button section")
+	})
+}
+
+func TestLintDialogTextIndexes(t *testing.T) {
+	t.Run("IndexesInRange", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"text 10 10 0 0",
+			"croptext 10 30 100 20 0 1",
+			"textentry 10 50 100 20 0 1 2",
+			"htmlgump 10 70 100 20 <LOCAL.TEXT> 0 0",
+			"[DIALOG d_test TEXT]",
+			"Name",
+			"Title (optional)",
+			"// comment lines are not entries",
+			"Notes",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_text_valid.scp", content), "text indexes in range")
+	})
+
+	t.Run("IndexOutOfRange", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test TEXT]",
+			"Name",
+			"[DIALOG d_test]",
+			"0,0",
+			"text 10 10 0 0",
+			"text 10 30 0 1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_text_range.scp", content)
+		assertHasMessage(t, errs, "DIALOG: text index 1 out of range for D_TEST (TEXT section at dialog_text_range.scp:1 has 1 lines)")
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+	})
+
+	t.Run("MissingTextSection", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"textentrylimited 10 50 100 20 0 1 0 30",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_text_missing.scp", content)
+		assertHasMessage(t, errs, "DIALOG: text index 0 used but D_TEST has no TEXT section")
+	})
+}
+
+func TestLintDialogPagesAndButtons(t *testing.T) {
+	t.Run("HandledButtons", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"page 0",
+			"button 10 10 4005 4007 0 2 0",
+			"button 10 30 4005 4007 1 0 1",
+			"button 10 50 4005 4007 1 0 5",
+			"button 10 70 4005 4007 1 0 <LOCAL.ID>",
+			"page 2",
+			"button 10 10 4014 4016 0 1 0",
+			"page 1",
+			"[DIALOG d_test BUTTON]",
+			"ON=0",
+			"ON=1",
+			"SRC.SYSMESSAGE ok",
+			"ON=4 6",
+			"SRC.SYSMESSAGE range",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_buttons_valid.scp", content), "handled buttons")
+	})
+
+	t.Run("AnyButtonHandler", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"button 10 30 4005 4007 1 0 7",
+			"[DIALOG d_test BUTTON]",
+			"ON=@AnyButton",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_buttons_any.scp", content), "anybutton handler")
+	})
+
+	t.Run("MissingPage", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"page 1",
+			"button 10 10 4005 4007 0 3 0",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_missing_page.scp", content)
+		assertHasMessage(t, errs, "DIALOG: button targets page 3 but D_TEST has no 'page 3'")
+	})
+
+	t.Run("UnhandledButton", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"button 10 30 4005 4007 1 0 1",
+			"button 10 50 4005 4007 1 0 2",
+			"[DIALOG d_test BUTTON]",
+			"ON=1",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_unhandled.scp", content)
+		assertHasMessage(t, errs, "DIALOG: button 2 has no ON=2 handler in D_TEST BUTTON section (dialog_unhandled.scp:5)")
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+	})
+
+	t.Run("MissingButtonSection", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_test]",
+			"0,0",
+			"button 10 30 4005 4007 1 0 3",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_no_buttons.scp", content)
+		assertHasMessage(t, errs, "DIALOG: button 3 emitted but D_TEST has no BUTTON section")
+	})
+}
+
+func TestLintDialogCalls(t *testing.T) {
+	t.Run("ValidCalls", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_shop]",
+			"0,0",
+			"page 0",
+			"[DIALOG shopkeeper_menu]",
+			"0,0",
+			"page 0",
+			"[FUNCTION f_open]",
+			"SRC.DIALOG d_shop 2",
+			"SDIALOG shopkeeper_menu",
+			"DIALOG d_shop <ARGN1>",
+			"SRC.DIALOG <TAG.DLG>",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "dialog_calls_ok.scp", content), "valid dialog calls")
+	})
+
+	t.Run("InvalidCalls", func(t *testing.T) {
+		content := joinLines(
+			"[DIALOG d_shop]",
+			"0,0",
+			"page 0",
+			"[FUNCTION f_open]",
+			"SRC.DIALOG d_shop two",
+			"SDIALOG shopkeeper_menu",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "dialog_calls_bad.scp", content)
+		assertHasMessage(t, errs, "DIALOG: page argument 'two' of DIALOG d_shop must be a number")
+		assertHasMessage(t, errs, "UNDECLARED: 'SHOPKEEPER_MENU' not defined as DIALOG")
+	})
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// treeSnapshot is what "sphere-lint diff" compares between two trees: the
+// body of every tracked definition and the issues of a full lint run.
+type treeSnapshot struct {
+	defs   map[string]*symbolInfo
+	bodies map[string]string
+	issues []lintIssue
+}
+
+// scanTree lints every script under root into a fresh index, reporting
+// paths relative to root.
+func scanTree(root string) (*lintIndex, []lintIssue) {
+	prevRoot := scriptsRoot
+	scriptsRoot = root
+	defer func() { scriptsRoot = prevRoot }()
+
+	index := newLintIndex()
+	var issues []lintIssue
+	issues = append(issues, walkScripts(root, func(path string) {
+		issues = append(issues, lintScriptFile(path, index)...)
+	})...)
+	return index, append(issues, lintIndexIssues(index)...)
+}
+
+// lintTree lints every script under root and snapshots the definitions.
+func lintTree(root string) treeSnapshot {
+	index, issues := scanTree(root)
+
+	snap := treeSnapshot{defs: make(map[string]*symbolInfo), bodies: make(map[string]string), issues: issues}
+	files := make(map[string][]string)
+	for _, s := range index.symbols {
+		lines, ok := files[s.File]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(s.File)))
+			if err == nil {
+				lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+			}
+			files[s.File] = lines
+		}
+		key := s.Type + " " + strings.ToUpper(s.ID)
+		if _, ok := snap.defs[key]; !ok {
+			snap.defs[key] = s
+		}
+		snap.bodies[key] += sectionText(lines, s.Line, s.end)
+	}
+	return snap
+}
+
+// sectionText returns lines first..last (1-based) without comments, blank
+// lines or indentation, so reformatting does not count as a change.
+func sectionText(lines []string, first, last int) string {
+	var b strings.Builder
+	for i := first; i <= last && i <= len(lines); i++ {
+		if cleaned := cleanLine(lines[i-1]); cleaned != "" {
+			b.WriteString(cleaned)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// definitionChange is an added (+), removed (-) or changed (~) definition.
+type definitionChange struct {
+	mark   byte
+	symbol *symbolInfo
+}
+
+// diffDefinitions compares the definitions of two snapshots, sorted by type
+// and ID.
+func diffDefinitions(oldTree, newTree treeSnapshot) []definitionChange {
+	keys := make(map[string]bool)
+	for key := range oldTree.defs {
+		keys[key] = true
+	}
+	for key := range newTree.defs {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var changes []definitionChange
+	for _, key := range sorted {
+		before, inOld := oldTree.defs[key]
+		after, inNew := newTree.defs[key]
+		switch {
+		case !inOld:
+			changes = append(changes, definitionChange{mark: '+', symbol: after})
+		case !inNew:
+			changes = append(changes, definitionChange{mark: '-', symbol: before})
+		case oldTree.bodies[key] != newTree.bodies[key]:
+			changes = append(changes, definitionChange{mark: '~', symbol: after})
+		}
+	}
+	return changes
+}
+
+// newIssues returns the issues of after that before does not have. Issues are
+// matched by file, kind and message, not line, so code moving around does
+// not count as a new issue.
+func newIssues(before, after []lintIssue) []lintIssue {
+	key := func(issue lintIssue) string {
+		return issue.file + "\x00" + issue.kind + "\x00" + issue.msg
+	}
+	seen := make(map[string]int)
+	for _, issue := range before {
+		seen[key(issue)]++
+	}
+	var added []lintIssue
+	for _, issue := range after {
+		if seen[key(issue)] > 0 {
+			seen[key(issue)]--
+			continue
+		}
+		added = append(added, issue)
+	}
+	return added
+}
+
+// writeDefinitionChanges prints one line per changed definition with its
+// location in the tree it exists in (the old one for removals).
+func writeDefinitionChanges(w io.Writer, changes []definitionChange) {
+	for _, c := range changes {
+		fmt.Fprintf(w, "%c %s %s (%s:%d)\n", c.mark, c.symbol.Type, c.symbol.ID, c.symbol.File, c.symbol.Line)
+	}
+}
+
+// runDiff implements "sphere-lint diff OLD_DIR NEW_DIR": it lists the
+// definitions added, removed or changed between two versions of a pack and
+// the lint issues the new version introduces, exiting 1 if one of those is
+// an error.
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint diff", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in NEW_DIR)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: sphere-lint diff [-config file] OLD_DIR NEW_DIR")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	oldDir, newDir := flags.Arg(0), flags.Arg(1)
+
+	prevRoot := scriptsRoot
+	scriptsRoot = newDir
+	err := setupConfig(*configPath, "")
+	scriptsRoot = prevRoot
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	oldTree, newTree := lintTree(oldDir), lintTree(newDir)
+	changes := diffDefinitions(oldTree, newTree)
+	writeDefinitionChanges(os.Stdout, changes)
+
+	added := newIssues(oldTree.issues, newTree.issues)
+	errorCount := 0
+	for _, issue := range added {
+		printError(issue)
+		if issue.severity == severityError {
+			errorCount++
+		}
+	}
+
+	counts := make(map[byte]int)
+	for _, c := range changes {
+		counts[c.mark]++
+	}
+	fmt.Println("---------------------------------------------")
+	fmt.Printf("Definitions added: %d, removed: %d, changed: %d\n", counts['+'], counts['-'], counts['~'])
+	fmt.Printf("New errors: %d\n", errorCount)
+	fmt.Printf("New warnings: %d\n", len(added)-errorCount)
+	if errorCount > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestDiffTrees(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeTempFile(t, oldDir, "items.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"NAME=Fire Sword",
+		"[ITEMDEF i_sword_old]",
+		"ID=0f61",
+		"[ITEMDEF i_sword_ice]",
+		"ID=0f61",
+		"[FUNCTION f_test]",
+		"SRC.NEWITEM i_missing",
+		"[EOF]",
+	))
+	writeTempFile(t, newDir, "items.scp", joinLines(
+		"// the ice sword moved up, reformatted",
+		"[ITEMDEF i_sword_ice]",
+		"  ID=0f61",
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"NAME=Flame Sword",
+		"[ITEMDEF i_sword_new]",
+		"ID=0f61",
+		"[FUNCTION f_test]",
+		"SRC.NEWITEM i_missing",
+		"SRC.NEWITEM i_typo",
+		"[EOF]",
+	))
+
+	oldTree, newTree := lintTree(oldDir), lintTree(newDir)
+	var out bytes.Buffer
+	writeDefinitionChanges(&out, diffDefinitions(oldTree, newTree))
+	want := joinLines(
+		"~ FUNCTION f_test (items.scp:9)",
+		"~ ITEMDEF i_sword_fire (items.scp:4)",
+		"+ ITEMDEF i_sword_new (items.scp:7)",
+		"- ITEMDEF i_sword_old (items.scp:4)",
+	)
+	if out.String() != want {
+		t.Fatalf("unexpected definition changes:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	added := newIssues(oldTree.issues, newTree.issues)
+	if len(added) != 1 || added[0].line != 11 {
+		t.Fatalf("expected only the undeclared i_typo as a new issue, got %+v", added)
+	}
+	assertHasMessage(t, added, "UNDECLARED: 'I_TYPO'")
+}
+
+func TestRunDiff(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeTempFile(t, oldDir, "a.scp", joinLines("[FUNCTION f_test]", "SERV.LOG hi", "[EOF]"))
+	writeTempFile(t, newDir, "a.scp", joinLines("[FUNCTION f_test]", "SERV.LOG hi", "[EOF]"))
+	if code := runDiff([]string{oldDir, newDir}); code != 0 {
+		t.Fatalf("expected identical trees to exit 0, got %d", code)
+	}
+	if err := os.WriteFile(newDir+"/a.scp", []byte(joinLines("[FUNCTION f_test]", "IF 1", "[EOF]")), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if code := runDiff([]string{oldDir, newDir}); code != 1 {
+		t.Fatalf("expected a new error to exit 1, got %d", code)
+	}
+	if code := runDiff([]string{oldDir}); code != 2 {
+		t.Fatalf("expected a missing directory to exit 2, got %d", code)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import "fmt"
+
+// doBlock counts the statements of a DORAND/DOSWITCH block, which pick one
+// of their lines to run; a nested block counts as a single line.
+type doBlock struct {
+	token      string
+	line       int
+	arg        string
+	statements int
+}
+
+// newDoBlock returns a counter for DORAND and DOSWITCH lines, or nil.
+func newDoBlock(token, line string, lineNum int) *doBlock {
+	if token != "DORAND" && token != "DOSWITCH" {
+		return nil
+	}
+	return &doBlock{token: token, line: lineNum, arg: conditionText(line)}
+}
+
+// noteDoStatement counts a line for the innermost block when it is a DO
+// block. It runs before the line changes the stack, so the line opening a
+// nested block is counted and the lines inside it are not.
+func noteDoStatement(stack []blockState, token string) {
+	if len(stack) == 0 || stack[len(stack)-1].do == nil {
+		return
+	}
+	if normalizeEndToken(token) != "" || token == "ELSE" || token == "ELIF" || token == "ELSEIF" {
+		return
+	}
+	stack[len(stack)-1].do.statements++
+}
+
+// inDoBlock reports whether the innermost block is a DORAND, DOSWITCH or
+// DOSELECT, whose lines are alternatives rather than a sequence: a RETURN
+// there does not make the next line unreachable.
+func inDoBlock(stack []blockState) bool {
+	if len(stack) == 0 {
+		return false
+	}
+	switch stack[len(stack)-1].typ {
+	case "DORAND", "DOSWITCH", "DOSELECT":
+		return true
+	}
+	return false
+}
+
+// finish compares a literal DORAND count or DOSWITCH selector with the
+// lines of the block once its ENDDO is reached.
+func (d *doBlock) finish(file string) []lintIssue {
+	if d == nil {
+		return nil
+	}
+	if d.token == "DOSWITCH" {
+		return d.finishSwitch(file)
+	}
+	n, ok := parseSphereInt(d.arg)
+	if !ok || n == d.statements {
+		return nil
+	}
+	effect := "the last lines can never run"
+	if n > d.statements {
+		effect = "some picks run nothing"
+	}
+	return appendWarning(nil, file, d.line, "LOGIC", fmt.Sprintf("LOGIC: DORAND %s picks from %d lines but the block has %d; %s.", d.arg, n, d.statements, effect))
+}
+
+// finishSwitch reports empty DOSWITCH blocks and constant selectors past
+// the last line; the selector is 0-based.
+func (d *doBlock) finishSwitch(file string) []lintIssue {
+	if d.statements == 0 {
+		return appendWarning(nil, file, d.line, "LOGIC", "LOGIC: DOSWITCH block has no lines to select from.")
+	}
+	n, ok := parseSphereInt(d.arg)
+	if !ok || (n >= 0 && n < d.statements) {
+		return nil
+	}
+	return appendWarning(nil, file, d.line, "LOGIC", fmt.Sprintf("LOGIC: DOSWITCH %s selects line %d but the block only has lines 0-%d; nothing runs.", d.arg, n, d.statements-1))
+}
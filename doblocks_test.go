@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestLintDorandLineCount(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_gold]",
+		"[FUNCTION f_loot]",
+		"DORAND 3",
+		"  SERV.NEWITEM i_gold",
+		"  BEGIN",
+		"    SERV.NEWITEM i_gold",
+		"    SERV.NEWITEM i_gold",
+		"  END",
+		"  IF <SRC.ISPLAYER>",
+		"    SERV.NEWITEM i_gold",
+		"  ENDIF",
+		"ENDDO",
+		"DORAND 2",
+		"  SERV.NEWITEM i_gold",
+		"  SERV.NEWITEM i_gold",
+		"  SERV.NEWITEM i_gold",
+		"ENDDO",
+		"DORAND 4",
+		"  SERV.NEWITEM i_gold",
+		"ENDDO",
+		"DORAND <TAG.COUNT>",
+		"  SERV.NEWITEM i_gold",
+		"ENDDO",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "dorand.scp", content)
+	assertHasMessage(t, errs, "LOGIC: DORAND 2 picks from 2 lines but the block has 3; the last lines can never run.")
+	assertHasMessage(t, errs, "LOGIC: DORAND 4 picks from 4 lines but the block has 1; some picks run nothing.")
+	if len(errs) != 2 || errs[0].line != 13 || errs[1].line != 18 {
+		t.Fatalf("expected DORAND warnings on lines 13 and 18, got %+v", errs)
+	}
+}
+
+func TestLintDoswitchSelector(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_pick]",
+		"DOSWITCH 2",
+		"  SRC.SYSMESSAGE zero",
+		"  SRC.SYSMESSAGE one",
+		"ENDDO",
+		"DOSWITCH 1",
+		"  SRC.SYSMESSAGE zero",
+		"  SRC.SYSMESSAGE one",
+		"ENDDO",
+		"DOSWITCH <SRC.FAME>",
+		"ENDDO",
+		"DOSWITCH <ARGN1>",
+		"  SRC.SYSMESSAGE zero",
+		"ENDDO",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "doswitch.scp", content)
+	assertHasMessage(t, errs, "LOGIC: DOSWITCH 2 selects line 2 but the block only has lines 0-1; nothing runs.")
+	assertHasMessage(t, errs, "LOGIC: DOSWITCH block has no lines to select from.")
+	if len(errs) != 2 || errs[0].line != 2 || errs[1].line != 10 {
+		t.Fatalf("expected DOSWITCH warnings on lines 2 and 10, got %+v", errs)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import "strings"
+
+// checkEmptyScript reports a script with no statements: an empty file or one
+// with nothing but comments and perhaps an [EOF]. The server loads nothing
+// from it, so empty is true and the caller skips the missing-[EOF] check. A
+// file holding only [EOF] is a deliberate stub and is left alone.
+func checkEmptyScript(content, file string) (issues []lintIssue, empty bool) {
+	if strings.TrimSpace(content) == "" {
+		return appendWarning(nil, file, 1, "EMPTY", "EMPTY: file is empty; the server loads nothing from it."), true
+	}
+	commented := false
+	for _, line := range strings.Split(content, "\n") {
+		cleaned := cleanLine(line)
+		switch {
+		case cleaned == "":
+			commented = commented || strings.TrimSpace(line) != ""
+		case !strings.EqualFold(cleaned, "[EOF]"):
+			return nil, false
+		}
+	}
+	if !commented {
+		return nil, false
+	}
+	return appendWarning(nil, file, 1, "EMPTY", "EMPTY: file only has comments; the server loads nothing from it."), true
+}
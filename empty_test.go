@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestLintEmptyScripts(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		for _, content := range []string{"", " \n\t\n"} {
+			errs := lintFromContent(t, "empty.scp", content)
+			assertHasMessage(t, errs, "EMPTY: file is empty; the server loads nothing from it.")
+			if len(errs) != 1 || errs[0].severity != severityWarning {
+				t.Fatalf("expected only the empty file warning for %q, got %+v", content, errs)
+			}
+		}
+	})
+
+	t.Run("CommentOnly", func(t *testing.T) {
+		for _, content := range []string{
+			joinLines("// old spawns, moved to spawns.scp", ""),
+			joinLines("// old spawns, moved to spawns.scp", "[EOF]"),
+		} {
+			errs := lintFromContent(t, "comments.scp", content)
+			assertHasMessage(t, errs, "EMPTY: file only has comments; the server loads nothing from it.")
+			if len(errs) != 1 || errs[0].line != 1 {
+				t.Fatalf("expected only the comment-only warning at line 1 for %q, got %+v", content, errs)
+			}
+		}
+	})
+
+	t.Run("NoHeader", func(t *testing.T) {
+		errs := lintFromContent(t, "headless.scp", joinLines(
+			"// lamp setup",
+			"ID=0a22",
+			"NAME=lamp",
+			"[EOF]",
+		))
+		assertHasMessage(t, errs, "LOGIC: file has 2 statement(s) but no [SECTION] header; the server ignores the whole file.")
+		if len(errs) != 1 || errs[0].line != 2 || errs[0].severity == severityWarning {
+			t.Fatalf("expected one error at line 2, got %+v", errs)
+		}
+	})
+
+	t.Run("Stub", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "stub.scp", joinLines("[EOF]")), "a file holding only [EOF]")
+	})
+}
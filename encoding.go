@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// scriptEncodings are the values accepted by the encoding config field.
+var scriptEncodings = map[string]bool{"utf-8": true, "cp1252": true}
+
+// utf8BOM is the byte order mark some Windows editors put in front of UTF-8
+// files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// cp1252High maps the 0x80-0x9F range of Windows-1252 to Unicode; zero
+// entries are bytes the code page leaves undefined. The rest of the code
+// page matches Latin-1.
+var cp1252High = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// decodeScript checks data against the configured encoding and returns it
+// as UTF-8 text for the other rules. UTF-16 files are always decoded, since
+// every other rule would only see NUL bytes otherwise. A UTF-8 byte order
+// mark is dropped, and reported when the utf8-bom rule is enabled.
+func decodeScript(data []byte, file string) (string, []lintIssue) {
+	var issues []lintIssue
+	if rest, ok := bytes.CutPrefix(data, utf8BOM); ok {
+		// The mark would otherwise stick to the first [SECTION] header.
+		data = rest
+		if ruleEnabled("utf8-bom") {
+			issues = appendWarning(issues, file, 1, "STYLE", "STYLE: file starts with a UTF-8 byte order mark; run sphere-lint format to strip it")
+		}
+	}
+	text, encodingIssues := decodeText(data, file)
+	return text, append(issues, encodingIssues...)
+}
+
+// decodeText decodes a script without a UTF-8 byte order mark.
+func decodeText(data []byte, file string) (string, []lintIssue) {
+	if order := utf16ByteOrder(data); order != "" {
+		msg := fmt.Sprintf("ENCODING: file is saved as UTF-16 (%s); the server reads it as garbage, save it as %s", order, strings.ToUpper(config.Encoding))
+		return decodeUTF16(data, order == "big endian"), appendError(nil, file, 1, "ENCODING", msg)
+	}
+	if config.Encoding == "cp1252" {
+		return decodeCP1252(data, file)
+	}
+	return string(data), checkUTF8(data, file)
+}
+
+// utf16ByteOrder recognises UTF-16 by its byte order mark, or by the NUL
+// byte every ASCII character carries when there is none.
+func utf16ByteOrder(data []byte) string {
+	switch {
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		return "little endian"
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return "big endian"
+	}
+	sample := data
+	if len(sample) > 512 {
+		sample = sample[:512]
+	}
+	if len(sample) < 4 {
+		return ""
+	}
+	var zeros [2]int
+	for i, b := range sample {
+		if b == 0 {
+			zeros[i%2]++
+		}
+	}
+	half := len(sample) / 2
+	switch {
+	case zeros[1] > half*3/4 && zeros[0] == 0:
+		return "little endian"
+	case zeros[0] > half*3/4 && zeros[1] == 0:
+		return "big endian"
+	}
+	return ""
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	text := string(utf16.Decode(units))
+	return strings.TrimPrefix(text, "\ufeff")
+}
+
+// checkUTF8 reports the first line that is not valid UTF-8, with the number
+// of such lines, rather than flooding the output for a file saved in a
+// Windows code page.
+func checkUTF8(data []byte, file string) []lintIssue {
+	if utf8.Valid(data) {
+		return nil
+	}
+	firstLine, firstByte, badLines := 0, byte(0), 0
+	for i, line := range strings.Split(string(data), "\n") {
+		if utf8.ValidString(line) {
+			continue
+		}
+		badLines++
+		if firstLine == 0 {
+			firstLine = i + 1
+			for j := 0; j < len(line); {
+				r, size := utf8.DecodeRuneInString(line[j:])
+				if r == utf8.RuneError && size == 1 {
+					firstByte = line[j]
+					break
+				}
+				j += size
+			}
+		}
+	}
+	msg := fmt.Sprintf("ENCODING: invalid UTF-8 byte 0x%02X (%d line(s) affected); set \"encoding\": \"cp1252\" if the scripts are saved in Windows-1252", firstByte, badLines)
+	return appendError(nil, file, firstLine, "ENCODING", msg)
+}
+
+// decodeCP1252 converts Windows-1252 text to UTF-8, reporting bytes the
+// code page does not define and files that are actually UTF-8.
+func decodeCP1252(data []byte, file string) (string, []lintIssue) {
+	var issues []lintIssue
+	if utf8.Valid(data) {
+		for i, line := range strings.Split(string(data), "\n") {
+			if strings.IndexFunc(line, func(r rune) bool { return r >= utf8.RuneSelf }) >= 0 {
+				issues = appendWarning(issues, file, i+1, "ENCODING", "ENCODING: file looks like UTF-8 but the configured encoding is cp1252; its non-ASCII text will show up garbled")
+				break
+			}
+		}
+	}
+	var out strings.Builder
+	out.Grow(len(data))
+	lineNum := 1
+	reported := false
+	for _, b := range data {
+		switch {
+		case b == '\n':
+			lineNum++
+			out.WriteByte(b)
+		case b < 0x80:
+			out.WriteByte(b)
+		case b < 0xA0:
+			r := cp1252High[b-0x80]
+			if r == 0 {
+				if !reported {
+					issues = appendError(issues, file, lineNum, "ENCODING", fmt.Sprintf("ENCODING: byte 0x%02X is not defined in cp1252", b))
+					reported = true
+				}
+				r = utf8.RuneError
+			}
+			out.WriteRune(r)
+		default:
+			out.WriteRune(rune(b))
+		}
+	}
+	return out.String(), issues
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestLintFileEncoding(t *testing.T) {
+	script := joinLines(
+		"[FUNCTION f_greet]",
+		"SAY caf\xe9",
+		"IF <SRC.ISPLAYER>",
+		"[EOF]",
+	)
+
+	t.Run("InvalidUTF8", func(t *testing.T) {
+		errs := lintFromContent(t, "encoding.scp", script)
+		assertHasMessage(t, errs, "ENCODING: invalid UTF-8 byte 0xE9 (1 line(s) affected)")
+		if errs[0].line != 2 {
+			t.Fatalf("expected the encoding error on line 2, got %+v", errs[0])
+		}
+	})
+
+	t.Run("CP1252", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Encoding = "cp1252" })
+
+		errs := lintFromContent(t, "encoding.scp", script)
+		for _, e := range errs {
+			if e.kind == "ENCODING" {
+				t.Fatalf("expected no encoding errors, got %+v", e)
+			}
+		}
+		assertHasMessage(t, errs, "BLOCK")
+
+		errs = lintFromContent(t, "encoding.scp", joinLines("[FUNCTION f_greet]", "SAY \x81", "[EOF]"))
+		assertHasMessage(t, errs, "ENCODING: byte 0x81 is not defined in cp1252")
+
+		errs = lintFromContent(t, "encoding.scp", joinLines("[FUNCTION f_greet]", "SAY café", "[EOF]"))
+		assertHasMessage(t, errs, "ENCODING: file looks like UTF-8 but the configured encoding is cp1252")
+	})
+
+	t.Run("UTF16", func(t *testing.T) {
+		text := "\ufeff" + strings.ReplaceAll(joinLines("[FUNCTION f_greet]", "IF <SRC.ISPLAYER>", "[EOF]"), "\n", "\r\n")
+		var data []byte
+		for _, unit := range utf16.Encode([]rune(text)) {
+			data = append(data, byte(unit), byte(unit>>8))
+		}
+
+		errs := lintFromContent(t, "encoding.scp", string(data))
+		assertHasMessage(t, errs, "ENCODING: file is saved as UTF-16 (little endian)")
+		assertHasMessage(t, errs, "BLOCK")
+		for _, e := range errs {
+			if strings.Contains(e.msg, "[EOF]") {
+				t.Fatalf("expected the decoded file to end with [EOF], got %+v", e)
+			}
+		}
+
+		if order := utf16ByteOrder(data[2:]); order != "little endian" {
+			t.Fatalf("expected UTF-16 without BOM to be detected, got %q", order)
+		}
+	})
+	t.Run("UTF8BOM", func(t *testing.T) {
+		bom := "\ufeff" + joinLines("[FUNCTION f_greet]", "IF <SRC.ISPLAYER>", "[EOF]")
+
+		errs := lintFromContent(t, "encoding.scp", bom)
+		assertHasMessage(t, errs, "BLOCK")
+		for _, e := range errs {
+			if e.kind == "STYLE" || strings.Contains(e.msg, "before the first [SECTION]") {
+				t.Fatalf("expected the BOM to be ignored, got %+v", e)
+			}
+		}
+
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"utf8-bom"} })
+		errs = lintFromContent(t, "encoding.scp", bom)
+		assertHasMessage(t, errs, "STYLE: file starts with a UTF-8 byte order mark")
+
+		if got := formatScript(bom); strings.HasPrefix(got, "\ufeff") {
+			t.Fatalf("expected format to strip the BOM, got %q", got)
+		}
+	})
+}
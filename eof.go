@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// eofMarker tracks the [EOF] lines of a file. The server stops reading at
+// the first one, so anything after it is silently ignored.
+type eofMarker struct {
+	line     int
+	trailing bool
+}
+
+// check records the first [EOF] line. Once one is seen, the first non-empty
+// line after it is reported, as a duplicate [EOF] or as ignored content;
+// one report is enough, since the server reads none of it.
+func (m *eofMarker) check(cleaned, file string, lineNum int) []lintIssue {
+	isEOF := strings.EqualFold(cleaned, "[EOF]")
+	if m.line == 0 {
+		if isEOF {
+			m.line = lineNum
+		}
+		return nil
+	}
+	if m.trailing {
+		return nil
+	}
+	m.trailing = true
+	if isEOF {
+		return appendError(nil, file, lineNum, "CRITICAL", fmt.Sprintf("CRITICAL: duplicate [EOF]; the first one is at line %d.", m.line))
+	}
+	return appendError(nil, file, m.line, "CRITICAL", fmt.Sprintf("CRITICAL: [EOF] is not at the end of the file; the server ignores everything after it (next content at line %d).", lineNum))
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestLintEOFPlacement(t *testing.T) {
+	t.Run("ContentAfterEOF", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_first]",
+			"RETURN 1",
+			"[EOF]",
+			"",
+			"[FUNCTION f_second]",
+			"IF <SRC.ISPLAYER>",
+			"\tSRC.NEWITEM i_missing",
+			"RETURN (2",
+			"[eof]",
+		)
+
+		errs := lintFromContent(t, "mid_eof.scp", content)
+		assertHasMessage(t, errs, "CRITICAL: [EOF] is not at the end of the file; the server ignores everything after it (next content at line 5).")
+		if len(errs) != 1 || errs[0].line != 3 {
+			t.Fatalf("expected only the misplaced [EOF] at line 3, got %+v", errs)
+		}
+	})
+
+	t.Run("DuplicateEOF", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_first]",
+			"RETURN 1",
+			"[EOF]",
+			"// done",
+			"[eof]",
+			"[FUNCTION f_second]",
+			"ENDIF",
+		)
+
+		errs := lintFromContent(t, "duplicate_eof.scp", content)
+		assertHasMessage(t, errs, "CRITICAL: duplicate [EOF]; the first one is at line 3.")
+		if len(errs) != 1 || errs[0].line != 5 {
+			t.Fatalf("expected only the duplicate [EOF] at line 5, got %+v", errs)
+		}
+	})
+
+	t.Run("CommentSection", func(t *testing.T) {
+		content := joinLines(
+			"[COMMENT notes]",
+			"These notes run to the end.",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "comment_eof.scp", content), "a comment section closed by [EOF]")
+	})
+}
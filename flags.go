@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flagTable is the set of named bits a bitmask property accepts. Names are
+// recognised by their prefixes; the first one names the table in messages.
+type flagTable struct {
+	prefixes []string
+	bits     map[string]int64
+	mask     int64
+}
+
+func newFlagTable(bits map[string]int64, prefixes ...string) *flagTable {
+	table := &flagTable{prefixes: prefixes, bits: bits}
+	for _, bit := range bits {
+		table.mask |= bit
+	}
+	return table
+}
+
+// named reports whether name uses one of the table's prefixes.
+func (t *flagTable) named(name string) bool {
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	itemAttrFlags = newFlagTable(map[string]int64{
+		"ATTR_IDENTIFIED":  0x00000001,
+		"ATTR_DECAY":       0x00000002,
+		"ATTR_NEWBIE":      0x00000004,
+		"ATTR_MOVE_ALWAYS": 0x00000008,
+		"ATTR_MOVE_NEVER":  0x00000010,
+		"ATTR_MAGIC":       0x00000020,
+		"ATTR_OWNED":       0x00000040,
+		"ATTR_INVIS":       0x00000080,
+		"ATTR_CURSED":      0x00000100,
+		"ATTR_CURSED2":     0x00000200,
+		"ATTR_BLESSED":     0x00000400,
+		"ATTR_BLESSED2":    0x00000800,
+		"ATTR_FORSALE":     0x00001000,
+		"ATTR_STOLEN":      0x00002000,
+		"ATTR_CAN_DECAY":   0x00004000,
+		"ATTR_STATIC":      0x00008000,
+		"ATTR_EXCEPTIONAL": 0x00010000,
+		"ATTR_ENCHANTED":   0x00020000,
+		"ATTR_IMBUED":      0x00040000,
+		"ATTR_QUESTITEM":   0x00080000,
+		"ATTR_INSURED":     0x00100000,
+		"ATTR_NODROP":      0x00200000,
+		"ATTR_NOTRADE":     0x00400000,
+		"ATTR_ARTIFACT":    0x00800000,
+		"ATTR_LOCKEDDOWN":  0x01000000,
+		"ATTR_SECURE":      0x02000000,
+		"ATTR_REFORGED":    0x04000000,
+		"ATTR_OPENED":      0x08000000,
+	}, "ATTR_")
+
+	charStatFlags = newFlagTable(map[string]int64{
+		"STATF_INVUL":         0x00000001,
+		"STATF_DEAD":          0x00000002,
+		"STATF_FREEZE":        0x00000004,
+		"STATF_INVISIBLE":     0x00000008,
+		"STATF_SLEEPING":      0x00000010,
+		"STATF_WAR":           0x00000020,
+		"STATF_REACTIVE":      0x00000040,
+		"STATF_POISONED":      0x00000080,
+		"STATF_NIGHTSIGHT":    0x00000100,
+		"STATF_REFLECTION":    0x00000200,
+		"STATF_POLYMORPH":     0x00000400,
+		"STATF_INCOGNITO":     0x00000800,
+		"STATF_SPIRITSPEAK":   0x00001000,
+		"STATF_INSUBSTANTIAL": 0x00002000,
+		"STATF_EMOTEACTION":   0x00004000,
+		"STATF_COMM_CRYSTAL":  0x00008000,
+		"STATF_HASSHIELD":     0x00010000,
+		"STATF_ARCHERCANMOVE": 0x00020000,
+		"STATF_STONE":         0x00040000,
+		"STATF_HOVERING":      0x00080000,
+		"STATF_FLY":           0x00100000,
+		"STATF_HALLUCINATING": 0x00400000,
+		"STATF_HIDDEN":        0x00800000,
+		"STATF_INDOORS":       0x01000000,
+		"STATF_CRIMINAL":      0x02000000,
+		"STATF_CONJURED":      0x04000000,
+		"STATF_PET":           0x08000000,
+		"STATF_SPAWNED":       0x10000000,
+		"STATF_SAVEPARITY":    0x20000000,
+		"STATF_RIDDEN":        0x40000000,
+		"STATF_ONHORSE":       0x80000000,
+	}, "STATF_")
+
+	itemCanFlags = newFlagTable(map[string]int64{
+		"CAN_I_DOOR":            0x00000001,
+		"CAN_I_WATER":           0x00000002,
+		"CAN_I_PLATFORM":        0x00000004,
+		"CAN_I_BLOCK":           0x00000008,
+		"CAN_I_CLIMB":           0x00000010,
+		"CAN_I_FIRE":            0x00000020,
+		"CAN_I_ROOF":            0x00000040,
+		"CAN_I_HOVER":           0x00000080,
+		"CAN_I_PILE":            0x00000100,
+		"CAN_I_DYE":             0x00000200,
+		"CAN_I_FLIP":            0x00000400,
+		"CAN_I_LIGHT":           0x00000800,
+		"CAN_I_REPAIR":          0x00001000,
+		"CAN_I_REPLICATE":       0x00002000,
+		"CAN_I_DCIGNORELOS":     0x00004000,
+		"CAN_I_DCIGNOREDIST":    0x00008000,
+		"CAN_I_BLOCKLOS":        0x00010000,
+		"CAN_I_EXCEPTIONAL":     0x00020000,
+		"CAN_I_MAKERSMARK":      0x00040000,
+		"CAN_I_RETAINCOLOR":     0x00080000,
+		"CAN_I_ENCHANT":         0x00100000,
+		"CAN_I_IMBUE":           0x00200000,
+		"CAN_I_RECYCLE":         0x00400000,
+		"CAN_I_REFORGE":         0x00800000,
+		"CAN_I_FORCEDC":         0x01000000,
+		"CAN_I_DAMAGEABLE":      0x02000000,
+		"CAN_I_BLOCKLOS_HEIGHT": 0x04000000,
+	}, "CAN_I_")
+
+	// charCanFlags are the CHARDEF movement flags, named MT_* in 0.56 scripts
+	// and CAN_C_* in newer ones.
+	charCanFlags = newFlagTable(map[string]int64{
+		"MT_GHOST": 0x00000001, "CAN_C_GHOST": 0x00000001,
+		"MT_SWIM": 0x00000002, "CAN_C_SWIM": 0x00000002,
+		"MT_WALK": 0x00000004, "CAN_C_WALK": 0x00000004,
+		"MT_PASSWALLS": 0x00000008, "CAN_C_PASSWALLS": 0x00000008,
+		"MT_FLY": 0x00000010, "CAN_C_FLY": 0x00000010,
+		"MT_FIRE_IMMUNE": 0x00000020, "CAN_C_FIRE_IMMUNE": 0x00000020,
+		"MT_INDOORS": 0x00000040, "CAN_C_INDOORS": 0x00000040,
+		"MT_HOVER": 0x00000080, "CAN_C_HOVER": 0x00000080,
+		"MT_EQUIP": 0x00000100, "CAN_C_EQUIP": 0x00000100,
+		"MT_USEHANDS": 0x00000200, "CAN_C_USEHANDS": 0x00000200,
+		"MT_MOUNT": 0x00000400, "CAN_C_MOUNT": 0x00000400,
+		"MT_FEMALE": 0x00000800, "CAN_C_FEMALE": 0x00000800,
+		"MT_NONHUMANOID": 0x00001000, "CAN_C_NONHUMANOID": 0x00001000,
+		"MT_RUN": 0x00002000, "CAN_C_RUN": 0x00002000,
+		"CAN_C_DCIGNORELOS":   0x00004000,
+		"CAN_C_DCIGNOREDIST":  0x00008000,
+		"CAN_C_NOBLOCKHEIGHT": 0x00010000,
+		"CAN_C_STATUE":        0x00020000,
+		"CAN_C_NONMOVER":      0x00040000,
+	}, "CAN_C_", "MT_")
+
+	// charFlagSections are the sections whose bare FLAGS= is a character's
+	// statf_* mask; elsewhere FLAGS means region, spell or skill flags.
+	charFlagSections = map[string]bool{"CHARDEF": true, "EVENTS": true}
+)
+
+// checkFlagAssignment validates ATTR=, character FLAGS= and ITEMDEF/CHARDEF
+// CAN= assignments. In trigger and function bodies it also warns about
+// values that replace or mask out every other bit of an existing object;
+// NEW. objects and @Create triggers set up fresh ones.
+func checkFlagAssignment(section sectionState, line, file string, lineNum int) []lintIssue {
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" {
+		return nil
+	}
+	if inVariableNamespace(key) {
+		return nil
+	}
+	segments := strings.Split(key, ".")
+	property := segments[len(segments)-1]
+	var table *flagTable
+	switch {
+	case property == "ATTR":
+		table = itemAttrFlags
+	case property == "FLAGS" && (charFlagSections[section.defType] || segments[0] == "SRC"):
+		table = charStatFlags
+	case property == "CAN" && section.defType == "ITEMDEF":
+		table = itemCanFlags
+	case property == "CAN" && section.defType == "CHARDEF":
+		table = charCanFlags
+	default:
+		return nil
+	}
+	inBody := section.locals != nil && segments[0] != "NEW" && (section.body == nil || !strings.EqualFold(section.body.name, "CREATE"))
+
+	var issues []lintIssue
+	self := strings.Contains(strings.ToUpper(value), "<"+key+">") || strings.Contains(strings.ToUpper(value), "<"+property+">")
+	compact := strings.Join(strings.Fields(value), "")
+	for _, part := range strings.FieldsFunc(compact, func(r rune) bool { return r == '|' || r == '+' }) {
+		if idx := strings.IndexByte(part, '&'); idx >= 0 {
+			mask := strings.Trim(part[idx+1:], "()")
+			if inBody && self && !strings.HasPrefix(mask, "~") {
+				issues = appendWarning(issues, file, lineNum, "FLAGS", fmt.Sprintf("FLAGS: %s=%s keeps only the bits of '%s' and clears every other one; use &~%s to clear just those bits", key, value, mask, mask))
+			}
+			continue
+		}
+		if strings.ContainsAny(part, "<>") {
+			continue
+		}
+		negated := strings.HasPrefix(part, "~")
+		part = strings.Trim(part, "~()")
+		if n, ok := parseSphereInt(part); ok {
+			if extra := int64(uint32(n)) &^ table.mask; extra != 0 && !negated {
+				issues = appendError(issues, file, lineNum, "FLAGS", fmt.Sprintf("FLAGS: %s value %s sets undefined %s bits 0%x", property, part, strings.ToLower(table.prefixes[0])+"*", extra))
+			}
+			continue
+		}
+		upper := strings.ToUpper(part)
+		if table.named(upper) {
+			if _, ok := table.bits[upper]; !ok {
+				issues = appendError(issues, file, lineNum, "FLAGS", fmt.Sprintf("FLAGS: unknown %s constant '%s'", property, part))
+			}
+		}
+	}
+	if inBody && !self && !strings.HasPrefix(value, "|") && value != "0" && len(issues) == 0 {
+		issues = appendWarning(issues, file, lineNum, "FLAGS", fmt.Sprintf("FLAGS: %s=%s replaces every %s bit of the object; write %s=<%s>|%s to add bits", key, value, property, key, key, value))
+	}
+	return issues
+}
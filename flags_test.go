@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestLintFlagAssignments(t *testing.T) {
+	t.Run("Definitions", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_relic]",
+			"ID=0e21",
+			"ATTR=attr_newbie|attr_magic|attr_blesed",
+			"[CHARDEF c_ghost]",
+			"ID=01",
+			"FLAGS=statf_invul|0200000",
+			"[AREADEF a_town]",
+			"FLAGS=region_flag_guarded",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "flags_defs.scp", content)
+		assertHasMessage(t, errs, "FLAGS: unknown ATTR constant 'attr_blesed'")
+		assertHasMessage(t, errs, "FLAGS: FLAGS value 0200000 sets undefined statf_* bits 0200000")
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got %+v", errs)
+		}
+	})
+
+	t.Run("CanFlags", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_door_custom]",
+			"ID=0675",
+			"CAN=can_i_door|can_i_blok",
+			"[ITEMDEF i_crate_custom]",
+			"ID=0e3c",
+			"CAN=0800000|010000000",
+			"[CHARDEF c_wisp]",
+			"ID=03a",
+			"CAN=MT_FLY|MT_PASSWALLS|CAN_C_NONMOVER",
+			"[CHARDEF c_golem]",
+			"ID=02f",
+			"CAN=mt_walk|mt_swimm",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "flags_can.scp", content)
+		assertHasMessage(t, errs, "FLAGS: unknown CAN constant 'can_i_blok'")
+		assertHasMessage(t, errs, "FLAGS: CAN value 010000000 sets undefined can_i_* bits 010000000")
+		assertHasMessage(t, errs, "FLAGS: unknown CAN constant 'mt_swimm'")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 errors, got %+v", errs)
+		}
+	})
+
+	t.Run("Bodies", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_relic]",
+			"ID=0e21",
+			"ON=@Create",
+			"ATTR=attr_magic",
+			"ON=@DClick",
+			"ATTR=04",
+			"ATTR=<ATTR>|04",
+			"ATTR=|attr_newbie",
+			"ATTR=<ATTR> & attr_magic",
+			"ATTR=<ATTR>&~attr_magic",
+			"SRC.FLAGS=<SRC.FLAGS>|statf_hidden",
+			"SRC.FLAGS=statf_hidden",
+			"SERV.NEWITEM i_relic",
+			"NEW.ATTR=attr_newbie",
+			"TAG.ATTR=5",
+			"ATTR=0",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "flags_bodies.scp", content)
+		assertHasMessage(t, errs, "FLAGS: ATTR=04 replaces every ATTR bit of the object; write ATTR=<ATTR>|04 to add bits")
+		assertHasMessage(t, errs, "FLAGS: ATTR=<ATTR> & attr_magic keeps only the bits of 'attr_magic' and clears every other one; use &~attr_magic to clear just those bits")
+		assertHasMessage(t, errs, "FLAGS: SRC.FLAGS=statf_hidden replaces every FLAGS bit of the object")
+		if len(errs) != 3 || errs[0].line != 6 || errs[1].line != 9 || errs[2].line != 12 {
+			t.Fatalf("expected warnings on lines 6, 9 and 12, got %+v", errs)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runFormat implements "sphere-lint format": it strips trailing whitespace
+// and evens out the indentation of every script file; -casing also rewrites
+// references to the spelling of their definition. With -check it only
+// lists the files that would change and exits 1 if there are any.
+func runFormat(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint format", flag.ExitOnError)
+	check := flags.Bool("check", false, "list files that need formatting without rewriting them")
+	casing := flags.Bool("casing", false, "also rewrite i_/c_/f_/... references to the letter case of their definition")
+	flags.Parse(args)
+
+	var spellings map[string]string
+	if *casing {
+		spellings = collectSpellings(scriptsRoot)
+	}
+
+	changed := 0
+	failed := false
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			printError(lintIssue{file: toRelative(path), line: 1, kind: "CRITICAL", msg: err.Error()})
+			failed = true
+			return
+		}
+		formatted := formatScript(string(data))
+		if *casing {
+			formatted = fixReferenceCasing(formatted, spellings)
+		}
+		if formatted == string(data) {
+			return
+		}
+		changed++
+		if *check {
+			fmt.Printf("needs formatting: %s\n", toRelative(path))
+			return
+		}
+		if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+			printError(lintIssue{file: toRelative(path), line: 1, kind: "CRITICAL", msg: err.Error()})
+			failed = true
+			return
+		}
+		fmt.Printf("formatted: %s\n", toRelative(path))
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	fmt.Println("---------------------------------------------")
+	if *check {
+		fmt.Printf("Files needing formatting: %d\n", changed)
+	} else {
+		fmt.Printf("Files formatted: %d\n", changed)
+	}
+	if failed || len(walkIssues) > 0 || (*check && changed > 0) {
+		return 1
+	}
+	return 0
+}
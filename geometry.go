@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type mapSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type mapRect struct {
+	x1, y1, x2, y2 int
+	plane          int
+	line           int
+}
+
+type mapPoint struct {
+	x, y, z int
+	plane   int
+	line    int
+}
+
+// regionGeometry collects the RECT=, P= and GROUP= lines of one
+// AREADEF/ROOMDEF section so they can be cross-checked once the section ends.
+type regionGeometry struct {
+	typ    string
+	id     string
+	file   string
+	line   int
+	group  string
+	rects  []mapRect
+	points []mapPoint
+	// parent is the closest AREADEF above a ROOMDEF in the same file.
+	parent *regionGeometry
+}
+
+func newRegionGeometry(typ, args, file string, lineNum int) *regionGeometry {
+	return &regionGeometry{typ: typ, id: strings.ToUpper(firstField(args)), file: file, line: lineNum}
+}
+
+// regionIndex keeps every AREADEF and ROOMDEF so rooms can be matched with
+// their area once all files are read.
+type regionIndex struct {
+	areas []*regionGeometry
+	rooms []*regionGeometry
+}
+
+// add registers a region; a ROOMDEF takes the last AREADEF of its file as
+// parent.
+func (idx *regionIndex) add(g *regionGeometry) {
+	if g.typ == "AREADEF" {
+		idx.areas = append(idx.areas, g)
+		return
+	}
+	for i := len(idx.areas) - 1; i >= 0; i-- {
+		if idx.areas[i].file == g.file {
+			g.parent = idx.areas[i]
+			break
+		}
+	}
+	idx.rooms = append(idx.rooms, g)
+}
+
+// findRoomsOutside reports ROOMDEF rects that are not inside their area: the
+// AREADEFs sharing the room's GROUP, or else the closest AREADEF above it in
+// the same file. A rect that misses the area entirely never applies; rects
+// on a map plane the area does not cover belong to some other area.
+func (idx *regionIndex) findRoomsOutside() []lintIssue {
+	var issues []lintIssue
+	for _, room := range idx.rooms {
+		parents := idx.groupAreas(room.group)
+		if len(parents) == 0 && room.parent != nil {
+			parents = []*regionGeometry{room.parent}
+		}
+		if len(parents) == 0 {
+			continue
+		}
+		for _, r := range room.rects {
+			issues = appendRoomRectIssue(issues, room, r, parents)
+		}
+	}
+	return issues
+}
+
+func (idx *regionIndex) groupAreas(group string) []*regionGeometry {
+	if group == "" {
+		return nil
+	}
+	var areas []*regionGeometry
+	for _, area := range idx.areas {
+		if strings.EqualFold(area.group, group) && len(area.rects) > 0 {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+func appendRoomRectIssue(issues []lintIssue, room *regionGeometry, r mapRect, parents []*regionGeometry) []lintIssue {
+	var overlap *regionGeometry
+	samePlane := false
+	for _, area := range parents {
+		for _, a := range area.rects {
+			if a.encloses(r) {
+				return issues
+			}
+			samePlane = samePlane || a.plane == r.plane
+			if overlap == nil && a.overlaps(r) {
+				overlap = area
+			}
+		}
+	}
+	if !samePlane {
+		return issues
+	}
+	if overlap != nil {
+		return appendWarning(issues, room.file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: RECT of ROOMDEF %s extends past AREADEF %s (%s:%d); the part outside never applies", room.id, overlap.id, overlap.file, overlap.line))
+	}
+	area := parents[0]
+	return appendError(issues, room.file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: RECT of ROOMDEF %s lies outside AREADEF %s (%s:%d); the room never applies", room.id, area.id, area.file, area.line))
+}
+
+// addLine records the RECT= and P= values of the section. Format and bounds
+// problems are reported by validateCoordinateLine.
+func (g *regionGeometry) addLine(line string, lineNum int) {
+	key, value, ok := splitAssignment(line)
+	if !ok {
+		return
+	}
+	switch key {
+	case "GROUP":
+		g.group = value
+	case "RECT":
+		if rect, msg := parseMapRect(value); rect != nil && msg == "" {
+			rect.line = lineNum
+			g.rects = append(g.rects, *rect)
+		}
+	case "P":
+		if point, msg := parseMapPoint("P", value); point != nil && msg == "" {
+			point.line = lineNum
+			g.points = append(g.points, *point)
+		}
+	}
+}
+
+// finish reports P= points that fall outside every RECT of the section;
+// such regions never contain their own anchor and silently never trigger.
+func (g *regionGeometry) finish(file string) []lintIssue {
+	if g == nil || len(g.rects) == 0 {
+		return nil
+	}
+	var issues []lintIssue
+	for _, p := range g.points {
+		inside := false
+		for _, r := range g.rects {
+			if r.contains(p) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			issues = appendError(issues, file, p.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: P=%d,%d is not inside any RECT of %s %s", p.x, p.y, g.typ, g.id))
+		}
+	}
+	return issues
+}
+
+func (r mapRect) contains(p mapPoint) bool {
+	return p.plane == r.plane && p.x >= r.x1 && p.x <= r.x2 && p.y >= r.y1 && p.y <= r.y2
+}
+
+func (r mapRect) encloses(o mapRect) bool {
+	return o.plane == r.plane && o.x1 >= r.x1 && o.y1 >= r.y1 && o.x2 <= r.x2 && o.y2 <= r.y2
+}
+
+func (r mapRect) overlaps(o mapRect) bool {
+	return o.plane == r.plane && o.x1 <= r.x2 && o.x2 >= r.x1 && o.y1 <= r.y2 && o.y2 >= r.y1
+}
+
+// validateCoordinateLine checks coordinate literals in RECT=, P=, MOREP= and
+// GO lines against the configured map planes. P and MOREP may also be set
+// with a space (SRC.P 1500,1600).
+func validateCoordinateLine(line, file string, lineNum int) []lintIssue {
+	if key, value, ok := splitAssignment(line); ok {
+		if inVariableNamespace(key) {
+			return nil
+		}
+		switch lastPathSegment(key) {
+		case "RECT":
+			rect, msg := parseMapRect(value)
+			if msg != "" {
+				return appendError(nil, file, lineNum, "GEOMETRY", msg)
+			}
+			if rect == nil {
+				return nil
+			}
+			rect.line = lineNum
+			return validateMapRect(nil, *rect, file)
+		case "P", "MOREP":
+			return validatePointValue(lastPathSegment(key), value, file, lineNum)
+		}
+		return nil
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil
+	}
+	key := strings.ToUpper(fields[0])
+	if inVariableNamespace(key) {
+		return nil
+	}
+	value := strings.Join(fields[1:], "")
+	switch verb := lastPathSegment(key); verb {
+	case "P", "MOREP":
+		return validatePointValue(verb, value, file, lineNum)
+	case "GO":
+		if !strings.Contains(value, ",") || value[0] < '0' || value[0] > '9' {
+			return nil
+		}
+		return validatePointValue("GO destination", value, file, lineNum)
+	}
+	return nil
+}
+
+// validatePointValue parses a coordinate value and checks it against the
+// configured map planes.
+func validatePointValue(label, value, file string, lineNum int) []lintIssue {
+	point, msg := parseMapPoint(label, value)
+	if msg != "" {
+		return appendError(nil, file, lineNum, "GEOMETRY", msg)
+	}
+	if point == nil {
+		return nil
+	}
+	point.line = lineNum
+	return validateMapPoint(nil, *point, file)
+}
+
+func validateMapRect(issues []lintIssue, r mapRect, file string) []lintIssue {
+	if r.x1 > r.x2 {
+		issues = appendError(issues, file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: RECT x1 > x2 (%d > %d)", r.x1, r.x2))
+	}
+	if r.y1 > r.y2 {
+		issues = appendError(issues, file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: RECT y1 > y2 (%d > %d)", r.y1, r.y2))
+	}
+	size, ok := config.Maps[r.plane]
+	if !ok {
+		return appendError(issues, file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: unknown map plane %d", r.plane))
+	}
+	if r.x1 < 0 || r.y1 < 0 || r.x2 > size.Width || r.y2 > size.Height {
+		issues = appendError(issues, file, r.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: RECT outside map %d bounds (%dx%d)", r.plane, size.Width, size.Height))
+	}
+	return issues
+}
+
+func validateMapPoint(issues []lintIssue, p mapPoint, file string) []lintIssue {
+	size, ok := config.Maps[p.plane]
+	if !ok {
+		return appendError(issues, file, p.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: unknown map plane %d", p.plane))
+	}
+	if p.x < 0 || p.y < 0 || p.x >= size.Width || p.y >= size.Height {
+		issues = appendError(issues, file, p.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: P=%d,%d outside map %d bounds (%dx%d)", p.x, p.y, p.plane, size.Width, size.Height))
+	}
+	if p.z < -128 || p.z > 127 {
+		issues = appendError(issues, file, p.line, "GEOMETRY", fmt.Sprintf("GEOMETRY: P=%d,%d,%d has z outside -128..127", p.x, p.y, p.z))
+	}
+	return issues
+}
+
+// parseMapRect parses "x1,y1,x2,y2[,map]". A nil rect with an empty message
+// means the value is dynamic and cannot be checked statically.
+func parseMapRect(value string) (*mapRect, string) {
+	values, dynamic, ok := parseCoordinateList(value)
+	if dynamic {
+		return nil, ""
+	}
+	if !ok || len(values) < 4 || len(values) > 5 {
+		return nil, fmt.Sprintf("GEOMETRY: malformed RECT '%s' (expected x1,y1,x2,y2[,map])", value)
+	}
+	rect := &mapRect{x1: values[0], y1: values[1], x2: values[2], y2: values[3]}
+	if len(values) == 5 {
+		rect.plane = values[4]
+	}
+	return rect, ""
+}
+
+// parseMapPoint parses "x,y[,z[,map]]" using the same conventions as
+// parseMapRect; label names the value in the error message.
+func parseMapPoint(label, value string) (*mapPoint, string) {
+	values, dynamic, ok := parseCoordinateList(value)
+	if dynamic {
+		return nil, ""
+	}
+	if !ok || len(values) < 2 || len(values) > 4 {
+		return nil, fmt.Sprintf("GEOMETRY: malformed %s '%s' (expected x,y[,z[,map]])", label, value)
+	}
+	point := &mapPoint{x: values[0], y: values[1]}
+	if len(values) > 2 {
+		point.z = values[2]
+	}
+	if len(values) > 3 {
+		point.plane = values[3]
+	}
+	return point, ""
+}
+
+func parseCoordinateList(value string) ([]int, bool, bool) {
+	value = strings.TrimSpace(value)
+	if strings.ContainsAny(value, "<>") {
+		return nil, true, false
+	}
+	if value == "" {
+		return nil, false, false
+	}
+	parts := strings.Split(value, ",")
+	values := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, ok := parseSphereInt(strings.TrimSpace(part))
+		if !ok {
+			return nil, false, false
+		}
+		values = append(values, n)
+	}
+	return values, false, true
+}
+
+// parseSphereInt parses a numeric literal the way the server does: a leading
+// 0 (or 0x) marks a hexadecimal value, anything else is decimal.
+func parseSphereInt(value string) (int, bool) {
+	if value == "" {
+		return 0, false
+	}
+	negative := false
+	if value[0] == '-' || value[0] == '+' {
+		negative = value[0] == '-'
+		value = value[1:]
+	}
+	if value == "" {
+		return 0, false
+	}
+	base := 10
+	if len(value) > 1 && value[0] == '0' {
+		base = 16
+		value = value[1:]
+		if value[0] == 'x' || value[0] == 'X' {
+			value = value[1:]
+		}
+	}
+	n, err := strconv.ParseInt(value, base, 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		n = -n
+	}
+	return int(n), true
+}
+
+func lastPathSegment(key string) string {
+	if idx := strings.LastIndexByte(key, '.'); idx >= 0 {
+		return key[idx+1:]
+	}
+	return key
+}
+
+// inVariableNamespace reports whether an upper-cased key names a user
+// variable (TAG.P, LOCAL.RECT, SRC.VAR.P) rather than a property.
+func inVariableNamespace(key string) bool {
+	segments := strings.Split(key, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		if variableNamespaces[segment] {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAssignment splits "KEY=value" into its upper-cased key and trimmed
+// value. Lines without '=' or with an empty key are not assignments.
+func splitAssignment(line string) (string, string, bool) {
+	idx := strings.IndexByte(line, '=')
+	if idx <= 0 {
+		return "", "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+	return strings.ToUpper(key), strings.TrimSpace(line[idx+1:]), true
+}
@@ -0,0 +1,225 @@
+package main
+
+import "testing"
+
+func TestLintRegionGeometry(t *testing.T) {
+	t.Run("ValidArea", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_britain]",
+			"NAME=Britain",
+			"P=1495,1629,10",
+			"RECT=1416,1498,1740,1777",
+			"RECT=1500,1400,1600,1497,0",
+			"[ROOMDEF r_dungeon_room]",
+			"P=5200,100,0,1",
+			"RECT=5120,0,5300,200,1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "area_valid.scp", content), "valid area")
+	})
+
+	t.Run("ReversedRect", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_test]",
+			"RECT=1740,1777,1416,1498",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "area_reversed.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: RECT x1 > x2 (1740 > 1416)")
+		assertHasMessage(t, errs, "GEOMETRY: RECT y1 > y2 (1777 > 1498)")
+	})
+
+	t.Run("OutOfBounds", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_test]",
+			"P=100,100",
+			"RECT=0,0,7000,200",
+			"RECT=0,0,100,100,9",
+			"[ROOMDEF r_test]",
+			"P=3000,100,0,2",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "area_bounds.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: RECT outside map 0 bounds (6144x4096)")
+		assertHasMessage(t, errs, "GEOMETRY: unknown map plane 9")
+		assertHasMessage(t, errs, "GEOMETRY: P=3000,100 outside map 2 bounds (2304x1600)")
+	})
+
+	t.Run("PointOutsideRects", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_test]",
+			"P=2000,2000",
+			"RECT=1416,1498,1740,1777",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "area_point_outside.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: P=2000,2000 is not inside any RECT of AREADEF A_TEST")
+	})
+
+	t.Run("MalformedValues", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_test]",
+			"P=100",
+			"RECT=1,2,3",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "area_malformed.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: malformed P '100'")
+		assertHasMessage(t, errs, "GEOMETRY: malformed RECT '1,2,3'")
+	})
+}
+
+func TestLintMapBounds(t *testing.T) {
+	t.Run("CoordinatesOutsideSections", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_moongate]",
+			"ON=@Step",
+			"SRC.GO 7000,100,0",
+			"SRC.P=100,5000",
+			"MOREP=1495,1629,10,7",
+			"SRC.GO britain",
+			"SRC.GO <MOREP>",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "map_bounds.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: P=7000,100 outside map 0 bounds (6144x4096)")
+		assertHasMessage(t, errs, "GEOMETRY: P=100,5000 outside map 0 bounds (6144x4096)")
+		assertHasMessage(t, errs, "GEOMETRY: unknown map plane 7")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 errors, got %d", len(errs))
+		}
+	})
+
+	t.Run("ConfiguredMaps", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Maps = map[int]mapSize{0: {Width: 7168, Height: 4096}, 7: {Width: 1000, Height: 1000}}
+		})
+		content := joinLines(
+			"[FUNCTION f_travel]",
+			"SRC.GO 7000,100,0",
+			"SRC.GO 500,500,0,7",
+			"SRC.GO 500,500,0,1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "map_config.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: unknown map plane 1")
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %d", len(errs))
+		}
+	})
+}
+
+func TestLintPointValues(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_place]",
+		"SRC.P 1500,1600,0",
+		"P 1500,1600,0,9",
+		"NEW.P=1500",
+		"SRC.P=1500,16OO",
+		"P=1500,1600,200",
+		"MOREP 1,2,3,4,5",
+		"SRC.P=<SRC.TAG.HOME>",
+		"TAG.P=hello",
+		"VAR.P=home",
+		"LOCAL.RECT=abc",
+		"SAY <LOCAL.RECT>",
+		"SRC.TAG.MOREP home",
+		"VAR.GO hello,there",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "points.scp", content)
+	assertHasMessage(t, errs, "GEOMETRY: unknown map plane 9")
+	assertHasMessage(t, errs, "GEOMETRY: malformed P '1500' (expected x,y[,z[,map]])")
+	assertHasMessage(t, errs, "GEOMETRY: malformed P '1500,16OO'")
+	assertHasMessage(t, errs, "GEOMETRY: P=1500,1600,200 has z outside -128..127")
+	assertHasMessage(t, errs, "GEOMETRY: malformed MOREP '1,2,3,4,5'")
+	if len(errs) != 5 || errs[0].line != 3 || errs[4].line != 7 {
+		t.Fatalf("expected errors on lines 3-7, got %+v", errs)
+	}
+}
+
+func TestLintRoomContainment(t *testing.T) {
+	t.Run("ByProximity", func(t *testing.T) {
+		content := joinLines(
+			"[AREADEF a_britain]",
+			"RECT=1416,1498,1740,1777",
+			"[ROOMDEF r_castle]",
+			"RECT=1500,1500,1600,1600",
+			"[ROOMDEF r_docks]",
+			"RECT=1700,1700,1800,1800",
+			"[ROOMDEF r_moonglow]",
+			"RECT=4400,1000,4500,1100",
+			"[ROOMDEF r_dungeon]",
+			"RECT=5120,0,5300,200,1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "rooms.scp", content)
+		assertHasMessage(t, errs, "GEOMETRY: RECT of ROOMDEF R_DOCKS extends past AREADEF A_BRITAIN (rooms.scp:1); the part outside never applies")
+		assertHasMessage(t, errs, "GEOMETRY: RECT of ROOMDEF R_MOONGLOW lies outside AREADEF A_BRITAIN (rooms.scp:1); the room never applies")
+		if len(errs) != 2 || errs[0].line != 6 || errs[0].severity != severityWarning || errs[1].line != 8 || errs[1].severity != severityError {
+			t.Fatalf("expected a warning at line 6 and an error at line 8, got %+v", errs)
+		}
+	})
+
+	t.Run("ByGroup", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		areas := writeTempFile(t, dir, "areas.scp", joinLines(
+			"[AREADEF a_minoc]",
+			"GROUP=Minoc",
+			"RECT=2400,400,2600,600",
+			"[EOF]",
+		))
+		rooms := writeTempFile(t, dir, "rooms.scp", joinLines(
+			"[AREADEF a_vesper]",
+			"RECT=2800,600,3000,1000",
+			"[ROOMDEF r_minoc_bank]",
+			"GROUP=minoc",
+			"RECT=2450,450,2500,500",
+			"[ROOMDEF r_minoc_mine]",
+			"GROUP=minoc",
+			"RECT=2800,700,2850,750",
+			"[EOF]",
+		))
+		index := newLintIndex()
+		errs := lintScriptFile(areas, index)
+		errs = append(errs, lintScriptFile(rooms, index)...)
+		errs = append(errs, lintIndexIssues(index)...)
+		assertHasMessage(t, errs, "GEOMETRY: RECT of ROOMDEF R_MINOC_MINE lies outside AREADEF A_MINOC (areas.scp:1)")
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %+v", errs)
+		}
+	})
+}
+
+func TestParseSphereInt(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{in: "0", want: 0, ok: true},
+		{in: "1495", want: 1495, ok: true},
+		{in: "-10", want: -10, ok: true},
+		{in: "010", want: 16, ok: true},
+		{in: "0x1f", want: 31, ok: true},
+		{in: "0eed", want: 0xeed, ok: true},
+		{in: "12a", ok: false},
+		{in: "", ok: false},
+	}
+
+	for _, tc := range cases {
+		got, ok := parseSphereInt(tc.in)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Fatalf("parseSphereInt(%q) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// graphFilter selects the edges of the reference graph. Empty fields match
+// everything.
+type graphFilter struct {
+	defType string
+	prefix  string
+	file    string
+}
+
+// matchesNode reports whether a definition key ("ITEMDEF I_SWORD") passes
+// the type and prefix filters.
+func (f graphFilter) matchesNode(key string) bool {
+	defType, id, _ := strings.Cut(key, " ")
+	if f.defType != "" && defType != f.defType {
+		return false
+	}
+	return f.prefix == "" || strings.HasPrefix(id, f.prefix)
+}
+
+// referenceTarget returns the definition key ref resolves to, or "" when it
+// names nothing defined.
+func referenceTarget(ref referenceUse, defs map[string]definitionLocation) string {
+	for _, defType := range ref.defTypes {
+		key := defType + " " + ref.id
+		if _, ok := defs[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// referenceEdges returns, for every definition, the definitions its lines
+// reference, each once and sorted. An edge is kept when either end passes
+// the filter and the reference sits in a file under filter.file.
+func referenceEdges(index *lintIndex, filter graphFilter) map[string][]string {
+	edges := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, ref := range index.references {
+		if ref.from == "" || (filter.file != "" && !strings.HasPrefix(ref.file, filter.file)) {
+			continue
+		}
+		target := referenceTarget(ref, index.defs)
+		if target == "" || target == ref.from {
+			continue
+		}
+		if !filter.matchesNode(ref.from) && !filter.matchesNode(target) {
+			continue
+		}
+		if edge := ref.from + "\x00" + target; !seen[edge] {
+			seen[edge] = true
+			edges[ref.from] = append(edges[ref.from], target)
+		}
+	}
+	for _, targets := range edges {
+		sort.Strings(targets)
+	}
+	return edges
+}
+
+// writeReferenceDOT prints the reference graph in Graphviz format, with one
+// node per definition and one edge per referencing pair.
+func writeReferenceDOT(w io.Writer, edges map[string][]string) {
+	nodes := make(map[string]bool)
+	sources := make([]string, 0, len(edges))
+	for source, targets := range edges {
+		sources = append(sources, source)
+		nodes[source] = true
+		for _, target := range targets {
+			nodes[target] = true
+		}
+	}
+	sort.Strings(sources)
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "digraph references {")
+	for _, name := range names {
+		defType, id, _ := strings.Cut(name, " ")
+		fmt.Fprintf(w, "  %s [label=%s];\n", strconv.Quote(name), strconv.Quote(strings.ToLower(id)+"\n"+defType))
+	}
+	for _, source := range sources {
+		for _, target := range edges[source] {
+			fmt.Fprintf(w, "  %s -> %s;\n", strconv.Quote(source), strconv.Quote(target))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// runGraph implements "sphere-lint graph": it prints which definitions
+// reference which others as a Graphviz graph, to show how script modules
+// are coupled.
+func runGraph(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint graph", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	defType := flags.String("type", "", "only keep edges from or to definitions of this type, such as ITEMDEF")
+	prefix := flags.String("prefix", "", "only keep edges from or to definitions whose ID starts with this prefix")
+	file := flags.String("file", "", "only keep references made in files under this path")
+	outPath := flags.String("out", "", "write the graph to this file instead of stdout")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer f.Close()
+		out = f
+	}
+	filter := graphFilter{defType: strings.ToUpper(*defType), prefix: strings.ToUpper(*prefix)}
+	if *file != "" {
+		filter.file = filepath.ToSlash(filepath.Clean(*file))
+	}
+	writeReferenceDOT(out, referenceEdges(index, filter))
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
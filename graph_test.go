@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunGraph(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "items.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"ID=0f61",
+		"EVENTS=e_burn",
+		"ON=@Equip",
+		"SRC.NEWITEM i_ember",
+		"[ITEMDEF i_ember]",
+		"ID=0f62",
+		"[EVENTS e_burn]",
+		"ON=@Timer",
+		"f_burn",
+		"[EOF]",
+	))
+	if err := os.Mkdir(filepath.Join(dir, "funcs"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeTempFile(t, dir, "funcs/burn.scp", joinLines(
+		"[FUNCTION f_burn]",
+		"SRC.NEWITEM i_ember",
+		"f_burn",
+		"[EOF]",
+	))
+
+	outPath := filepath.Join(t.TempDir(), "graph.dot")
+	if code := runGraph([]string{"-out", outPath}); code != 0 {
+		t.Fatalf("expected graph to succeed, got %d", code)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read graph: %v", err)
+	}
+	graph := string(data)
+	for _, want := range []string{
+		`"ITEMDEF I_SWORD_FIRE" -> "EVENTS E_BURN";`,
+		`"ITEMDEF I_SWORD_FIRE" -> "ITEMDEF I_EMBER";`,
+		`"EVENTS E_BURN" -> "FUNCTION F_BURN";`,
+		`"FUNCTION F_BURN" -> "ITEMDEF I_EMBER";`,
+		`"ITEMDEF I_EMBER" [label="i_ember\nITEMDEF"];`,
+	} {
+		if !strings.Contains(graph, want) {
+			t.Fatalf("expected %s in the graph:\n%s", want, graph)
+		}
+	}
+	if strings.Contains(graph, `"FUNCTION F_BURN" -> "FUNCTION F_BURN"`) {
+		t.Fatalf("expected no self edges:\n%s", graph)
+	}
+
+	index := newLintIndex()
+	walkScripts(dir, func(path string) { lintScriptFile(path, index) })
+	edges := referenceEdges(index, graphFilter{defType: "FUNCTION"})
+	if len(edges) != 2 || len(edges["EVENTS E_BURN"]) != 1 || len(edges["FUNCTION F_BURN"]) != 1 {
+		t.Fatalf("expected only the edges from or to f_burn, got %+v", edges)
+	}
+	edges = referenceEdges(index, graphFilter{file: "funcs"})
+	if len(edges) != 1 || edges["FUNCTION F_BURN"][0] != "ITEMDEF I_EMBER" {
+		t.Fatalf("expected only the references made under funcs/, got %+v", edges)
+	}
+	edges = referenceEdges(index, graphFilter{prefix: "I_SWORD"})
+	if len(edges) != 1 || len(edges["ITEMDEF I_SWORD_FIRE"]) != 2 {
+		t.Fatalf("expected only the edges of i_sword_fire, got %+v", edges)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// historyVersion is bumped whenever the layout of historyRun changes.
+const historyVersion = 1
+
+// historyRun is one lint run as -save-history stores it.
+type historyRun struct {
+	Version int            `json:"version"`
+	Time    time.Time      `json:"time"`
+	Rules   map[string]int `json:"rules"`
+	Issues  []historyIssue `json:"issues"`
+}
+
+// historyIssue identifies an issue across runs; lines are left out so code
+// moving around does not count as fixing and reintroducing it.
+type historyIssue struct {
+	File     string `json:"file"`
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+func newHistoryRun(issues []lintIssue, now time.Time) historyRun {
+	run := historyRun{Version: historyVersion, Time: now.UTC(), Rules: make(map[string]int), Issues: []historyIssue{}}
+	for _, issue := range issues {
+		severity := "error"
+		if issue.severity == severityWarning {
+			severity = "warning"
+		}
+		run.Rules[issue.kind]++
+		run.Issues = append(run.Issues, historyIssue{File: issue.file, Kind: issue.kind, Message: issue.msg, Severity: severity})
+	}
+	return run
+}
+
+// lintIssues turns the stored issues back into issues for comparison.
+func (r historyRun) lintIssues() []lintIssue {
+	issues := make([]lintIssue, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		severity := severityError
+		if issue.Severity == "warning" {
+			severity = severityWarning
+		}
+		issues = append(issues, lintIssue{file: issue.File, kind: issue.Kind, msg: issue.Message, severity: severity})
+	}
+	return issues
+}
+
+// saveHistory writes run to a new file named after its time in dir and
+// returns its path.
+func saveHistory(dir string, run historyRun) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, run.Time.Format("20060102T150405Z")+".json")
+	return path, os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// readHistory loads a run saved by saveHistory.
+func readHistory(path string) (historyRun, error) {
+	var run historyRun
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return run, err
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return run, fmt.Errorf("%s: %w", path, err)
+	}
+	if run.Version != historyVersion {
+		return run, fmt.Errorf("%s: unsupported history version %d (want %d)", path, run.Version, historyVersion)
+	}
+	return run, nil
+}
+
+// writeHistoryDelta prints how current differs from previous: the issues
+// that are new, fixed or unchanged, and the count of every rule that moved.
+func writeHistoryDelta(w io.Writer, previous, current historyRun) {
+	before, after := previous.lintIssues(), current.lintIssues()
+	added := newIssues(before, after)
+	fixed := newIssues(after, before)
+	fmt.Fprintf(w, "Compared to the run of %s:\n", previous.Time.Format(time.RFC3339))
+	fmt.Fprintf(w, "New issues: %d, fixed: %d, unchanged: %d\n", len(added), len(fixed), len(after)-len(added))
+
+	rules := make(map[string]bool)
+	for rule := range previous.Rules {
+		rules[rule] = true
+	}
+	for rule := range current.Rules {
+		rules[rule] = true
+	}
+	names := make([]string, 0, len(rules))
+	for rule := range rules {
+		if previous.Rules[rule] != current.Rules[rule] {
+			names = append(names, rule)
+		}
+	}
+	sort.Strings(names)
+	for _, rule := range names {
+		fmt.Fprintf(w, "  %-12s %d -> %d (%+d)\n", rule, previous.Rules[rule], current.Rules[rule], current.Rules[rule]-previous.Rules[rule])
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryDelta(t *testing.T) {
+	dir := t.TempDir()
+	earlier := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	previous := newHistoryRun([]lintIssue{
+		{file: "a.scp", line: 3, kind: "UNDECLARED", msg: "UNDECLARED: 'I_GOLD' not defined as ITEMDEF"},
+		{file: "a.scp", line: 9, kind: "LOGIC", msg: "LOGIC: trigger @Create has no statements.", severity: severityWarning},
+		{file: "b.scp", line: 1, kind: "UNDECLARED", msg: "UNDECLARED: 'C_ORC' not defined as CHARDEF"},
+	}, earlier)
+	path, err := saveHistory(filepath.Join(dir, "results"), previous)
+	if err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+	if filepath.Base(path) != "20261001T120000Z.json" {
+		t.Fatalf("expected the file to be named after the run time, got %s", path)
+	}
+	loaded, err := readHistory(path)
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	if loaded.Rules["UNDECLARED"] != 2 || len(loaded.Issues) != 3 || loaded.Issues[1].Severity != "warning" {
+		t.Fatalf("unexpected saved run %+v", loaded)
+	}
+
+	current := newHistoryRun([]lintIssue{
+		{file: "a.scp", line: 5, kind: "UNDECLARED", msg: "UNDECLARED: 'I_GOLD' not defined as ITEMDEF"},
+		{file: "a.scp", line: 12, kind: "LOGIC", msg: "LOGIC: trigger @Create has no statements.", severity: severityWarning},
+		{file: "a.scp", line: 20, kind: "LOGIC", msg: "LOGIC: trigger @DClick has no statements.", severity: severityWarning},
+	}, earlier.Add(time.Hour))
+	var out bytes.Buffer
+	writeHistoryDelta(&out, loaded, current)
+	want := joinLines(
+		"Compared to the run of 2026-10-01T12:00:00Z:",
+		"New issues: 1, fixed: 1, unchanged: 2",
+		"  LOGIC        1 -> 2 (+1)",
+		"  UNDECLARED   2 -> 1 (-1)",
+	)
+	if out.String() != want {
+		t.Fatalf("unexpected delta:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := readHistory(path); err == nil {
+		t.Fatal("expected an unknown history version to be rejected")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// indexSchema creates the tables of the -index-db database. IDs compare
+// without case, as they do on the server.
+const indexSchema = `DROP TABLE IF EXISTS definitions;
+DROP TABLE IF EXISTS defnames;
+DROP TABLE IF EXISTS refs;
+DROP TABLE IF EXISTS issues;
+CREATE TABLE definitions (type TEXT NOT NULL, id TEXT NOT NULL COLLATE NOCASE, file TEXT NOT NULL, line INTEGER NOT NULL);
+CREATE TABLE defnames (name TEXT NOT NULL COLLATE NOCASE, file TEXT NOT NULL, line INTEGER NOT NULL);
+CREATE TABLE refs (id TEXT NOT NULL COLLATE NOCASE, text TEXT NOT NULL, def_types TEXT NOT NULL, file TEXT NOT NULL, line INTEGER NOT NULL);
+CREATE TABLE issues (file TEXT NOT NULL, line INTEGER NOT NULL, col INTEGER NOT NULL, severity TEXT NOT NULL, kind TEXT NOT NULL, message TEXT NOT NULL);
+CREATE INDEX definitions_id ON definitions (id);
+CREATE INDEX definitions_file ON definitions (file);
+CREATE INDEX defnames_name ON defnames (name);
+CREATE INDEX refs_id ON refs (id);
+CREATE INDEX refs_file ON refs (file);
+CREATE INDEX issues_file ON issues (file);
+CREATE INDEX issues_kind ON issues (kind);
+`
+
+// sqlQuote quotes s as an SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// writeIndexSQL writes the definitions, DEFNAMEs, references and issues of
+// a run as an SQL script for sqlite3.
+func writeIndexSQL(w io.Writer, index *lintIndex, issues []lintIssue) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "BEGIN;")
+	fmt.Fprint(out, indexSchema)
+
+	keys := make([]string, 0, len(index.defs))
+	for key := range index.defs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		defType, id, _ := strings.Cut(key, " ")
+		loc := index.defs[key]
+		fmt.Fprintf(out, "INSERT INTO definitions VALUES (%s, %s, %s, %d);\n", sqlQuote(defType), sqlQuote(id), sqlQuote(loc.file), loc.line)
+	}
+
+	names := make([]string, 0, len(index.defnames))
+	for name := range index.defnames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		loc := index.defnames[name]
+		fmt.Fprintf(out, "INSERT INTO defnames VALUES (%s, %s, %d);\n", sqlQuote(name), sqlQuote(loc.file), loc.line)
+	}
+
+	for _, ref := range index.references {
+		text := ref.text
+		if text == "" {
+			text = ref.id
+		}
+		fmt.Fprintf(out, "INSERT INTO refs VALUES (%s, %s, %s, %s, %d);\n", sqlQuote(ref.id), sqlQuote(text), sqlQuote(strings.Join(ref.defTypes, ",")), sqlQuote(ref.file), ref.line)
+	}
+
+	for _, issue := range issues {
+		severity := "error"
+		if issue.severity == severityWarning {
+			severity = "warning"
+		}
+		fmt.Fprintf(out, "INSERT INTO issues VALUES (%s, %d, %d, %s, %s, %s);\n", sqlQuote(issue.file), issue.line, issue.col, sqlQuote(severity), sqlQuote(issue.kind), sqlQuote(issue.msg))
+	}
+	fmt.Fprintln(out, "COMMIT;")
+	return out.Flush()
+}
+
+// writeIndexDB stores a run in the SQLite database at path through the
+// sqlite3 command-line tool, replacing the tables of an earlier run.
+func writeIndexDB(path string, index *lintIndex, issues []lintIssue) error {
+	sqlite, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return fmt.Errorf("sqlite3 is not installed or not in PATH")
+	}
+	var script bytes.Buffer
+	if err := writeIndexSQL(&script, index, issues); err != nil {
+		return err
+	}
+	cmd := exec.Command(sqlite, "-bail", path)
+	cmd.Stdin = &script
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sqlite3: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteIndexSQL(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, dir, "potions.scp", joinLines(
+		"[ITEMDEF i_potion_heal]",
+		"ID=0f0c",
+		"TYPE=t_potion",
+		"[FUNCTION f_brew]",
+		"SRC.NEWITEM i_potion_heal",
+		"SRC.SYSMESSAGE It's ready",
+		"SRC.NEWITEM i_potion_cure",
+		"[EOF]",
+	)), index)
+	errs = append(errs, lintIndexIssues(index)...)
+
+	var script bytes.Buffer
+	if err := writeIndexSQL(&script, index, errs); err != nil {
+		t.Fatalf("write sql: %v", err)
+	}
+	sql := script.String()
+	for _, want := range []string{
+		"INSERT INTO definitions VALUES ('ITEMDEF', 'I_POTION_HEAL', 'potions.scp', 1);",
+		"INSERT INTO refs VALUES ('I_POTION_HEAL', 'i_potion_heal',",
+		"'potions.scp', 7, 0, 'error', 'UNDECLARED',",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("expected %q in the script:\n%s", want, sql)
+		}
+	}
+
+	sqlite, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	dbPath := filepath.Join(t.TempDir(), "symbols.db")
+	if err := writeIndexDB(dbPath, index, errs); err != nil {
+		t.Fatalf("write db: %v", err)
+	}
+	// A second run replaces the tables instead of failing on them.
+	if err := writeIndexDB(dbPath, index, errs); err != nil {
+		t.Fatalf("rewrite db: %v", err)
+	}
+	output, err := exec.Command(sqlite, dbPath, "SELECT DISTINCT file FROM refs WHERE id = 'i_potion_heal'; SELECT count(*) FROM issues;").CombinedOutput()
+	if err != nil {
+		t.Fatalf("query db: %v: %s", err, output)
+	}
+	if got := strings.TrimSpace(string(output)); got != fmt.Sprintf("potions.scp\n%d", len(errs)) {
+		t.Fatalf("unexpected query result %q", got)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// intrinsicFunctions are the built-in functions called as <NAME args> or
+	// <NAME(args)>; the string functions are added in init.
+	intrinsicFunctions = map[string]bool{
+		"ABS": true, "ARCCOS": true, "ARCSIN": true, "ARCTAN": true, "BETWEEN": true,
+		"BETWEEN2": true, "CLRBIT": true, "COS": true, "EVAL": true, "EXPLODE": true,
+		"FEVAL": true, "FHVAL": true, "FLOATVAL": true, "FVAL": true, "HVAL": true,
+		"ISBIT": true, "ISEMPTY": true, "ISNUMBER": true, "ISOBSCENE": true,
+		"LOGARITHM": true, "MULDIV": true, "NAPIERPOW": true, "QVAL": true,
+		"RAND": true, "RANDBELL": true, "SETBIT": true, "SIN": true, "SQRT": true,
+		"TAN": true, "UVAL": true,
+	}
+
+	// intrinsicNamespaces are the variable namespaces read as <NAME.x>,
+	// including their d-prefixed decimal forms.
+	intrinsicNamespaces = map[string]bool{"DLOCAL": true, "DTAG": true, "DTAG0": true, "DVAR": true, "DVAR0": true}
+
+	// scriptObjects are object references read as <NAME.x>; they are not
+	// namespaces but several sit one edit away from one (ARGO and ARGV).
+	scriptObjects = map[string]bool{
+		"ACCOUNT": true, "ACT": true, "ARGO": true, "CONT": true, "DB": true,
+		"FILE": true, "GUILD": true, "LDB": true, "LINK": true, "MAP": true,
+		"MDB": true, "NEW": true, "OBJ": true, "OWNER": true, "PARTY": true,
+		"REGION": true, "ROOM": true, "SECTOR": true, "SERV": true, "SRC": true,
+		"TOPOBJ": true, "TYPEDEF": true, "UID": true, "WEAPON": true,
+	}
+
+	intrinsicCallPattern = regexp.MustCompile(`<([A-Za-z_][A-Za-z0-9_]*)([ \t(.])`)
+)
+
+func init() {
+	for name := range stringFunctionArgs {
+		intrinsicFunctions[name] = true
+	}
+	for name := range variableNamespaces {
+		intrinsicNamespaces[name] = true
+	}
+}
+
+// intrinsicCall is a <NAME ...> token one or two edits away from a built-in
+// function or namespace. It is only reported once every file is read, since
+// it may turn out to be a FUNCTION of the scripts.
+type intrinsicCall struct {
+	file       string
+	line       int
+	name       string
+	suggestion string
+}
+
+// recordIntrinsicCalls collects the function-like tokens of a line that look
+// like misspelled intrinsics, such as <EVL 1+2> or <LOACL.X>. Object methods
+// and other unknown names that resemble no intrinsic are left alone.
+func (idx *lintIndex) recordIntrinsicCalls(line, file string, lineNum int) {
+	for _, match := range intrinsicCallPattern.FindAllStringSubmatch(line, -1) {
+		name := strings.ToUpper(match[1])
+		known := intrinsicFunctions
+		if match[2] == "." {
+			known = intrinsicNamespaces
+		}
+		if known[name] || scriptObjects[name] || len(name) < 3 || customIntrinsic(name) {
+			continue
+		}
+		if suggestion := closestIntrinsic(name, known); suggestion != "" {
+			idx.intrinsicCalls = append(idx.intrinsicCalls, intrinsicCall{file: file, line: lineNum, name: name, suggestion: suggestion})
+		}
+	}
+}
+
+// closestIntrinsic returns the known name within one edit of name (two for
+// names of six letters or more), or "".
+func closestIntrinsic(name string, known map[string]bool) string {
+	candidates := make([]string, 0, len(known))
+	for candidate := range known {
+		candidates = append(candidates, candidate)
+	}
+	sort.Strings(candidates)
+	best, bestDistance := "", 0
+	for _, candidate := range candidates {
+		limit := 1
+		if len(candidate) >= 6 {
+			limit = 2
+		}
+		if d := editDistance(name, candidate); d <= limit && (best == "" || d < bestDistance) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func customIntrinsic(name string) bool {
+	for _, custom := range config.Intrinsics {
+		if strings.EqualFold(custom, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findIntrinsicTypos reports the recorded calls whose name is not defined by
+// the scripts themselves.
+func findIntrinsicTypos(index *lintIndex) []lintIssue {
+	var issues []lintIssue
+	for _, call := range index.intrinsicCalls {
+		if _, ok := index.ids[call.name]; ok {
+			continue
+		}
+		if _, ok := index.defnames[call.name]; ok {
+			continue
+		}
+		issues = appendError(issues, call.file, call.line, "TYPO", fmt.Sprintf("TYPO: unknown function '%s' in <> expression. Did you mean '%s'?", call.name, call.suggestion))
+	}
+	return issues
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestLintIntrinsicTypos(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION qvals]",
+		"RETURN 1",
+		"[FUNCTION f_math]",
+		"LOCAL.X=<EVL 1+2>",
+		"LOCAL.Y=<LOCL.X>",
+		"LOCAL.Z=<STRLENN(<LOCAL.X>)>",
+		"LOCAL.W=<QVALS 1>",
+		"LOCAL.V=<RANDBEL 3,5>",
+		"LOCAL.U=<FINDLAYER 21> <ARGO.NAME> <dLOCAL.X> <EVAL <HVAL 5>>",
+		"[EOF]",
+	)
+
+	t.Run("Default", func(t *testing.T) {
+		errs := lintFromContent(t, "intrinsics.scp", content)
+		assertHasMessage(t, errs, "TYPO: unknown function 'EVL' in <> expression. Did you mean 'EVAL'?")
+		assertHasMessage(t, errs, "TYPO: unknown function 'LOCL' in <> expression. Did you mean 'LOCAL'?")
+		assertHasMessage(t, errs, "TYPO: unknown function 'STRLENN' in <> expression. Did you mean 'STRLEN'?")
+		assertHasMessage(t, errs, "TYPO: unknown function 'RANDBEL' in <> expression. Did you mean 'RANDBELL'?")
+		if len(errs) != 4 {
+			t.Fatalf("expected 4 intrinsic typos, got %+v", errs)
+		}
+	})
+
+	t.Run("CustomIntrinsics", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Intrinsics = []string{"evl", "RANDBEL"} })
+
+		errs := lintFromContent(t, "intrinsics.scp", content)
+		if len(errs) != 2 {
+			t.Fatalf("expected configured intrinsics to be accepted, got %+v", errs)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// variableNamespaces are path segments whose children are user variables
+	// rather than intrinsic properties (LOCAL.UID is a plain variable).
+	variableNamespaces = map[string]bool{
+		"ARGV": true, "CTAG": true, "CTAG0": true, "DEF": true, "DEF0": true,
+		"LOCAL": true, "TAG": true, "TAG0": true, "VAR": true, "VAR0": true,
+	}
+
+	// readOnlyProperties are object intrinsics the server computes itself;
+	// assigning to them is silently ignored or raises a console error.
+	readOnlyProperties = map[string]bool{
+		"AGE":       true,
+		"BASEID":    true,
+		"ISARMOR":   true,
+		"ISCHAR":    true,
+		"ISCONT":    true,
+		"ISGM":      true,
+		"ISITEM":    true,
+		"ISMOUNTED": true,
+		"ISNPC":     true,
+		"ISONLINE":  true,
+		"ISPLAYER":  true,
+		"ISWEAPON":  true,
+		"REGION":    true,
+		"ROOM":      true,
+		"SECTOR":    true,
+		"SERIAL":    true,
+		"TOPOBJ":    true,
+		"UID":       true,
+	}
+
+	// readOnlyServerProperties are SERV.* values that only report state.
+	readOnlyServerProperties = map[string]bool{
+		"ACCOUNTS": true,
+		"CHARS":    true,
+		"CLIENTS":  true,
+		"GUILDS":   true,
+		"ITEMS":    true,
+		"RTICKS":   true,
+		"RTIME":    true,
+		"TIME":     true,
+		"UPTIME":   true,
+		"VERSION":  true,
+	}
+)
+
+// checkReadOnlyAssignment reports assignments such as SRC.UID=... or
+// SERV.TIME=... that target a read-only intrinsic.
+func checkReadOnlyAssignment(line, file string, lineNum int) []lintIssue {
+	key, _, ok := splitAssignment(line)
+	if !ok || strings.ContainsAny(key, "<>[]()") {
+		return nil
+	}
+	if inVariableNamespace(key) {
+		return nil
+	}
+	segments := strings.Split(key, ".")
+	last := segments[len(segments)-1]
+	readOnly := readOnlyProperties[last]
+	if len(segments) == 2 && segments[0] == "SERV" {
+		readOnly = readOnlyServerProperties[last]
+	}
+	if !readOnly {
+		return nil
+	}
+	return appendError(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: '%s' is read-only; the assignment has no effect.", key))
+}
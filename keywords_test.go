@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestLintReadOnlyAssignments(t *testing.T) {
+	t.Run("ReadOnlyTargets", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"SRC.UID=01",
+			"ISPLAYER=1",
+			"ACT.TOPOBJ.ISNPC = 0",
+			"SERV.TIME=0",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "readonly.scp", content)
+		assertHasMessage(t, errs, "LOGIC: 'SRC.UID' is read-only; the assignment has no effect.")
+		assertHasMessage(t, errs, "LOGIC: 'ISPLAYER' is read-only")
+		assertHasMessage(t, errs, "LOGIC: 'ACT.TOPOBJ.ISNPC' is read-only")
+		assertHasMessage(t, errs, "LOGIC: 'SERV.TIME' is read-only")
+	})
+
+	t.Run("WritableTargets", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.UID=<SRC.UID>",
+			"SRC.TAG.ISPLAYER=1",
+			"VAR.TIME=<SERV.TIME>",
+			"SRC.NAME=test",
+			"IF (<SRC.UID> == <UID>)",
+			"ENDIF",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "readonly_ok.scp", content), "writable targets")
+	})
+}
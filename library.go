@@ -0,0 +1,72 @@
+package main
+
+import "strings"
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// loadLibraries scans the -lib-dir script packs, usually the stock server
+// scripts an overlay pack builds on, and adds their definitions to index so
+// references to them resolve. Library files are never linted themselves:
+// their issues are dropped, and definitions of the linted pack win over
+// library ones without being reported as duplicates.
+func loadLibraries(dirs []string, index *lintIndex) []lintIssue {
+	var issues []lintIssue
+	for _, dir := range dirs {
+		lib := newLintIndex()
+		issues = append(issues, walkScripts(dir, func(path string) {
+			lintScriptFile(path, lib)
+		})...)
+		index.addLibrary(lib)
+	}
+	return issues
+}
+
+// addLibrary copies the definitions, aliases and templates of a library
+// index that index does not define itself.
+func (idx *lintIndex) addLibrary(lib *lintIndex) {
+	for key, loc := range lib.defs {
+		if _, ok := idx.defs[key]; !ok {
+			idx.defs[key] = loc
+		}
+	}
+	for key, loc := range lib.defnames {
+		if _, ok := idx.defnames[key]; !ok {
+			idx.defnames[key] = loc
+		}
+	}
+	for key, loc := range lib.ids {
+		if _, ok := idx.ids[key]; !ok {
+			idx.ids[key] = loc
+		}
+	}
+	for key, owner := range lib.owners {
+		if _, ok := idx.owners[key]; !ok {
+			idx.owners[key] = owner
+		}
+	}
+	for key, alias := range lib.aliases {
+		if _, ok := idx.aliases[key]; !ok {
+			idx.aliases[key] = alias
+		}
+	}
+	for id, edges := range lib.templates.edges {
+		if _, ok := idx.templates.edges[id]; !ok {
+			idx.templates.edges[id] = edges
+		}
+	}
+	for name, id := range lib.templates.aliases {
+		if _, ok := idx.templates.aliases[name]; !ok {
+			idx.templates.aliases[name] = id
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLibraries(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	libDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(libDir, "items.scp"), []byte(joinLines(
+		"[ITEMDEF i_gold]",
+		"ID=0eed",
+		"TYPE=t_gold",
+		"[ITEMDEF i_dagger]",
+		"ID=0f51",
+		"[EOF]",
+	)), 0o644); err != nil {
+		t.Fatalf("write library: %v", err)
+	}
+
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, dir, "custom.scp", joinLines(
+		"[ITEMDEF i_dagger]",
+		"ID=0f52",
+		"[FUNCTION f_pay]",
+		"SRC.NEWITEM i_gold",
+		"SRC.NEWITEM i_silver",
+		"[EOF]",
+	)), index)
+	errs = append(errs, loadLibraries([]string{libDir}, index)...)
+	errs = append(errs, lintIndexIssues(index)...)
+
+	assertHasMessage(t, errs, "UNDECLARED: 'I_SILVER'")
+	if len(errs) != 1 || errs[0].file != "custom.scp" {
+		t.Fatalf("expected only the undeclared i_silver in custom.scp, got %+v", errs)
+	}
+	if loc := index.defs["ITEMDEF I_DAGGER"]; loc.file != "custom.scp" {
+		t.Fatalf("expected the pack's i_dagger to win over the library one, got %+v", loc)
+	}
+}
+
+func TestLoadLibraryTemplates(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	libDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(libDir, "vendors.scp"), []byte(joinLines(
+		"[ITEMDEF i_hammer]",
+		"ID=013e3",
+		"[TEMPLATE 100]",
+		"DEFNAME=vendor_s_smith",
+		"ITEM=i_hammer",
+		"[RESDEFNAME]",
+		"i_smith_hammer i_hammer",
+		"[EOF]",
+	)), 0o644); err != nil {
+		t.Fatalf("write library: %v", err)
+	}
+
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, dir, "smith.scp", joinLines(
+		"[CHARDEF c_smith]",
+		"NAME=smith",
+		"SELL=vendor_s_smith",
+		"ON=@DClick",
+		"SRC.NEWITEM i_smith_hammer",
+		"[EOF]",
+	)), index)
+	errs = append(errs, loadLibraries([]string{libDir}, index)...)
+	errs = append(errs, lintIndexIssues(index)...)
+
+	assertNoErrors(t, errs, "a vendor template and alias from a library")
+}
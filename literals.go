@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// numericProperties are assignments whose values are numbers (or
+	// comma-separated ranges of numbers) when they start with a digit.
+	numericProperties = map[string]bool{
+		"AMOUNT": true, "ARMOR": true, "ATTR": true, "COLOR": true,
+		"DAM": true, "DECAY": true, "DEX": true, "DISPID": true, "FAME": true, "FOOD": true,
+		"HITS": true, "ID": true, "INT": true, "KARMA": true, "LAYER": true,
+		"MANA": true, "MAXHITS": true, "MORE": true, "MORE1": true, "MORE2": true,
+		"MOREX": true, "MOREY": true, "MOREZ": true, "STAM": true, "STR": true,
+		"TDATA1": true, "TDATA2": true, "TDATA3": true, "TDATA4": true,
+		"TIMER": true, "TIMERD": true, "WEIGHT": true,
+	}
+
+	// numericHeaderTypes are sections whose numeric header IDs are hex.
+	numericHeaderTypes = map[string]bool{"CHARDEF": true, "ITEMDEF": true}
+)
+
+// checkNumericLiteral validates the value of an assignment to one of the
+// numericProperties. Each number between the operators of the value is
+// checked; tokens starting with a letter are defnames and are left alone.
+func checkNumericLiteral(line, file string, lineNum int) []lintIssue {
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" || strings.ContainsAny(value, "<>") {
+		return nil
+	}
+	if inVariableNamespace(key) {
+		return nil
+	}
+	segments := strings.Split(key, ".")
+	if !numericProperties[segments[len(segments)-1]] {
+		return nil
+	}
+	var issues []lintIssue
+	for _, token := range strings.FieldsFunc(value, isLiteralSeparator) {
+		if problem := literalProblem(token); problem != "" {
+			issues = appendError(issues, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s=%s: %s", key, value, problem))
+		}
+	}
+	return issues
+}
+
+// isLiteralSeparator splits a value into the literals of its comma parts;
+// the server evaluates each part as an expression, so TIMER=60*5 holds the
+// two numbers 60 and 5, and TIMER={5 10} the bounds of a random range.
+func isLiteralSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t' || strings.ContainsRune("+-*/%|&^(){}!~", r)
+}
+
+// checkHeaderID validates numeric ITEMDEF/CHARDEF header IDs, which the
+// server always reads as hex.
+func checkHeaderID(defType, args, file string, lineNum int) []lintIssue {
+	id := firstField(args)
+	if !numericHeaderTypes[defType] || id == "" || !isDigit(id[0]) {
+		return nil
+	}
+	digits := id
+	if len(digits) > 2 && (digits[1] == 'x' || digits[1] == 'X') && digits[0] == '0' {
+		digits = digits[2:]
+	}
+	if !isHexDigits(digits) {
+		return appendError(nil, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: [%s %s]: '%s' is not a valid hex ID", defType, id, id))
+	}
+	return nil
+}
+
+// literalProblem describes what is wrong with a token that starts with a
+// digit, or returns "" when it is a valid decimal or 0-prefixed hex number.
+func literalProblem(token string) string {
+	if token == "" || !isDigit(token[0]) {
+		return ""
+	}
+	if token[0] == '0' && !(strings.Contains(token, ".") && isDecimalNumber(token)) {
+		digits := token
+		if len(digits) > 1 && (digits[1] == 'x' || digits[1] == 'X') {
+			digits = digits[2:]
+		}
+		if !isHexDigits(digits) {
+			return fmt.Sprintf("'%s' is not a valid hex number", token)
+		}
+		return ""
+	}
+	if isDecimalNumber(token) {
+		return ""
+	}
+	if isHexDigits(token) {
+		return fmt.Sprintf("'%s' contains hex digits but no leading 0; write 0%s", token, token)
+	}
+	return fmt.Sprintf("'%s' is not a valid number", token)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isHexDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if !isDigit(b) && !(b >= 'a' && b <= 'f') && !(b >= 'A' && b <= 'F') {
+			return false
+		}
+	}
+	return true
+}
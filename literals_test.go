@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestLintNumericLiterals(t *testing.T) {
+	t.Run("Malformed", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF 0eeg]",
+			"COLOR=07ag",
+			"DISPID=1bf2",
+			"DAM=3,7x",
+			"TIMER=60*5x",
+			"[CHARDEF 0x]",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "literals_bad.scp", content)
+		assertHasMessage(t, errs, "SYNTAX: [ITEMDEF 0eeg]: '0eeg' is not a valid hex ID")
+		assertHasMessage(t, errs, "SYNTAX: COLOR=07ag: '07ag' is not a valid hex number")
+		assertHasMessage(t, errs, "SYNTAX: DISPID=1bf2: '1bf2' contains hex digits but no leading 0; write 01bf2")
+		assertHasMessage(t, errs, "SYNTAX: DAM=3,7x: '7x' is not a valid number")
+		assertHasMessage(t, errs, "SYNTAX: TIMER=60*5x: '5x' is not a valid number")
+		assertHasMessage(t, errs, "SYNTAX: [CHARDEF 0x]: '0x' is not a valid hex ID")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF 01bf2]",
+			"COLOR=0481",
+			"DISPID=0x1bf2",
+			"WEIGHT=0.5",
+			"DAM=3,7",
+			"AMOUNT=-5",
+			"COLOR=colors_red",
+			"TAG.COLOR=1st",
+			"MORE1=<EVAL 1+2>",
+			"TIMER=60*5",
+			"MORE1=10+2",
+			"MORE2=(04|0100) - 1",
+			"DAM=2*3,10/2",
+			"[CHARDEF 1bf2]",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "literals_ok.scp", content), "valid numeric literals")
+	})
+}
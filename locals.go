@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	localReadPattern   = regexp.MustCompile(`(?i)<[dh]?LOCAL\.([A-Za-z0-9_]+)(<?)`)
+	localAssignPattern = regexp.MustCompile(`(?i)^\s*LOCAL\.([A-Za-z0-9_]+)(<?)`)
+)
+
+// localScope tracks LOCAL.X variables through one trigger or function body
+// to catch reads of names that are never assigned before use, which is
+// usually a typo. Reads inside a loop are only reported once the outermost
+// loop closes, since a later statement of the loop may set the variable for
+// the next iteration.
+type localScope struct {
+	assigned  map[string]bool
+	reported  map[string]bool
+	deferred  map[string]int
+	loopDepth int
+	dynamic   bool
+}
+
+func newLocalScope() *localScope {
+	return &localScope{assigned: make(map[string]bool), reported: make(map[string]bool), deferred: make(map[string]int)}
+}
+
+// check processes one statement line: reads first, then any assignment the
+// line makes, so LOCAL.X=<LOCAL.X>+1 still counts as a read before set.
+func (s *localScope) check(line, file string, lineNum int) []lintIssue {
+	if s == nil {
+		return nil
+	}
+	fields := strings.Fields(line)
+	token := ""
+	if len(fields) > 0 {
+		token = strings.ToUpper(fields[0])
+	}
+	if token == "WHILE" || (strings.HasPrefix(token, "FOR") && blockStartToEnd[token] != "") {
+		s.loopDepth++
+	}
+	if token == "FOR" && len(fields) >= 3 && isIdentifier(fields[1]) {
+		s.assigned[strings.ToUpper(fields[1])] = true
+	}
+
+	var issues []lintIssue
+	for _, match := range localReadPattern.FindAllStringSubmatch(line, -1) {
+		name := strings.ToUpper(match[1])
+		if match[2] != "" || strings.HasPrefix(name, "_") || s.assigned[name] || s.reported[name] {
+			continue
+		}
+		if s.loopDepth > 0 {
+			if _, ok := s.deferred[name]; !ok {
+				s.deferred[name] = lineNum
+			}
+			continue
+		}
+		issues = s.report(issues, file, lineNum, name)
+	}
+
+	if match := localAssignPattern.FindStringSubmatch(line); len(match) == 3 {
+		if match[2] != "" {
+			s.dynamic = true
+		} else {
+			name := strings.ToUpper(match[1])
+			s.assigned[name] = true
+			delete(s.deferred, name)
+		}
+	}
+
+	if (token == "ENDWHILE" || token == "ENDFOR") && s.loopDepth > 0 {
+		s.loopDepth--
+		if s.loopDepth == 0 {
+			issues = append(issues, s.flushDeferred(file)...)
+		}
+	}
+	return issues
+}
+
+// finish reports deferred reads of a body whose loops never closed.
+func (s *localScope) finish(file string) []lintIssue {
+	if s == nil {
+		return nil
+	}
+	return s.flushDeferred(file)
+}
+
+func (s *localScope) flushDeferred(file string) []lintIssue {
+	names := make([]string, 0, len(s.deferred))
+	for name := range s.deferred {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.deferred[names[i]] != s.deferred[names[j]] {
+			return s.deferred[names[i]] < s.deferred[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var issues []lintIssue
+	for _, name := range names {
+		issues = s.report(issues, file, s.deferred[name], name)
+	}
+	s.deferred = make(map[string]int)
+	return issues
+}
+
+// report warns about a read before set unless the body assigns locals with
+// computed names (LOCAL.X_<LOCAL.I>=...), which can set any name.
+func (s *localScope) report(issues []lintIssue, file string, lineNum int, name string) []lintIssue {
+	if s.dynamic || s.reported[name] {
+		return issues
+	}
+	s.reported[name] = true
+	return appendWarning(issues, file, lineNum, "LOCAL", fmt.Sprintf("LOCAL: 'LOCAL.%s' is read before it is assigned in this body; check the variable name.", name))
+}
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestLintLocalReadBeforeSet(t *testing.T) {
+	t.Run("TypoedName", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.COUNT=0",
+			"LOCAL.COUNT=<LOCAL.COUTN>+1",
+			"SRC.SYSMESSAGE <dLOCAL.COUTN>",
+			"[ITEMDEF i_test]",
+			"ON=@Create",
+			"LOCAL.COUNT=1",
+			"ON=@DClick",
+			"SRC.SYSMESSAGE <LOCAL.COUNT>",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "locals.scp", content)
+		assertHasMessage(t, errs, "LOCAL: 'LOCAL.COUTN' is read before it is assigned in this body; check the variable name.")
+		assertHasMessage(t, errs, "LOCAL: 'LOCAL.COUNT' is read before it is assigned")
+		count := 0
+		for _, e := range errs {
+			if e.kind == "LOCAL" {
+				count++
+			}
+		}
+		if count != 2 {
+			t.Fatalf("expected 2 LOCAL warnings, got %d: %+v", count, errs)
+		}
+	})
+
+	t.Run("LoopAssignment", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"WHILE (<LOCAL.I> < 5)",
+			"  LOCAL.I=<EVAL <LOCAL.I>+1>",
+			"ENDWHILE",
+			"FOR N 1 5",
+			"  SRC.SYSMESSAGE <LOCAL.N> <LOCAL._FOR>",
+			"ENDFOR",
+			"IF (<SRC.STR> > 50)",
+			"  LOCAL.MSG=strong",
+			"ENDIF",
+			"SRC.SYSMESSAGE <LOCAL.MSG>",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "locals_loop.scp", content), "loop assignments")
+	})
+
+	t.Run("LoopWithoutAssignment", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"WHILE (<LOCAL.J> < 5)",
+			"  LOCAL.I=<EVAL <LOCAL.I>+1>",
+			"ENDWHILE",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "locals_loop_bad.scp", content)
+		assertHasMessage(t, errs, "LOCAL: 'LOCAL.J' is read before it is assigned")
+	})
+
+	t.Run("DynamicNames", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.SLOT_<ARGN1>=1",
+			"SRC.SYSMESSAGE <LOCAL.SLOT_1> <LOCAL.SLOT_<ARGN1>>",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "locals_dynamic.scp", content), "dynamic local names")
+	})
+}
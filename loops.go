@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// loopCheck follows the body of a WHILE loop whose condition can only change
+// from inside the loop: a constant true literal, or a condition that reads
+// nothing but LOCAL variables. Such a loop needs a RETURN, a BREAK or (for
+// the LOCAL case) an assignment to one of its variables to ever end.
+type loopCheck struct {
+	line     int
+	constant bool
+	vars     map[string]bool
+	exits    bool
+	mutated  bool
+}
+
+// newLoopCheck returns a check for WHILE lines whose condition qualifies,
+// or nil.
+func newLoopCheck(token, line string, lineNum int) *loopCheck {
+	if token != "WHILE" {
+		return nil
+	}
+	cond := strings.TrimSpace(line[len(firstField(line)):])
+	literal := strings.TrimSpace(strings.Trim(cond, "() \t"))
+	if n, ok := parseSphereInt(literal); ok {
+		if n == 0 {
+			return nil
+		}
+		return &loopCheck{line: lineNum, constant: true}
+	}
+
+	reads := localReadPattern.FindAllStringSubmatch(cond, -1)
+	if len(reads) == 0 || countAngleTokens(cond) != len(reads) {
+		return nil
+	}
+	check := &loopCheck{line: lineNum, vars: make(map[string]bool)}
+	for _, match := range reads {
+		if match[2] != "" {
+			return nil
+		}
+		check.vars[strings.ToUpper(match[1])] = true
+	}
+	return check
+}
+
+// countAngleTokens counts the <expr> tokens of a line, ignoring comparison
+// operators.
+func countAngleTokens(line string) int {
+	count := 0
+	for i := 0; i+1 < len(line); i++ {
+		if line[i] == '<' && isAngleTokenStart(line[i+1]) {
+			count++
+		}
+	}
+	return count
+}
+
+// noteLoopStatement records what a statement inside the open blocks does to
+// the enclosing WHILE loops: RETURN leaves all of them, BREAK only the
+// innermost loop, and a LOCAL assignment may change their conditions.
+func noteLoopStatement(stack []blockState, token, line string) {
+	switch token {
+	case "RETURN":
+		for _, block := range stack {
+			if block.loop != nil {
+				block.loop.exits = true
+			}
+		}
+		return
+	case "BREAK":
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].typ == "WHILE" || blockStartToEnd[stack[i].typ] == "ENDFOR" {
+				if stack[i].loop != nil {
+					stack[i].loop.exits = true
+				}
+				return
+			}
+		}
+		return
+	}
+	match := localAssignPattern.FindStringSubmatch(line)
+	if len(match) != 3 {
+		return
+	}
+	name := strings.ToUpper(match[1])
+	for _, block := range stack {
+		if block.loop != nil && (match[2] != "" || block.loop.vars[name]) {
+			block.loop.mutated = true
+		}
+	}
+}
+
+// finish reports the loop once its ENDWHILE is reached.
+func (l *loopCheck) finish(file string) []lintIssue {
+	if l == nil || l.exits || l.mutated {
+		return nil
+	}
+	if l.constant {
+		return appendError(nil, file, l.line, "LOGIC", "LOGIC: WHILE loop with a constant true condition has no RETURN or BREAK; it never ends.")
+	}
+	names := make([]string, 0, len(l.vars))
+	for name := range l.vars {
+		names = append(names, "LOCAL."+name)
+	}
+	sort.Strings(names)
+	return appendWarning(nil, file, l.line, "LOGIC", fmt.Sprintf("LOGIC: WHILE loop never changes %s and has no RETURN or BREAK; it may never end.", strings.Join(names, ", ")))
+}
+
+// checkForBounds flags FOR loops whose literal start bound is above the end
+// bound.
+func checkForBounds(token, line, file string, lineNum int) []lintIssue {
+	if token != "FOR" {
+		return nil
+	}
+	fields := strings.Fields(line)[1:]
+	if len(fields) == 3 {
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return nil
+	}
+	start, okStart := parseSphereInt(fields[0])
+	end, okEnd := parseSphereInt(fields[1])
+	if !okStart || !okEnd || start <= end {
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: FOR loop bounds are reversed (%s > %s).", fields[0], fields[1]))
+}
+
+// loopControlSince is the first server version with BREAK and CONTINUE.
+const loopControlSince = "56b"
+
+// checkLoopControl flags BREAK and CONTINUE outside any FOR or WHILE loop,
+// and anywhere when the target server version does not know them yet.
+func checkLoopControl(stack []blockState, token, file string, lineNum int) []lintIssue {
+	if token != "BREAK" && token != "CONTINUE" {
+		return nil
+	}
+	if versionIndex(config.TargetVersion) < versionIndex(loopControlSince) {
+		return appendError(nil, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s is not supported by %s; it was added in %s.", token, formatVersion(config.TargetVersion), formatVersion(loopControlSince)))
+	}
+	for _, block := range stack {
+		if block.typ == "WHILE" || blockStartToEnd[block.typ] == "ENDFOR" {
+			return nil
+		}
+	}
+	return appendError(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s outside of a FOR or WHILE loop.", token))
+}
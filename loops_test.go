@@ -0,0 +1,102 @@
+package main
+
+import "testing"
+
+func TestLintInfiniteLoops(t *testing.T) {
+	t.Run("LikelyInfinite", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.I=0",
+			"LOCAL.J=0",
+			"WHILE 1",
+			"  SRC.SYSMESSAGE tick",
+			"ENDWHILE",
+			"WHILE (<LOCAL.I> < 10)",
+			"  LOCAL.J=<EVAL <LOCAL.J>+1>",
+			"ENDWHILE",
+			"FOR N 10 1",
+			"ENDFOR",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "loops_bad.scp", content)
+		assertHasMessage(t, errs, "LOGIC: WHILE loop with a constant true condition has no RETURN or BREAK; it never ends.")
+		assertHasMessage(t, errs, "LOGIC: WHILE loop never changes LOCAL.I and has no RETURN or BREAK; it may never end.")
+		assertHasMessage(t, errs, "LOGIC: FOR loop bounds are reversed (10 > 1).")
+		if len(errs) != 3 || errs[0].line != 4 || errs[1].line != 7 || errs[2].line != 10 {
+			t.Fatalf("expected 3 loop issues on lines 4, 7 and 10, got %+v", errs)
+		}
+	})
+
+	t.Run("TerminatingLoops", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.I=0",
+			"WHILE (1)",
+			"  IF (<SRC.HITS> < 10)",
+			"    BREAK",
+			"  ENDIF",
+			"ENDWHILE",
+			"WHILE 1",
+			"  FOR 3",
+			"  ENDFOR",
+			"  RETURN 1",
+			"ENDWHILE",
+			"WHILE (<LOCAL.I> < 10)",
+			"  LOCAL.I ++",
+			"ENDWHILE",
+			"WHILE (<LOCAL.I> < <SRC.STR>)",
+			"ENDWHILE",
+			"FOR N 1 10",
+			"ENDFOR",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "loops_ok.scp", content), "terminating loops")
+	})
+}
+
+func TestLintLoopControl(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_scan]",
+		"FORCHARS 5",
+		"  IF <ISPLAYER>",
+		"    CONTINUE",
+		"  ENDIF",
+		"  DORAND 2",
+		"    BREAK",
+		"    SAY hi",
+		"  ENDDO",
+		"ENDFOR",
+		"WHILE <LOCAL.I> < 5",
+		"  LOCAL.I += 1",
+		"  BREAK",
+		"ENDWHILE",
+		"IF <SRC.ISGM>",
+		"  BREAK",
+		"ENDIF",
+		"CONTINUE",
+		"[EOF]",
+	)
+
+	t.Run("Supported", func(t *testing.T) {
+		errs := lintFromContent(t, "loop_control.scp", content)
+		assertHasMessage(t, errs, "LOGIC: BREAK outside of a FOR or WHILE loop.")
+		assertHasMessage(t, errs, "LOGIC: CONTINUE outside of a FOR or WHILE loop.")
+		if len(errs) != 2 || errs[0].line != 16 || errs[1].line != 18 {
+			t.Fatalf("expected errors on lines 16 and 18, got %+v", errs)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.TargetVersion = "56a"
+		})
+		errs := lintFromContent(t, "loop_control.scp", content)
+		assertHasMessage(t, errs, "SYNTAX: CONTINUE is not supported by 0.56a; it was added in 0.56b.")
+		assertHasMessage(t, errs, "SYNTAX: BREAK is not supported by 0.56a")
+		if len(errs) != 5 {
+			t.Fatalf("expected every BREAK/CONTINUE to be reported, got %+v", errs)
+		}
+	})
+}
@@ -2,20 +2,31 @@ package main
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type lintIssue struct {
-	file string
-	line int
-	kind string
-	msg  string
+	file     string
+	line     int
+	col      int
+	kind     string
+	msg      string
+	severity string
 }
 
+const (
+	severityError   = ""
+	severityWarning = "warning"
+)
+
 type definitionLocation struct {
 	file string
 	line int
@@ -26,6 +37,40 @@ type referenceUse struct {
 	line     int
 	defTypes []string
 	id       string
+	text     string
+	from     string
+}
+
+// lintIndex holds the cross-file state collected while scanning scripts and
+// resolved once every file has been read.
+type lintIndex struct {
+	defs       map[string]definitionLocation
+	defnames   map[string]definitionLocation
+	ids        map[string]definitionLocation
+	references []referenceUse
+	dialogs    map[string]*dialogInfo
+	tags       map[string]*tagUsage
+
+	owners       map[string]*triggerOwner
+	triggerCalls []triggerCall
+	templates    *templateGraph
+	vendorUses   []vendorUse
+
+	intrinsicCalls []intrinsicCall
+	commandUses    []commandUse
+	calls          *callGraph
+	resources      *resourceList
+	scripts        []string
+	regions        regionIndex
+	skillKeys      map[string]definitionLocation
+	bodies         *sectionBodies
+	spellings      map[string]declaredSpelling
+	aliases        map[string]aliasEntry
+	numericRefs    []numericReference
+	symbols        []*symbolInfo
+	// labelled counts the references already tagged with their section.
+	labelled int
+	files    map[[sha256.Size]byte]string
 }
 
 type referencePattern struct {
@@ -75,6 +120,7 @@ var (
 		"SKILLCLASS": true,
 		"SKILLMENU":  true,
 		"SPAWN":      true,
+		"SPEECH":     true,
 		"SPELL":      true,
 		"TYPEDEF":    true,
 		"TEMPLATE":   true,
@@ -83,7 +129,7 @@ var (
 	textKeywords = map[string]bool{
 		"SAY": true, "SYSMESSAGE": true, "MESSAGE": true, "EMOTE": true, "SAYU": true, "SAYUA": true,
 		"TITLE": true, "NAME": true, "DESC": true, "PROMPTCONSOLE": true, "BARK": true, "GROUP": true,
-		"EVENTS": true, "FLAGS": true, "RECT": true, "P": true, "AUTHOR": true, "PAGES": true,
+		"EVENTS": true, "FLAGS": true, "RECT": true, "AUTHOR": true, "PAGES": true,
 	}
 
 	bracketPairs = map[rune]rune{')': '(', ']': '[', '}': '{', '>': '<'}
@@ -131,13 +177,139 @@ var (
 	itemAssignPattern      = regexp.MustCompile(`(?i)^\s*ITEM\s*=\s*(.*)$`)
 	containerAssignPattern = regexp.MustCompile(`(?i)^\s*CONTAINER\s*=\s*(.*)$`)
 	templateIdentPattern   = regexp.MustCompile(`(?i)\b[a-z_][a-z0-9_]*\b`)
+	speechAssignPattern    = regexp.MustCompile(`(?i)^\s*(?:[a-z0-9_.]+\.)?SPEECH\s*=\s*(.*)$`)
+	creationCallPattern    = regexp.MustCompile(`(?i)^\s*(?:[a-z0-9_.]+\.)?(NEWITEM|NEWNPC|NEWLOOT)(?:\s*=\s*|\s+)([^,\s]+)`)
+
+	creationDefTypes = map[string][]string{
+		"NEWITEM": {"ITEMDEF", "TEMPLATE"},
+		"NEWLOOT": {"ITEMDEF", "TEMPLATE"},
+		"NEWNPC":  {"CHARDEF"},
+	}
 )
 
+func newLintIndex() *lintIndex {
+	return &lintIndex{
+		defs:      make(map[string]definitionLocation),
+		defnames:  make(map[string]definitionLocation),
+		ids:       make(map[string]definitionLocation),
+		dialogs:   make(map[string]*dialogInfo),
+		tags:      make(map[string]*tagUsage),
+		owners:    make(map[string]*triggerOwner),
+		templates: newTemplateGraph(),
+		calls:     newCallGraph(),
+		resources: newResourceList(),
+		skillKeys: make(map[string]definitionLocation),
+		bodies:    newSectionBodies(),
+		spellings: make(map[string]declaredSpelling),
+		aliases:   make(map[string]aliasEntry),
+		files:     make(map[[sha256.Size]byte]string),
+	}
+}
+
 func main() {
-	defLocations := make(map[string]definitionLocation)
-	defnameLocations := make(map[string]definitionLocation)
-	idLocations := make(map[string]definitionLocation)
-	var refUses []referenceUse
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "format":
+			os.Exit(runFormat(os.Args[2:]))
+		case "callgraph":
+			os.Exit(runCallGraph(os.Args[2:]))
+		case "index":
+			os.Exit(runIndex(os.Args[2:]))
+		case "graph":
+			os.Exit(runGraph(os.Args[2:]))
+		case "catalog":
+			os.Exit(runCatalog(os.Args[2:]))
+		case "conflicts":
+			os.Exit(runConflicts(os.Args[2:]))
+		case "deadcode":
+			os.Exit(runDeadCode(os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "defs":
+			os.Exit(runDefs(os.Args[2:]))
+		case "rename":
+			os.Exit(runRename(os.Args[2:]))
+		case "refs":
+			os.Exit(runRefs(os.Args[2:]))
+		case "stats":
+			os.Exit(runStats(os.Args[2:]))
+		case "symbols":
+			os.Exit(runSymbols(os.Args[2:]))
+		}
+	}
+	os.Exit(runLint(os.Args[1:]))
+}
+
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	targetVersion := flags.String("target-version", "", "server version to lint against: "+strings.Join(serverVersions, ", "))
+	var libDirs stringList
+	flags.Var(&libDirs, "lib-dir", "script pack whose definitions are known but not linted, such as the stock server scripts (repeatable)")
+	var indexPaths stringList
+	flags.Var(&indexPaths, "use-index", "definition index saved by sphere-lint index to resolve references against (repeatable)")
+	indexDB := flags.String("index-db", "", "write definitions, references and issues to this SQLite database (needs sqlite3)")
+	groupBy := flags.String("group-by", "", "group the issues in the report: owner (from CODEOWNERS)")
+	historyDir := flags.String("save-history", "", "save the issue counts of this run to a new JSON file in this directory")
+	compareTo := flags.String("compare-to", "", "report new, fixed and unchanged issues against a run saved by -save-history")
+	ratchetPath := flags.String("ratchet", "", "only fail when a rule has more issues than this JSON file of per-rule counts allows")
+	ratchetUpdate := flags.Bool("ratchet-update", false, "lower the counts in the -ratchet file to the current ones (creating it if needed)")
+	codeOwnersPath := flags.String("codeowners", "", "CODEOWNERS file for -group-by=owner (default: CODEOWNERS, .github/CODEOWNERS or docs/CODEOWNERS in the scripts root)")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, *targetVersion); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *ratchetUpdate && *ratchetPath == "" {
+		fmt.Fprintln(os.Stderr, "-ratchet-update needs -ratchet")
+		return 2
+	}
+	var allowed map[string]int
+	ratchetExists := false
+	if *ratchetPath != "" {
+		var err error
+		if allowed, ratchetExists, err = readRatchet(*ratchetPath); err != nil {
+			fmt.Fprintln(os.Stderr, "ratchet:", err)
+			return 2
+		}
+	}
+	var previous *historyRun
+	if *compareTo != "" {
+		run, err := readHistory(*compareTo)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "compare-to:", err)
+			return 2
+		}
+		previous = &run
+	}
+	var owners codeOwners
+	switch *groupBy {
+	case "":
+	case "owner":
+		var err error
+		if owners, err = loadCodeOwners(*codeOwnersPath); err != nil {
+			fmt.Fprintln(os.Stderr, "codeowners:", err)
+			return 2
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -group-by %q (use owner)\n", *groupBy)
+		return 2
+	}
+
+	var saved []*lintIndex
+	for _, path := range indexPaths {
+		lib, err := readSymbolIndex(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "use-index:", err)
+			return 2
+		}
+		saved = append(saved, lib)
+	}
+
+	index := newLintIndex()
 	var issues []lintIssue
 
 	scannedFiles := 0
@@ -145,7 +317,103 @@ func main() {
 
 	fmt.Println("=== SPHERE SCP LINT (Go Action) ===")
 
-	err := filepath.WalkDir(scriptsRoot, func(path string, d os.DirEntry, walkErr error) error {
+	issues = append(issues, walkScripts(scriptsRoot, func(path string) {
+		scannedFiles++
+
+		issues = append(issues, lintScriptFile(path, index)...)
+	})...)
+	issues = append(issues, loadLibraries(libDirs, index)...)
+	for _, lib := range saved {
+		index.addLibrary(lib)
+	}
+	issues = append(issues, lintResourceIni(index)...)
+	issues = append(issues, lintIndexIssues(index)...)
+
+	if *groupBy == "owner" {
+		printIssuesByOwner(issues, owners)
+	}
+	errorCount := 0
+	for _, issue := range issues {
+		if *groupBy == "" {
+			printError(issue)
+		}
+		if issue.severity == severityError {
+			errorCount++
+			filesWithIssues[issue.file] = true
+		}
+	}
+
+	fmt.Println("---------------------------------------------")
+	fmt.Printf("Files scanned: %d\n", scannedFiles)
+	fmt.Printf("Files with errors: %d\n", len(filesWithIssues))
+	fmt.Printf("Total errors: %d\n", errorCount)
+	fmt.Printf("Total warnings: %d\n", len(issues)-errorCount)
+
+	if *historyDir != "" || previous != nil {
+		current := newHistoryRun(issues, time.Now())
+		if previous != nil {
+			fmt.Println("---------------------------------------------")
+			writeHistoryDelta(os.Stdout, *previous, current)
+		}
+		if *historyDir != "" {
+			path, err := saveHistory(*historyDir, current)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "save-history:", err)
+				return 2
+			}
+			fmt.Printf("History saved to %s\n", path)
+		}
+	}
+	if *indexDB != "" {
+		if err := writeIndexDB(*indexDB, index, issues); err != nil {
+			fmt.Fprintln(os.Stderr, "index-db:", err)
+			return 2
+		}
+	}
+	if *ratchetPath != "" {
+		counts := ruleCounts(issues)
+		fmt.Println("---------------------------------------------")
+		regressed := checkRatchet(os.Stdout, allowed, counts, *ratchetPath)
+		if *ratchetUpdate {
+			if err := updateRatchet(*ratchetPath, allowed, counts, ratchetExists); err != nil {
+				fmt.Fprintln(os.Stderr, "ratchet:", err)
+				return 2
+			}
+			fmt.Printf("Ratchet updated: %s\n", *ratchetPath)
+		}
+		// Recording the first counts is not a regression.
+		if regressed && (ratchetExists || !*ratchetUpdate) {
+			return 1
+		}
+		return 0
+	}
+	if errorCount > 0 {
+		return 1
+	}
+	return 0
+}
+
+// setupConfig loads the config file and applies command-line overrides.
+func setupConfig(configPath, targetVersion string) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if targetVersion != "" {
+		if !isKnownVersion(targetVersion) {
+			return fmt.Errorf("unknown -target-version %q", targetVersion)
+		}
+		cfg.TargetVersion = targetVersion
+	}
+	config = cfg
+	return nil
+}
+
+// walkScripts calls visit for every script file under root, skipping the
+// ignored directories. Walk failures are returned as CRITICAL issues.
+func walkScripts(root string, visit func(path string)) []lintIssue {
+	var issues []lintIssue
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			issues = append(issues, lintIssue{file: path, line: 1, kind: "CRITICAL", msg: walkErr.Error()})
 			return nil
@@ -159,84 +427,111 @@ func main() {
 		if !hasExtension(path, scriptExtensions) {
 			return nil
 		}
-		scannedFiles++
-
-		fileIssues := lintScriptFile(path, defLocations, defnameLocations, idLocations, &refUses)
-		if len(fileIssues) > 0 {
-			for _, issue := range fileIssues {
-				filesWithIssues[issue.file] = true
-			}
-			issues = append(issues, fileIssues...)
-		}
+		visit(path)
 		return nil
 	})
 	if err != nil {
-		issues = append(issues, lintIssue{file: scriptsRoot, line: 1, kind: "CRITICAL", msg: err.Error()})
-	}
-
-	undefinedIssues := findUndefinedReferences(refUses, defLocations, defnameLocations, idLocations)
-	if len(undefinedIssues) > 0 {
-		for _, issue := range undefinedIssues {
-			filesWithIssues[issue.file] = true
-		}
-		issues = append(issues, undefinedIssues...)
-	}
-
-	for _, issue := range issues {
-		printError(issue)
-	}
-
-	fmt.Println("---------------------------------------------")
-	fmt.Printf("Files scanned: %d\n", scannedFiles)
-	fmt.Printf("Files with errors: %d\n", len(filesWithIssues))
-	fmt.Printf("Total errors: %d\n", len(issues))
-
-	if len(issues) > 0 {
-		os.Exit(1)
+		issues = append(issues, lintIssue{file: root, line: 1, kind: "CRITICAL", msg: err.Error()})
 	}
+	return issues
 }
 
-func lintScriptFile(path string, defIndex map[string]definitionLocation, defnameIndex map[string]definitionLocation, idIndex map[string]definitionLocation, references *[]referenceUse) []lintIssue {
+func lintScriptFile(path string, index *lintIndex) []lintIssue {
 	var issues []lintIssue
 	var stack []blockState
 	inTextBlock := false
+	inResources := false
 	currentSection := ""
+	var section sectionState
+	var preamble preambleLines
+	returnLine := 0
+	deprecated := activeDeprecations(config.TargetVersion)
 
 	rel := toRelative(path)
+	index.scripts = append(index.scripts, rel)
 
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return []lintIssue{{file: rel, line: 1, kind: "CRITICAL", msg: err.Error()}}
 	}
-	defer file.Close()
+	content, encodingIssues := decodeScript(data, rel)
+	issues = append(issues, encodingIssues...)
+	issues = append(issues, index.checkFileCopy(content, rel)...)
+	emptyIssues, empty := checkEmptyScript(content, rel)
+	issues = append(issues, emptyIssues...)
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	lineNum := 0
-	lastNonEmpty := ""
+	var eof eofMarker
 
 	for scanner.Scan() {
 		lineNum++
 		raw := scanner.Text()
-		cleaned := cleanLine(raw)
-		if cleaned != "" {
-			lastNonEmpty = cleaned
+		if eof.line > 0 {
+			// The server stops reading at [EOF]; linting the rest would
+			// only report problems in code that never runs.
+			if cleaned := cleanLine(raw); cleaned != "" {
+				issues = append(issues, eof.check(cleaned, rel, lineNum)...)
+			}
+			continue
+		}
+		issues = append(issues, checkControlChars(raw, rel, lineNum)...)
+		if ruleEnabled("trailing-whitespace") {
+			issues = append(issues, checkTrailingWhitespace(raw, rel, lineNum)...)
 		}
+		cleaned := cleanLine(raw)
 		if cleaned == "" {
 			continue
 		}
+		issues = append(issues, eof.check(cleaned, rel, lineNum)...)
 
 		if inTextBlock {
 			if hasLeadingWhitespace(raw) {
 				continue
 			}
-			if !defHeaderPattern.MatchString(cleaned) && !commentHeaderPattern.MatchString(cleaned) {
+			if !defHeaderPattern.MatchString(cleaned) && !commentHeaderPattern.MatchString(cleaned) && !resourcesHeaderPattern.MatchString(cleaned) {
 				continue
 			}
 		}
 
+		if resourcesHeaderPattern.MatchString(cleaned) {
+			issues = appendUnclosedStackErrors(issues, stack, rel, lineNum, " before new section.", false)
+			issues = append(issues, index.endSection(section, rel, lineNum-1)...)
+			section = sectionState{defType: "RESOURCES"}
+			preamble.closed = true
+			currentSection = "RESOURCES"
+			returnLine = 0
+			inTextBlock = false
+			inResources = true
+			stack = nil
+			continue
+		}
+		if inResources {
+			if !strings.HasPrefix(cleaned, "[") && !triggerPattern.MatchString(cleaned) {
+				issues = append(issues, index.resources.add(cleaned, rel, lineNum)...)
+				continue
+			}
+			inResources = false
+		}
+		if match := bareHeaderPattern.FindStringSubmatch(cleaned); len(match) == 2 && triggerlessSections[strings.ToUpper(match[1])] {
+			issues = appendUnclosedStackErrors(issues, stack, rel, lineNum, " before new section.", false)
+			issues = append(issues, index.endSection(section, rel, lineNum-1)...)
+			section = sectionState{defType: strings.ToUpper(match[1])}
+			preamble.closed = true
+			currentSection = section.defType
+			returnLine = 0
+			inTextBlock = false
+			stack = nil
+			continue
+		}
+
 		if commentHeaderPattern.MatchString(cleaned) {
 			issues = appendUnclosedStackErrors(issues, stack, rel, lineNum, " before new section.", false)
+			issues = append(issues, index.endSection(section, rel, lineNum-1)...)
+			section = sectionState{}
+			preamble.closed = true
+			returnLine = 0
 			inTextBlock = true
 			stack = nil
 			continue
@@ -252,14 +547,28 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 			} else {
 				inTextBlock = false
 			}
+			issues = append(issues, index.endSection(section, rel, lineNum-1)...)
+			section = beginSection(index, defType, defArgs, rel, lineNum)
+			preamble.closed = true
+			issues = append(issues, checkHeaderID(defType, defArgs, rel, lineNum)...)
+			issues = append(issues, checkHeaderChars(raw, rel, lineNum)...)
+			if defType == "SKILL" {
+				issues = append(issues, checkSkillHeader(defArgs, rel, lineNum)...)
+			}
+			if ruleEnabled("defname-prefix") {
+				issues = append(issues, checkDefnamePrefix(defType, firstField(defArgs), rel, lineNum)...)
+			}
+			returnLine = 0
 			if trackDefTypes[defType] {
+				issues = append(issues, checkBuiltinName(defType, firstField(defArgs), rel, lineNum)...)
 				fields := strings.Fields(defArgs)
 				id := ""
 				if len(fields) > 0 {
 					id = strings.ToUpper(fields[0])
 				}
 				if id != "" {
-					recordIdentifier(idIndex, id, rel, lineNum)
+					recordIdentifier(index.ids, id, rel, lineNum)
+					index.recordSpelling(fields[0], rel, lineNum)
 					key := defType + " " + id
 					if defType == "DIALOG" && len(fields) > 1 {
 						subType := strings.ToUpper(fields[1])
@@ -267,7 +576,7 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 							key = key + " " + subType
 						}
 					}
-					if prev, ok := defIndex[key]; ok {
+					if prev, ok := index.defs[key]; ok {
 						issues = append(issues, lintIssue{
 							file: rel,
 							line: lineNum,
@@ -275,7 +584,7 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 							msg:  fmt.Sprintf("DUPLICATE: '%s' already defined at %s:%d.", key, prev.file, prev.line),
 						})
 					} else {
-						defIndex[key] = definitionLocation{file: rel, line: lineNum}
+						index.defs[key] = definitionLocation{file: rel, line: lineNum}
 					}
 				}
 			}
@@ -283,9 +592,38 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 			continue
 		}
 
+		if !preamble.closed && !strings.HasPrefix(cleaned, "[") {
+			preamble.add(lineNum)
+			continue
+		}
+
+		if !strings.HasPrefix(cleaned, "[") {
+			section.copy.add(cleaned)
+		}
+
 		if triggerPattern.MatchString(cleaned) {
+			issues = append(issues, checkMisplacedTrigger(section, cleaned, rel, lineNum)...)
+			if section.dialogButtons != nil {
+				section.dialogButtons.recordButtonHandler(cleaned)
+			}
+			if section.menu != nil {
+				issues = append(issues, section.menu.addOption(cleaned, rel, lineNum, &index.references)...)
+			}
+			issues = appendDeprecationIssues(issues, rel, lineNum, findDeprecatedTrigger(cleaned, deprecated))
+			section.trigger = beginTriggerContext(cleaned)
+			section.owner.addTrigger(cleaned)
+			section.typeDef.addLine(cleaned)
+			issues = append(issues, section.body.finish(rel)...)
+			section.body = newTriggerBody(cleaned, lineNum)
+			section.indent = 0
+			issues = append(issues, section.locals.finish(rel)...)
+			section.locals = newLocalScope()
+			section.privilege = newTriggerPrivilege(section, cleaned)
+			section.caller = section.label + " " + strings.ToUpper(strings.TrimSpace(cleaned))
+			index.calls.addNode(section.caller, rel, lineNum)
 			inTextBlock = false
 			currentSection = ""
+			returnLine = 0
 			issues = appendUnclosedStackErrors(issues, stack, rel, lineNum, " before new trigger.", false)
 			stack = nil
 			continue
@@ -294,21 +632,83 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 		if inTextBlock {
 			continue
 		}
+		if !strings.HasPrefix(cleaned, "[") {
+			section.body.addStatement()
+		}
+		if ruleEnabled("mixed-indent") {
+			issues = append(issues, checkIndentStyle(&section.indent, raw, rel, lineNum)...)
+		}
+
+		if section.dialogText != nil {
+			section.dialogText.textLines++
+			continue
+		}
 
-		if isDefnameSection(currentSection) {
+		if section.menu.addTitle(cleaned) {
+			continue
+		}
+		issues = append(issues, checkIdentifierChars(raw, rel, lineNum)...)
+
+		if section.geometry != nil {
+			section.geometry.addLine(cleaned, lineNum)
+		}
+		issues = append(issues, validateCoordinateLine(cleaned, rel, lineNum)...)
+		if section.dialogLayout != nil {
+			issues = append(issues, validateGumpLine(cleaned, rel, lineNum)...)
+			section.dialogLayout.recordGumpLine(cleaned, rel, lineNum)
+		}
+
+		if currentSection == "SPELL" {
+			issues = append(issues, validateSpellLine(cleaned, rel, lineNum)...)
+		}
+		if currentSection == "ITEMDEF" {
+			section.typeData.addLine(cleaned, lineNum)
+		}
+		if currentSection != "" {
+			section.symbol.addLine(cleaned)
+		}
+		if currentSection != "" && ruleEnabled("numeric-references") {
+			collectNumericReferences(currentSection, section.dialogLayout != nil, cleaned, rel, lineNum, &index.numericRefs)
+		}
+		if currentSection == "TYPEDEF" {
+			section.typeDef.addLine(cleaned)
+		}
+		section.name.addLine(cleaned)
+		if currentSection == "EVENTS" && !strings.HasPrefix(cleaned, "[") {
+			section.stray.add(lineNum)
+		}
+		if currentSection == "SKILL" {
+			issues = append(issues, section.skill.addLine(cleaned, index.skillKeys, rel, lineNum)...)
+		}
+		if section.defType == "CHARDEF" {
+			issues = append(issues, validateCharLine(cleaned, rel, lineNum)...)
+		}
+
+		if isDefnameSection(currentSection) && !strings.HasPrefix(cleaned, "[") {
 			fields := strings.Fields(cleaned)
 			if len(fields) > 0 {
-				recordDefName(defnameIndex, fields[0], rel, lineNum)
+				recordDefName(index.defnames, fields[0], rel, lineNum)
+				if currentSection == "DEFNAME" {
+					issues = append(issues, checkBuiltinName("DEFNAME", fields[0], rel, lineNum)...)
+				}
+				index.recordAlias(currentSection, fields, rel, lineNum)
 			}
 		}
 
 		if name := parseDefnameAssignment(cleaned); name != "" {
 			upperName := strings.ToUpper(name)
-			recordDefName(defnameIndex, upperName, rel, lineNum)
+			recordDefName(index.defnames, upperName, rel, lineNum)
+			issues = append(issues, checkBuiltinName("DEFNAME", name, rel, lineNum)...)
+			if ruleEnabled("defname-prefix") {
+				issues = append(issues, checkDefnamePrefix(currentSection, name, rel, lineNum)...)
+			}
+			if currentSection == "TEMPLATE" {
+				index.templates.addAlias(upperName, section.template)
+			}
 			if currentSection == "ITEMDEF" || currentSection == "CHARDEF" || currentSection == "TEMPLATE" {
 				key := currentSection + " " + upperName
-				if _, ok := defIndex[key]; !ok {
-					defIndex[key] = definitionLocation{file: rel, line: lineNum}
+				if _, ok := index.defs[key]; !ok {
+					index.defs[key] = definitionLocation{file: rel, line: lineNum}
 				}
 			}
 		}
@@ -320,9 +720,61 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 		isFlowControl := upperToken == "IF" || upperToken == "ELIF" || upperToken == "ELSEIF" || upperToken == "WHILE"
 		isAssignment := strings.Contains(cleaned, "=") && !isFlowControl
 
+		if returnLine > 0 && !strings.HasPrefix(cleaned, "[") {
+			if !isBranchBoundary(upperToken) {
+				issues = appendError(issues, rel, lineNum, "LOGIC", fmt.Sprintf("LOGIC: unreachable statement after RETURN at line %d.", returnLine))
+			}
+			returnLine = 0
+		}
+		if upperToken == "RETURN" && !inDoBlock(stack) {
+			returnLine = lineNum
+		}
+		issues = append(issues, checkLoopControl(stack, upperToken, rel, lineNum)...)
+		noteLoopStatement(stack, upperToken, cleaned)
+		noteBranchStatement(stack, upperToken, cleaned)
+		noteDoStatement(stack, upperToken)
+		index.calls.addLine(section.caller, upperToken, cleaned, len(stack) > 0, rel, lineNum)
+
+		if isAssignment && !isDefnameSection(currentSection) {
+			issues = append(issues, checkReadOnlyAssignment(cleaned, rel, lineNum)...)
+			issues = append(issues, checkNumericLiteral(cleaned, rel, lineNum)...)
+			issues = append(issues, checkTimerValue(cleaned, rel, lineNum)...)
+		}
+		if !isTextLine && !isDefnameSection(currentSection) {
+			issues = appendDeprecationIssues(issues, rel, lineNum, findDeprecatedKeywords(cleaned, deprecated))
+		}
+		if !isDefnameSection(currentSection) {
+			issues = append(issues, checkStringFunctions(cleaned, rel, lineNum)...)
+			issues = append(issues, checkRandomExpressions(cleaned, rel, lineNum)...)
+			if currentSection != "SPELL" {
+				issues = append(issues, checkSoundCall(cleaned, rel, lineNum)...)
+			}
+			index.recordIntrinsicCalls(cleaned, rel, lineNum)
+			issues = append(issues, checkMessageLength(cleaned, rel, lineNum)...)
+			issues = append(issues, checkMessagePrefix(cleaned, rel, lineNum)...)
+		}
+		if section.locals != nil {
+			if ruleEnabled("unknown-commands") {
+				index.recordCommandUse(cleaned, rel, lineNum)
+			}
+			issues = append(issues, checkDangerousCommand(cleaned, rel, lineNum)...)
+		}
+		issues = append(issues, section.privilege.check(upperToken, cleaned, rel, lineNum)...)
+		issues = append(issues, checkFlagAssignment(section, cleaned, rel, lineNum)...)
+		issues = append(issues, section.trigger.check(cleaned, rel, lineNum)...)
+		issues = append(issues, section.locals.check(cleaned, rel, lineNum)...)
+		section.owner.addAttachments(cleaned)
+		issues = append(issues, checkDuplicateEvents(section.events, cleaned, rel, lineNum)...)
+		index.recordTriggerCall(section.owner, cleaned, rel, lineNum)
+		if ruleEnabled("tag-typos") && !isDefnameSection(currentSection) {
+			recordTagUses(index.tags, cleaned, rel, lineNum)
+		}
+
 		if !isTextLine && !isWriteFile {
-			if bracketErr := checkBrackets(cleaned); bracketErr != "" {
-				issues = appendError(issues, rel, lineNum, "SYNTAX", "SYNTAX: brackets -> "+bracketErr)
+			lead := strings.Index(raw, cleaned)
+			for _, problem := range checkBrackets(cleaned) {
+				col := utf8.RuneCountInString(raw[:lead+problem.offset]) + 1
+				issues = append(issues, lintIssue{file: rel, line: lineNum, col: col, kind: "SYNTAX", msg: "SYNTAX: brackets -> " + problem.msg})
 			}
 		}
 
@@ -333,6 +785,9 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 			if upperToken == "EN" {
 				issues = appendError(issues, rel, lineNum, "TYPO", "TYPO: 'EN' found. Did you mean 'ENDO', 'ENDDO', or 'ENDIF'?")
 			}
+			issues = append(issues, checkConstantCondition(upperToken, cleaned, rel, lineNum)...)
+			issues = append(issues, checkSingleEquals(upperToken, cleaned, rel, lineNum)...)
+			issues = append(issues, checkConditionOperators(upperToken, cleaned, rel, lineNum)...)
 			if upperToken == "IF" && strings.TrimSpace(cleaned) == "IF" {
 				issues = appendError(issues, rel, lineNum, "LOGIC", "LOGIC: empty 'IF' statement.")
 			}
@@ -366,6 +821,10 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 						expected := blockStartToEnd[last.typ]
 						if endToken != expected {
 							issues = appendError(issues, rel, lineNum, "BLOCK", fmt.Sprintf("BLOCK: mismatch. '%s' closed by '%s' (expected %s).", last.typ, upperToken, expected))
+						} else {
+							issues = append(issues, last.loop.finish(rel)...)
+							issues = append(issues, last.chain.finish(rel)...)
+							issues = append(issues, last.do.finish(rel)...)
 						}
 					}
 					continue
@@ -374,12 +833,15 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 				if upperToken == "ELSE" || upperToken == "ELIF" || upperToken == "ELSEIF" {
 					if len(stack) == 0 || stack[len(stack)-1].typ != "IF" {
 						issues = appendError(issues, rel, lineNum, "BLOCK", fmt.Sprintf("BLOCK: '%s' without matching IF.", upperToken))
+					} else {
+						issues = append(issues, stack[len(stack)-1].chain.addBranch(upperToken, cleaned, rel, lineNum)...)
 					}
 					continue
 				}
 
 				if endToken := blockStartToEnd[upperToken]; endToken != "" {
-					stack = append(stack, blockState{typ: upperToken, line: lineNum})
+					issues = append(issues, checkForBounds(upperToken, cleaned, rel, lineNum)...)
+					stack = append(stack, blockState{typ: upperToken, line: lineNum, loop: newLoopCheck(upperToken, cleaned, lineNum), chain: newIfChain(upperToken, cleaned, lineNum), do: newDoBlock(upperToken, cleaned, lineNum)})
 					continue
 				}
 			}
@@ -388,19 +850,33 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 		if !isTextLine && !isWriteFile {
 			if currentSection == "TEMPLATE" {
 				issues = append(issues, validateTemplateLine(cleaned, rel, lineNum)...)
-				collectTemplateReferences(cleaned, rel, lineNum, references)
+				collectTemplateReferences(cleaned, rel, lineNum, &index.references)
+				index.templates.addLine(section.template, cleaned, rel, lineNum)
 			}
+			index.recordVendorLine(section.defType, cleaned, rel, lineNum)
+			issues = append(issues, checkWeightedListLine(section.defType, cleaned, rel, lineNum)...)
 			if !isAliasSection(currentSection) {
-				collectReferenceUses(cleaned, rel, lineNum, references)
+				collectReferenceUses(cleaned, rel, lineNum, &index.references)
+				collectSpeechReferences(cleaned, rel, lineNum, &index.references)
+				collectCreationReferences(cleaned, rel, lineNum, &index.references)
+				issues = append(issues, checkTimerCall(cleaned, rel, lineNum, &index.references)...)
+				issues = append(issues, checkDialogCall(cleaned, rel, lineNum, &index.references)...)
 			}
 		}
 	}
 
+	lastLine := lineNum
+	if eof.line > 0 {
+		lastLine = eof.line - 1
+	}
+	issues = append(issues, index.endSection(section, rel, lastLine)...)
+	issues = append(issues, preamble.finish(rel)...)
+
 	if scanErr := scanner.Err(); scanErr != nil {
 		issues = appendError(issues, rel, lineNum, "CRITICAL", scanErr.Error())
 	}
 
-	if strings.ToUpper(strings.TrimSpace(lastNonEmpty)) != "[EOF]" {
+	if eof.line == 0 && !empty {
 		if lineNum == 0 {
 			lineNum = 1
 		}
@@ -415,8 +891,11 @@ func lintScriptFile(path string, defIndex map[string]definitionLocation, defname
 }
 
 type blockState struct {
-	typ  string
-	line int
+	typ   string
+	line  int
+	loop  *loopCheck
+	chain *ifChain
+	do    *doBlock
 }
 
 func hasExtension(path string, exts []string) bool {
@@ -429,12 +908,34 @@ func hasExtension(path string, exts []string) bool {
 }
 
 func cleanLine(line string) string {
-	if idx := strings.Index(line, "//"); idx >= 0 {
+	if idx := commentIndex(line); idx >= 0 {
 		line = line[:idx]
 	}
 	return strings.TrimSpace(line)
 }
 
+// commentIndex returns where the // comment of line starts, or -1. Slashes
+// inside double-quoted strings, in URLs (http://) and escaped as \/ do not
+// start a comment.
+func commentIndex(line string) int {
+	inQuote := false
+	for i := 0; i+1 < len(line); i++ {
+		switch {
+		case line[i] == '"':
+			inQuote = !inQuote
+		case line[i] == '\\' && line[i+1] == '/':
+			i++
+		case line[i] == '/' && line[i+1] == '/' && !inQuote:
+			if i >= 2 && line[i-1] == ':' && isAsciiLetter(line[i-2]) {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
 func hasLeadingWhitespace(line string) bool {
 	if line == "" {
 		return false
@@ -461,44 +962,94 @@ func normalizeEndToken(token string) string {
 	}
 }
 
-func checkBrackets(line string) string {
-	stack := make([]rune, 0, 8)
+// isBranchBoundary reports whether token ends the current block or starts
+// another branch of it, so code after it may run again.
+func isBranchBoundary(token string) bool {
+	if normalizeEndToken(token) != "" {
+		return true
+	}
+	return token == "ELSE" || token == "ELIF" || token == "ELSEIF"
+}
+
+// quotedText marks the bytes of line inside double-quoted string literals.
+// <...> expressions are left unmarked: the server evaluates them inside
+// strings too.
+func quotedText(line string) []bool {
+	quoted := make([]bool, len(line))
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		if line[i] == '<' && i+1 < len(line) && isAngleTokenStart(line[i+1]) {
+			if end, ok := scanAngleExpression(line, i+1); ok {
+				i = end
+				continue
+			}
+		}
+		if line[i] == '"' {
+			inQuote = !inQuote
+			continue
+		}
+		quoted[i] = inQuote
+	}
+	return quoted
+}
+
+// bracketProblem is a bracket mismatch at a byte offset of a line.
+type bracketProblem struct {
+	offset int
+	msg    string
+}
+
+// checkBrackets returns every bracket mismatch of line. After a mismatch
+// the scan goes on: a stray closing bracket is skipped, one that matches an
+// outer bracket closes it and the ones opened inside it, and any other one
+// is taken as a mistyped close of the innermost bracket.
+func checkBrackets(line string) []bracketProblem {
+	var problems []bracketProblem
+	var stack []int
+	quoted := quotedText(line)
 	for i := 0; i < len(line); i++ {
 		ch := line[i]
+		if quoted[i] {
+			continue
+		}
 		switch ch {
 		case '(', '[', '{':
-			stack = append(stack, rune(ch))
+			stack = append(stack, i)
 		case '<':
 			if i+1 < len(line) && isAngleTokenStart(line[i+1]) {
 				end, ok := scanAngleExpression(line, i+1)
 				if !ok {
-					return "unclosed '<'"
+					problems = append(problems, bracketProblem{offset: i, msg: "unclosed '<'"})
+					continue
 				}
 				i = end
-				continue
 			}
-			continue
 		case ')', ']', '}':
+			expected := byte(bracketPairs[rune(ch)])
 			if len(stack) == 0 {
-				return fmt.Sprintf("unexpected closing '%c'", ch)
+				problems = append(problems, bracketProblem{offset: i, msg: fmt.Sprintf("unexpected closing '%c'", ch)})
+				continue
 			}
-			expected := bracketPairs[rune(ch)]
-			if stack[len(stack)-1] != expected {
-				return fmt.Sprintf("expected closing '%c' but found '%c'", stack[len(stack)-1], ch)
+			top := line[stack[len(stack)-1]]
+			if top == expected {
+				stack = stack[:len(stack)-1]
+				continue
 			}
-			stack = stack[:len(stack)-1]
-		case '>':
-			continue
+			problems = append(problems, bracketProblem{offset: i, msg: fmt.Sprintf("expected closing '%c' but found '%c'", top, ch)})
+			closes := len(stack) - 1
+			for j := closes - 1; j >= 0; j-- {
+				if line[stack[j]] == expected {
+					closes = j
+					break
+				}
+			}
+			stack = stack[:closes]
 		}
 	}
-	if len(stack) > 0 {
-		parts := make([]string, 0, len(stack))
-		for _, ch := range stack {
-			parts = append(parts, string(ch))
-		}
-		return "unclosed: " + strings.Join(parts, ", ")
+	for _, open := range stack {
+		problems = append(problems, bracketProblem{offset: open, msg: fmt.Sprintf("unclosed '%c'", line[open])})
 	}
-	return ""
+	return problems
 }
 
 func scanAngleExpression(line string, start int) (int, bool) {
@@ -605,19 +1156,31 @@ func printError(e lintIssue) {
 	if e.line <= 0 {
 		e.line = 1
 	}
+	command, label := "error", "ERROR"
+	if e.severity == severityWarning {
+		command, label = "warning", "WARNING"
+	}
+	position := fmt.Sprintf("%s:%d", e.file, e.line)
+	if e.col > 0 {
+		position = fmt.Sprintf("%s:%d", position, e.col)
+	}
 	if isGitHubActions() {
 		msg := e.msg
 		if e.file != "" {
-			msg = fmt.Sprintf("%s:%d: %s", e.file, e.line, msg)
+			msg = fmt.Sprintf("%s: %s", position, msg)
 		}
-		fmt.Printf("::error file=%s,line=%d::%s\n", e.file, e.line, escapeAnnotation(msg))
+		location := fmt.Sprintf("file=%s,line=%d", e.file, e.line)
+		if e.col > 0 {
+			location = fmt.Sprintf("%s,col=%d", location, e.col)
+		}
+		fmt.Printf("::%s %s::%s\n", command, location, escapeAnnotation(msg))
 		return
 	}
 	if e.file != "" {
-		fmt.Printf("ERROR %s:%d: %s\n", e.file, e.line, e.msg)
+		fmt.Printf("%s %s: %s\n", label, position, e.msg)
 		return
 	}
-	fmt.Printf("ERROR %s\n", e.msg)
+	fmt.Printf("%s %s\n", label, e.msg)
 }
 
 func isGitHubActions() bool {
@@ -662,6 +1225,10 @@ func appendError(errors []lintIssue, rel string, lineNum int, kind, msg string)
 	return append(errors, lintIssue{file: rel, line: lineNum, kind: kind, msg: msg})
 }
 
+func appendWarning(issues []lintIssue, rel string, lineNum int, kind, msg string) []lintIssue {
+	return append(issues, lintIssue{file: rel, line: lineNum, kind: kind, msg: msg, severity: severityWarning})
+}
+
 func countFields(line string, max int) int {
 	count := 0
 	inField := false
@@ -718,11 +1285,16 @@ func isTextKeyword(token string) bool {
 }
 
 func collectReferenceUses(line, file string, lineNum int, references *[]referenceUse) {
-	for _, pattern := range refPatterns {
+	patterns := refPatterns
+	if re := speechReferencePattern(); re != nil {
+		patterns = append(patterns[:len(patterns):len(patterns)], referencePattern{re: re, defTypes: []string{"SPEECH"}})
+	}
+	quoted := quotedText(line)
+	for _, pattern := range patterns {
 		indices := pattern.re.FindAllStringIndex(line, -1)
 		for _, idx := range indices {
 			match := line[idx[0]:idx[1]]
-			if shouldSkipDynamicID(line, idx[1], match) {
+			if quoted[idx[0]] || shouldSkipDynamicID(line, idx[1], match) {
 				continue
 			}
 			*references = append(*references, referenceUse{
@@ -730,11 +1302,62 @@ func collectReferenceUses(line, file string, lineNum int, references *[]referenc
 				line:     lineNum,
 				defTypes: pattern.defTypes,
 				id:       strings.ToUpper(match),
+				text:     match,
 			})
 		}
 	}
 }
 
+func collectSpeechReferences(line, file string, lineNum int, references *[]referenceUse) {
+	match := speechAssignPattern.FindStringSubmatch(line)
+	if len(match) != 2 {
+		return
+	}
+	for _, entry := range strings.Split(match[1], ",") {
+		id := firstField(strings.TrimLeft(strings.TrimSpace(entry), "+-"))
+		if id == "" || strings.ContainsAny(id, "<>") || isAllDigits(id) {
+			continue
+		}
+		*references = append(*references, referenceUse{
+			file:     file,
+			line:     lineNum,
+			defTypes: []string{"SPEECH"},
+			id:       strings.ToUpper(id),
+		})
+	}
+}
+
+// collectCreationReferences resolves the first argument of NEWITEM, NEWNPC
+// and NEWLOOT calls, so unprefixed defnames are checked too. Prefixed IDs
+// are left to collectReferenceUses.
+func collectCreationReferences(line, file string, lineNum int, references *[]referenceUse) {
+	match := creationCallPattern.FindStringSubmatch(line)
+	if len(match) != 3 {
+		return
+	}
+	id := match[2]
+	if !isIdentifier(id) || hasReferencePrefix(id) {
+		return
+	}
+	*references = append(*references, referenceUse{
+		file:     file,
+		line:     lineNum,
+		defTypes: creationDefTypes[strings.ToUpper(match[1])],
+		id:       strings.ToUpper(id),
+	})
+}
+
+// hasReferencePrefix reports whether id is already picked up by one of the
+// prefix-based refPatterns.
+func hasReferencePrefix(id string) bool {
+	for _, pattern := range refPatterns {
+		if pattern.re.MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
 func collectTemplateReferences(line, file string, lineNum int, references *[]referenceUse) {
 	if match := itemAssignPattern.FindStringSubmatch(line); len(match) == 2 {
 		for _, ident := range extractTemplateIdentifiers(match[1]) {
@@ -788,6 +1411,9 @@ func appendTemplateSelectorIssues(issues []lintIssue, file string, lineNum int,
 	for _, msg := range validateTemplateRSelectors(value) {
 		issues = appendError(issues, file, lineNum, "SYNTAX", msg)
 	}
+	for _, msg := range validateWeightedLists(value) {
+		issues = appendError(issues, file, lineNum, "SYNTAX", msg)
+	}
 	return issues
 }
 
@@ -919,6 +1545,49 @@ func shouldSkipDynamicID(line string, end int, match string) bool {
 	return true
 }
 
+// lintIndexIssues runs the checks that need the definitions of every scanned
+// file.
+func lintIndexIssues(index *lintIndex) []lintIssue {
+	issues := findUndefinedReferences(index.references, index.defs, index.defnames, index.ids)
+	issues = append(issues, findDialogIssues(index.dialogs)...)
+	issues = append(issues, findTriggerCallIssues(index.owners, index.triggerCalls)...)
+	issues = append(issues, index.templates.findCycles()...)
+	issues = append(issues, findVendorTemplateIssues(index)...)
+	issues = append(issues, findIntrinsicTypos(index)...)
+	issues = append(issues, findUnknownCommands(index)...)
+	issues = append(issues, index.calls.findRecursion(index.defs)...)
+	issues = append(issues, findUnregisteredScripts(index)...)
+	issues = append(issues, index.regions.findRoomsOutside()...)
+	issues = append(issues, findBrokenAliases(index)...)
+	if ruleEnabled("tag-typos") {
+		issues = append(issues, findTagTypos(index.tags)...)
+	}
+	if ruleEnabled("numeric-references") {
+		issues = append(issues, findUndefinedNumericIDs(index)...)
+	}
+	if ruleEnabled("reference-casing") {
+		issues = append(issues, findCasingMismatches(index)...)
+	}
+	return issues
+}
+
+// referenceResolved reports whether ref names a DEFNAME, a defined ID or a
+// definition of one of its types.
+func referenceResolved(ref referenceUse, defIndex, defnameIndex, idIndex map[string]definitionLocation) bool {
+	if _, ok := defnameIndex[ref.id]; ok {
+		return true
+	}
+	if _, ok := idIndex[ref.id]; ok {
+		return true
+	}
+	for _, defType := range ref.defTypes {
+		if _, ok := defIndex[defType+" "+ref.id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func findUndefinedReferences(references []referenceUse, defIndex map[string]definitionLocation, defnameIndex map[string]definitionLocation, idIndex map[string]definitionLocation) []lintIssue {
 	if len(references) == 0 {
 		return nil
@@ -926,21 +1595,7 @@ func findUndefinedReferences(references []referenceUse, defIndex map[string]defi
 	var errors []lintIssue
 	seen := make(map[string]bool)
 	for _, ref := range references {
-		if _, ok := defnameIndex[ref.id]; ok {
-			continue
-		}
-		if _, ok := idIndex[ref.id]; ok {
-			continue
-		}
-		found := false
-		for _, defType := range ref.defTypes {
-			key := defType + " " + ref.id
-			if _, ok := defIndex[key]; ok {
-				found = true
-				break
-			}
-		}
-		if found {
+		if referenceResolved(ref, defIndex, defnameIndex, idIndex) {
 			continue
 		}
 		typeLabel := strings.Join(ref.defTypes, "/")
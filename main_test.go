@@ -47,6 +47,78 @@ func TestLintLogicErrors(t *testing.T) {
 	})
 }
 
+func TestLintUnreachableCode(t *testing.T) {
+	t.Run("StatementAfterReturn", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"SRC.SYSMESSAGE hello",
+			"RETURN 1",
+			"SRC.SYSMESSAGE never",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "unreachable_return.scp", content)
+		assertHasMessage(t, errs, "LOGIC: unreachable statement after RETURN at line 3.")
+	})
+
+	t.Run("ReturnInsideBlock", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"IF (<SRC.ISGM>)",
+			"  RETURN 1",
+			"  SAY never",
+			"ENDIF",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "unreachable_block.scp", content)
+		assertHasMessage(t, errs, "LOGIC: unreachable statement after RETURN at line 4.")
+	})
+
+	t.Run("ReachableCode", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"IF (<SRC.ISGM>)",
+			"  RETURN 1",
+			"ELIF (<SRC.ISPLAYER>)",
+			"  RETURN 0",
+			"ELSE",
+			"  RETURN 1",
+			"ENDIF",
+			"SAY reachable",
+			"RETURN 0",
+			"ON=@Click",
+			"RETURN 1",
+			"[FUNCTION f_test]",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "unreachable_ok.scp", content), "reachable code")
+	})
+
+	t.Run("ReturnInDoBlock", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_roll]",
+			"DORAND 3",
+			"  RETURN 1",
+			"  RETURN 2",
+			"  RETURN 3",
+			"ENDDO",
+			"[FUNCTION f_pick]",
+			"DOSWITCH <ARGN>",
+			"  RETURN 0",
+			"  RETURN 1",
+			"ENDDO",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "unreachable_do.scp", content), "RETURN lines of DORAND and DOSWITCH")
+	})
+}
+
 func TestLintBlockErrors(t *testing.T) {
 	t.Run("TriggerStartsNewBlock", func(t *testing.T) {
 		content := joinLines(
@@ -157,6 +229,8 @@ func TestLintReferenceErrors(t *testing.T) {
 				content: joinLines(
 					"[RESDEFNAME backward_compatibility_defs]",
 					"i_dragon_egg_lamp_s i_lamp_dragon_s",
+					"[ITEMDEF i_lamp_dragon_s]",
+					"ID=01e7",
 					"[DEFNAME items_test]",
 					"random_lamps { i_dragon_egg_lamp_s 1 }",
 					"[EOF]",
@@ -167,6 +241,8 @@ func TestLintReferenceErrors(t *testing.T) {
 				content: joinLines(
 					"[RES_RESDEFNAME backward_compatibility_defs]",
 					"i_dragon_egg_lamp_s i_lamp_dragon_s",
+					"[ITEMDEF i_lamp_dragon_s]",
+					"ID=01e7",
 					"[DEFNAME items_test]",
 					"random_lamps { i_dragon_egg_lamp_s 1 }",
 					"[EOF]",
@@ -195,6 +271,93 @@ func TestLintReferenceErrors(t *testing.T) {
 	})
 }
 
+func TestLintSpeechReferences(t *testing.T) {
+	t.Run("DefinedSpeech", func(t *testing.T) {
+		content := joinLines(
+			"[SPEECH spk_human_prime]",
+			"ON=*hello*",
+			"SAY Hello!",
+			"[SPEECH greetings]",
+			"[CHARDEF c_test]",
+			"SPEECH=spk_human_prime,greetings",
+			"ON=@Create",
+			"SPEECH=+spk_human_prime",
+			"SPEECH=-greetings",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "speech_valid.scp", content), "defined speech")
+	})
+
+	t.Run("UndefinedSpeech", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_test]",
+			"SPEECH=+spk_missing, vendor_talk",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "speech_missing.scp", content)
+		assertHasMessage(t, errs, "UNDECLARED: 'SPK_MISSING' not defined as SPEECH")
+		assertHasMessage(t, errs, "UNDECLARED: 'VENDOR_TALK' not defined as SPEECH")
+	})
+
+	t.Run("ConfiguredPrefix", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.SpeechPrefix = "talk_" })
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"SRC.SPEECH=+talk_missing",
+			"SERV.LOG talk_other",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "speech_prefix.scp", content)
+		assertHasMessage(t, errs, "UNDECLARED: 'TALK_MISSING' not defined as SPEECH")
+		assertHasMessage(t, errs, "UNDECLARED: 'TALK_OTHER' not defined as SPEECH")
+	})
+}
+
+func TestLintCreationReferences(t *testing.T) {
+	t.Run("DefinedTargets", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF 0eed]",
+			"DEFNAME=gold_coins",
+			"[TEMPLATE random_loot]",
+			"ITEM=gold_coins",
+			"[CHARDEF 01]",
+			"DEFNAME=ogre_lord",
+			"[FUNCTION f_test]",
+			"SERV.NEWITEM gold_coins",
+			"SRC.NEWITEM=random_loot,5",
+			"SERV.NEWNPC=ogre_lord",
+			"NEWLOOT random_loot",
+			"LOCAL.ID=<ARGS>",
+			"SERV.NEWITEM <LOCAL.ID>",
+			"SERV.NEWITEM 0eed",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "creation_valid.scp", content), "defined creation targets")
+	})
+
+	t.Run("UndefinedTargets", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"SERV.NEWITEM gold_coinz",
+			"SERV.NEWNPC=ogre_lordd",
+			"SERV.NEWITEM i_missing",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "creation_missing.scp", content)
+		assertHasMessage(t, errs, "UNDECLARED: 'GOLD_COINZ' not defined as ITEMDEF/TEMPLATE")
+		assertHasMessage(t, errs, "UNDECLARED: 'OGRE_LORDD' not defined as CHARDEF")
+		assertHasMessage(t, errs, "UNDECLARED: 'I_MISSING' not defined as ITEMDEF")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 errors, got %d", len(errs))
+		}
+	})
+}
+
 func TestLintSyntaxErrors(t *testing.T) {
 	t.Run("InvalidBrackets", func(t *testing.T) {
 		cases := []struct {
@@ -277,28 +440,25 @@ func TestLintDuplicateDefinitions(t *testing.T) {
 		for _, defType := range defTypes {
 			defType := defType
 			t.Run(defType, func(t *testing.T) {
-				defIndex := map[string]definitionLocation{}
-				defnameIndex := map[string]definitionLocation{}
-				idIndex := map[string]definitionLocation{}
-				var references []referenceUse
+				index := newLintIndex()
 				contentA := buildDefContent(defType, "dup")
 				contentB := buildDefContent(defType, "dup")
 
 				pathA := writeTempFile(t, dir, "dup_"+strings.ToLower(defType)+"_a.scp", contentA)
 				pathB := writeTempFile(t, dir, "dup_"+strings.ToLower(defType)+"_b.scp", contentB)
 
-				assertNoErrors(t, lintScriptFile(pathA, defIndex, defnameIndex, idIndex, &references), "first "+defType+" def")
-				assertHasMessage(t, lintScriptFile(pathB, defIndex, defnameIndex, idIndex, &references), "DUPLICATE: '"+defType+" DUP' already defined")
+				assertNoErrors(t, lintScriptFile(pathA, index), "first "+defType+" def")
+				assertHasMessage(t, lintScriptFile(pathB, index), "DUPLICATE: '"+defType+" DUP' already defined")
 			})
 		}
 	})
 
 	t.Run("DialogSections", func(t *testing.T) {
 		content := joinLines(
-			"[DIALOG dialog]",
-			"[DIALOG dialog TEXT]",
-			"[DIALOG dialog BUTTON]",
-			"[DIALOG dialog TEXT]",
+			"[DIALOG d_main]",
+			"[DIALOG d_main TEXT]",
+			"[DIALOG d_main BUTTON]",
+			"[DIALOG d_main TEXT]",
 			"[EOF]",
 		)
 
@@ -307,7 +467,7 @@ func TestLintDuplicateDefinitions(t *testing.T) {
 		if len(errs) != 1 {
 			t.Fatalf("expected 1 error, got %d", len(errs))
 		}
-		assertHasMessage(t, errs, "DUPLICATE: 'DIALOG DIALOG TEXT' already defined")
+		assertHasMessage(t, errs, "DUPLICATE: 'DIALOG D_MAIN TEXT' already defined")
 	})
 }
 
@@ -439,14 +599,11 @@ func joinLines(lines ...string) string {
 func lintFromContent(t *testing.T, name, content string) []lintIssue {
 	t.Helper()
 	dir := withTempScriptsDir(t)
-	defIndex := map[string]definitionLocation{}
-	defnameIndex := map[string]definitionLocation{}
-	idIndex := map[string]definitionLocation{}
-	var references []referenceUse
+	index := newLintIndex()
 
 	path := writeTempFile(t, dir, name, content)
-	errs := lintScriptFile(path, defIndex, defnameIndex, idIndex, &references)
-	errs = append(errs, findUndefinedReferences(references, defIndex, defnameIndex, idIndex)...)
+	errs := lintScriptFile(path, index)
+	errs = append(errs, lintIndexIssues(index)...)
 	return errs
 }
 
@@ -460,11 +617,11 @@ func writeTempFile(t *testing.T, dir, name, content string) string {
 }
 
 func buildDefContent(defType, id string) string {
-	return strings.Join([]string{
-		"[" + defType + " " + id + "]",
-		"[EOF]",
-		"",
-	}, "\n")
+	lines := []string{"[" + defType + " " + id + "]"}
+	if defType == "TYPEDEF" {
+		lines = append(lines, "ON=@DClick", "RETURN 1")
+	}
+	return strings.Join(append(lines, "[EOF]", ""), "\n")
 }
 
 func withTempScriptsDir(t *testing.T) string {
@@ -476,6 +633,15 @@ func withTempScriptsDir(t *testing.T) string {
 	return dir
 }
 
+func withConfig(t *testing.T, edit func(cfg *lintConfig)) {
+	t.Helper()
+	prevConfig := config
+	cfg := defaultConfig()
+	edit(&cfg)
+	config = cfg
+	t.Cleanup(func() { config = prevConfig })
+}
+
 func assertNoErrors(t *testing.T, errs []lintIssue, context string) {
 	t.Helper()
 	if len(errs) == 0 {
@@ -497,3 +663,73 @@ func assertHasMessage(t *testing.T, errs []lintIssue, needle string) {
 	}
 	t.Fatalf("expected error containing %q", needle)
 }
+
+func TestCleanLineComments(t *testing.T) {
+	cases := map[string]string{
+		"SRC.SYSMESSAGE hi // greet":                 "SRC.SYSMESSAGE hi",
+		`SAY "visit http://myshard.com"`:             `SAY "visit http://myshard.com"`,
+		"SAY visit https://myshard.com // our site":  "SAY visit https://myshard.com",
+		`TAG.PATH="a//b" // quoted`:                  `TAG.PATH="a//b"`,
+		`SRC.SYSMESSAGE 1 \// 2`:                     `SRC.SYSMESSAGE 1 \// 2`,
+		"// whole line":                              "",
+		`SRC.SYSMESSAGE "unterminated // still text`: `SRC.SYSMESSAGE "unterminated // still text`,
+		"IF (<SRC.STR> > 10) // strong ( enough":     "IF (<SRC.STR> > 10)",
+	}
+	for line, want := range cases {
+		if got := cleanLine(line); got != want {
+			t.Errorf("cleanLine(%q) = %q, want %q", line, got, want)
+		}
+	}
+
+	errs := lintFromContent(t, "urls.scp", joinLines(
+		"[FUNCTION f_site]",
+		`SRC.SYSMESSAGE "Visit http://myshard.com (forum)"`,
+		"SERV.LOG see https://myshard.com/wiki?page=(rules)",
+		"[EOF]",
+	))
+	assertNoErrors(t, errs, "URLs and quoted slashes")
+}
+
+func TestLintQuotedText(t *testing.T) {
+	errs := lintFromContent(t, "quotes.scp", joinLines(
+		"[FUNCTION f_quotes]",
+		`SERV.LOG "(laughs"`,
+		`SERV.LOG "Lord British]: see i_ghost_sword for c_ghost}"`,
+		`SERV.LOG "You found <SERV.ITEMDEF.i_phantom.NAME> :)"`,
+		`SERV.LOG ("done"`,
+		"[EOF]",
+	))
+	assertHasMessage(t, errs, "UNDECLARED: 'I_PHANTOM'")
+	assertHasMessage(t, errs, "SYNTAX: brackets -> unclosed '('")
+	if len(errs) != 2 {
+		t.Fatalf("expected only the reference inside <...> and the unclosed bracket outside the quotes, got %+v", errs)
+	}
+}
+
+func TestLintBracketPositions(t *testing.T) {
+	errs := lintFromContent(t, "brackets.scp", joinLines(
+		"[FUNCTION f_brackets]",
+		"\tIF (<ARGN1> > 1]) && (<ARGN2> == 2) // two problems",
+		"ENDIF",
+		"SERV.LOG ) (x] [y",
+		"[EOF]",
+	))
+	want := []struct {
+		line, col int
+		msg       string
+	}{
+		{2, 17, "SYNTAX: brackets -> expected closing '(' but found ']'"},
+		{2, 18, "SYNTAX: brackets -> unexpected closing ')'"},
+		{4, 10, "SYNTAX: brackets -> unexpected closing ')'"},
+		{4, 14, "SYNTAX: brackets -> expected closing '(' but found ']'"},
+		{4, 16, "SYNTAX: brackets -> unclosed '['"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d bracket problems, got %+v", len(want), errs)
+	}
+	for i, w := range want {
+		if errs[i].line != w.line || errs[i].col != w.col || errs[i].msg != w.msg {
+			t.Fatalf("expected problem %d at %d:%d %q, got %+v", i, w.line, w.col, w.msg, errs[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// menuState tracks the structure of one [MENU m_x] section: a title line
+// followed by ON=<id> <text> option lines, each with its own body.
+type menuState struct {
+	id       string
+	line     int
+	sawTitle bool
+	options  int
+}
+
+func newMenuState(args string, lineNum int) *menuState {
+	return &menuState{id: strings.ToUpper(firstField(args)), line: lineNum}
+}
+
+// addTitle consumes the first statement line of the section as the menu
+// title. It reports whether the line was taken.
+func (m *menuState) addTitle(line string) bool {
+	if m == nil || m.sawTitle || m.options > 0 || strings.HasPrefix(line, "[") {
+		return false
+	}
+	m.sawTitle = true
+	return true
+}
+
+// addOption validates an ON= option line and records the item or char ID it
+// displays as a reference.
+func (m *menuState) addOption(line, file string, lineNum int, references *[]referenceUse) []lintIssue {
+	var issues []lintIssue
+	if !m.sawTitle && m.options == 0 {
+		issues = appendError(issues, file, m.line, "MENU", fmt.Sprintf("MENU: %s is missing its title line", m.id))
+	}
+	m.options++
+
+	_, value, _ := splitAssignment(line)
+	if strings.HasPrefix(value, "@") {
+		return appendError(issues, file, lineNum, "MENU", fmt.Sprintf("MENU: trigger '%s' is not valid in a MENU section (expected ON=<id> <text>)", value))
+	}
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return appendError(issues, file, lineNum, "MENU", "MENU: option is missing its text (expected ON=<id> <text>)")
+	}
+	id := fields[0]
+	if _, ok := parseSphereInt(id); ok || strings.ContainsAny(id, "<>") {
+		return issues
+	}
+	if !isIdentifier(id) {
+		return appendError(issues, file, lineNum, "MENU", fmt.Sprintf("MENU: option ID '%s' is not a number or defname", id))
+	}
+	*references = append(*references, referenceUse{
+		file:     file,
+		line:     lineNum,
+		defTypes: []string{"ITEMDEF", "CHARDEF"},
+		id:       strings.ToUpper(id),
+	})
+	return issues
+}
+
+// finish reports menus that have a title but no options to choose from.
+func (m *menuState) finish(file string) []lintIssue {
+	if m == nil || !m.sawTitle || m.options > 0 {
+		return nil
+	}
+	return appendError(nil, file, m.line, "MENU", fmt.Sprintf("MENU: %s has no ON= options", m.id))
+}
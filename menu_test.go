@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestLintMenuSections(t *testing.T) {
+	t.Run("ValidMenu", func(t *testing.T) {
+		content := joinLines(
+			"[MENU m_test]",
+			"Choose a reward (one only)",
+			"ON=0 Nothing",
+			"RETURN 1",
+			"ON=i_gold Gold coins",
+			"SERV.NEWITEM i_gold",
+			"ON=0x0eed Gold pile",
+			"f_give_gold",
+			"[ITEMDEF i_gold]",
+			"[FUNCTION f_give_gold]",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "menu_valid.scp", content), "valid menu")
+	})
+
+	t.Run("MissingTitle", func(t *testing.T) {
+		content := joinLines(
+			"[MENU m_test]",
+			"ON=0 Nothing",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "menu_no_title.scp", content)
+		assertHasMessage(t, errs, "MENU: M_TEST is missing its title line")
+	})
+
+	t.Run("MalformedOptions", func(t *testing.T) {
+		content := joinLines(
+			"[MENU m_test]",
+			"Choose",
+			"ON=@Click",
+			"ON=0",
+			"ON=i-sword Sword",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "menu_options.scp", content)
+		assertHasMessage(t, errs, "MENU: trigger '@Click' is not valid in a MENU section")
+		assertHasMessage(t, errs, "MENU: option is missing its text")
+		assertHasMessage(t, errs, "MENU: option ID 'i-sword' is not a number or defname")
+	})
+
+	t.Run("NoOptions", func(t *testing.T) {
+		content := joinLines(
+			"[MENU m_test]",
+			"Choose",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "menu_empty.scp", content)
+		assertHasMessage(t, errs, "MENU: M_TEST has no ON= options")
+	})
+
+	t.Run("UndefinedReferences", func(t *testing.T) {
+		content := joinLines(
+			"[MENU m_test]",
+			"Choose",
+			"ON=i_missing Missing item",
+			"f_missing",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "menu_refs.scp", content)
+		assertHasMessage(t, errs, "UNDECLARED: 'I_MISSING' not defined as ITEMDEF/CHARDEF")
+		assertHasMessage(t, errs, "UNDECLARED: 'F_MISSING' not defined as FUNCTION")
+	})
+}
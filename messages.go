@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// messageVerbs are the statements that show their text to players. The
+	// UA variants take color, font, mode and language before the text.
+	messageVerbs = map[string]int{
+		"EMOTE": 0, "MESSAGE": 0, "MESSAGEUA": 4, "SAY": 0, "SAYU": 0,
+		"SAYUA": 4, "SYSMESSAGE": 0, "SYSMESSAGEUA": 4,
+	}
+
+	// messagePrefixLimits are the largest values of the @color,font,mode
+	// fields that may start SAY/MESSAGE text.
+	messagePrefixLimits = []struct {
+		name string
+		max  int
+	}{{"color", 0xFFFF}, {"font", 12}, {"mode", 15}}
+
+	messageVerbPattern = regexp.MustCompile(`^(?:[A-Za-z0-9_.]+\.)?([A-Za-z]+)(?:\s*=\s*|\s+)(.*)$`)
+	expressionPattern  = regexp.MustCompile(`<[^<>]*>`)
+)
+
+// checkMessageLength warns about player messages whose literal text alone is
+// longer than the client shows in one message; the rest is cut off in game.
+// <> expressions are left out of the count since their length is unknown.
+func checkMessageLength(line, file string, lineNum int) []lintIssue {
+	match := messageVerbPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	verb := strings.ToUpper(match[1])
+	skip, ok := messageVerbs[verb]
+	if !ok {
+		return nil
+	}
+	text := match[2]
+	if skip > 0 {
+		parts := strings.SplitN(text, ",", skip+1)
+		if len(parts) <= skip {
+			return nil
+		}
+		text = parts[skip]
+	} else if strings.HasPrefix(text, "@") {
+		_, text, _ = strings.Cut(text, " ")
+	}
+	for {
+		stripped := expressionPattern.ReplaceAllString(text, "")
+		if stripped == text {
+			break
+		}
+		text = stripped
+	}
+	length := utf8.RuneCountInString(strings.TrimSpace(text))
+	if length <= config.MaxMessageLength {
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "TEXT", fmt.Sprintf("TEXT: %s text is %d characters; the client cuts messages after %d.", verb, length, config.MaxMessageLength))
+}
+
+// checkMessagePrefix validates the @color,font,mode prefix of SAY/MESSAGE
+// style text. Each field may be left empty but must otherwise be a number in
+// range, and the prefix ends at the first space; a malformed prefix is not
+// recognised and players see it spoken as part of the text.
+func checkMessagePrefix(line, file string, lineNum int) []lintIssue {
+	match := messageVerbPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	verb := strings.ToUpper(match[1])
+	if skip, ok := messageVerbs[verb]; !ok || skip > 0 || !strings.HasPrefix(match[2], "@") {
+		return nil
+	}
+	prefix, _, _ := strings.Cut(match[2], " ")
+	if strings.ContainsAny(prefix, "<>") {
+		return nil
+	}
+	fields := strings.Split(prefix[1:], ",")
+	if len(fields) > len(messagePrefixLimits) {
+		return appendError(nil, file, lineNum, "TEXT", fmt.Sprintf("TEXT: %s prefix '%s' has %d fields; use @color,font,mode followed by a space.", verb, prefix, len(fields)))
+	}
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+		limit := messagePrefixLimits[i]
+		n, ok := parseSphereInt(field)
+		if !ok {
+			return appendError(nil, file, lineNum, "TEXT", fmt.Sprintf("TEXT: %s prefix '%s' has a %s '%s' that is not a number; players see the prefix as text.", verb, prefix, limit.name, field))
+		}
+		if n < 0 || n > limit.max {
+			return appendError(nil, file, lineNum, "TEXT", fmt.Sprintf("TEXT: %s prefix '%s' has %s %s outside 0..%d.", verb, prefix, limit.name, field, limit.max))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintMessageLength(t *testing.T) {
+	long := strings.Repeat("a", 130)
+	content := joinLines(
+		"[FUNCTION f_talk]",
+		"SAY "+long,
+		"SRC.SYSMESSAGE="+long,
+		"SRC.SYSMESSAGEUA 021,3,0,enu,"+long,
+		"SRC.SYSMESSAGE <SRC.NAME> "+strings.Repeat("b", 120)+" <SRC.TAG.TITLE>",
+		"EMOTE short",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "messages.scp", content)
+	assertHasMessage(t, errs, "TEXT: SAY text is 130 characters; the client cuts messages after 128.")
+	assertHasMessage(t, errs, "TEXT: SYSMESSAGE text is 130 characters")
+	assertHasMessage(t, errs, "TEXT: SYSMESSAGEUA text is 130 characters")
+	if len(errs) != 3 || errs[2].line != 4 {
+		t.Fatalf("expected 3 warnings on lines 2-4, got %+v", errs)
+	}
+
+	t.Run("ConfiguredLimit", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.MaxMessageLength = 200
+		})
+		assertNoErrors(t, lintFromContent(t, "messages.scp", content), "messages within maxMessageLength")
+	})
+}
+
+func TestLintMessagePrefixes(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_announce]",
+		"SAY @0481,3,1 Welcome!",
+		"SRC.MESSAGE @021 Ouch",
+		"SAY @,,9 Quietly",
+		"SAY @<ARGN1> Colored",
+		"SRC.SYSMESSAGEUA 021,3,0,enu,@ not a prefix here",
+		"SAY @red Hello",
+		"SAY @0481,3,1,2 Too many",
+		"SRC.SYSMESSAGE @0481,20 Big font",
+		"EMOTE @0481,3,99 Shrugs",
+		"SAY @0481,3Hello",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "prefixes.scp", content)
+	assertHasMessage(t, errs, "TEXT: SAY prefix '@red' has a color 'red' that is not a number; players see the prefix as text.")
+	assertHasMessage(t, errs, "TEXT: SAY prefix '@0481,3,1,2' has 4 fields; use @color,font,mode followed by a space.")
+	assertHasMessage(t, errs, "TEXT: SYSMESSAGE prefix '@0481,20' has font 20 outside 0..12.")
+	assertHasMessage(t, errs, "TEXT: EMOTE prefix '@0481,3,99' has mode 99 outside 0..15.")
+	assertHasMessage(t, errs, "TEXT: SAY prefix '@0481,3Hello' has a font '3Hello' that is not a number")
+	if len(errs) != 5 || errs[0].line != 7 {
+		t.Fatalf("expected 5 errors on lines 7-11, got %+v", errs)
+	}
+}
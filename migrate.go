@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+type migrationFinding struct {
+	file  string
+	line  int
+	match deprecationMatch
+}
+
+// runMigrate implements "sphere-lint migrate --from=55i --to=56d": it runs
+// only the version-difference rules between two server versions and prints
+// a report grouped by rule.
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint migrate", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	from := flags.String("from", "", "server version the scripts currently target")
+	to := flags.String("to", "", "server version to migrate to")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if !isKnownVersion(*from) || !isKnownVersion(*to) {
+		fmt.Fprintf(os.Stderr, "migrate: --from and --to must be one of %s\n", strings.Join(serverVersions, ", "))
+		return 2
+	}
+	if versionIndex(*from) >= versionIndex(*to) {
+		fmt.Fprintf(os.Stderr, "migrate: --from (%s) must be older than --to (%s)\n", *from, *to)
+		return 2
+	}
+
+	rules := migrationRules(*from, *to)
+	var findings []migrationFinding
+	scannedFiles := 0
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		scannedFiles++
+		findings = append(findings, scanMigrationFile(path, rules)...)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	printMigrationReport(findings, rules, *from, *to)
+	fmt.Println("---------------------------------------------")
+	fmt.Printf("Files scanned: %d\n", scannedFiles)
+	fmt.Printf("Migration findings: %d\n", len(findings))
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// scanMigrationFile applies the migration rules to every script line of a
+// file, skipping COMMENT/BOOK text and DEFNAME tables.
+func scanMigrationFile(path string, rules map[string]deprecation) []migrationFinding {
+	rel := toRelative(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	content, _ := decodeScript(data, rel)
+
+	var findings []migrationFinding
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	inTextBlock := false
+	section := ""
+	for scanner.Scan() {
+		lineNum++
+		cleaned := cleanLine(scanner.Text())
+		if cleaned == "" {
+			continue
+		}
+		if commentHeaderPattern.MatchString(cleaned) {
+			inTextBlock = true
+			continue
+		}
+		if defMatch := defHeaderPattern.FindStringSubmatch(cleaned); len(defMatch) == 3 {
+			section = strings.ToUpper(defMatch[1])
+			inTextBlock = section == "BOOK" || section == "COMMENT"
+			continue
+		}
+		if inTextBlock || isDefnameSection(section) {
+			continue
+		}
+		var matches []deprecationMatch
+		if triggerPattern.MatchString(cleaned) {
+			matches = findDeprecatedTrigger(cleaned, rules)
+		} else if !isTextKeyword(firstToken(cleaned)) {
+			matches = findDeprecatedKeywords(cleaned, rules)
+		}
+		for _, m := range matches {
+			findings = append(findings, migrationFinding{file: rel, line: lineNum, match: m})
+		}
+	}
+	return findings
+}
+
+func printMigrationReport(findings []migrationFinding, rules map[string]deprecation, from, to string) {
+	fmt.Printf("=== SPHERE SCP MIGRATION %s -> %s ===\n", formatVersion(from), formatVersion(to))
+	if note := migrationGap(rules, from, to); note != "" {
+		fmt.Println(note)
+		return
+	}
+	if len(findings) == 0 {
+		fmt.Println("No version-specific changes found.")
+		return
+	}
+
+	groups := make(map[string][]migrationFinding)
+	for _, f := range findings {
+		key := deprecationKey(f.match.rule.name, f.match.rule.trigger)
+		groups[key] = append(groups[key], f)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		group := groups[key]
+		rule := group[0].match.rule
+		if rule.changed {
+			fmt.Printf("\n%s changed in %s: %s (%d occurrence(s))\n", key, formatVersion(rule.removed), rule.note, len(group))
+		} else {
+			fmt.Printf("\n%s removed in %s, use %s (%d occurrence(s))\n", key, formatVersion(rule.removed), rule.replacement, len(group))
+		}
+		for _, f := range group {
+			fmt.Printf("  %s:%d: %s\n", f.file, f.line, f.match.label)
+		}
+	}
+}
+
+// migrationGap explains an empty rule window: the version table only holds
+// changes confirmed by server changelogs and does not cover every release
+// yet, so finding nothing there says nothing about the scripts.
+func migrationGap(rules map[string]deprecation, from, to string) string {
+	if len(rules) > 0 {
+		return ""
+	}
+	return fmt.Sprintf("No migration rules are known for %s -> %s yet; the rule table is still incomplete, so review the server changelog by hand.", formatVersion(from), formatVersion(to))
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestMigrationRules(t *testing.T) {
+	rules := migrationRules("55i", "56b")
+
+	if _, ok := rules["@USERDCLICK"]; !ok {
+		t.Fatalf("expected @UserDClick (removed in 56a) in the 55i -> 56b window")
+	}
+	if _, ok := rules["NEWDUPE"]; ok {
+		t.Fatalf("did not expect NEWDUPE (removed in 56c) in the 55i -> 56b window")
+	}
+	if _, ok := migrationRules("56c", "x")["TIMERD"]; !ok {
+		t.Fatalf("expected the TIMERD behaviour change in the 56c -> x window")
+	}
+	if _, ok := migrationRules("55i", "x")["@ITEMSTEP"]; ok {
+		t.Fatalf("did not expect @ItemStep, which is still a current trigger")
+	}
+}
+
+func TestScanMigrationFile(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	path := writeTempFile(t, dir, "migrate.scp", joinLines(
+		"[COMMENT notes]",
+		"ON=@UserDClick is documented here",
+		"[DEFNAME timers]",
+		"timerd 10",
+		"[ITEMDEF i_trap]",
+		"ON=@UserDClick",
+		"TIMERD=10",
+		"SRC.SYSMESSAGE TIMERD",
+		"LOCAL.TIMERD=5",
+		"ON=@ItemStep",
+		"[EOF]",
+	))
+
+	findings := scanMigrationFile(path, migrationRules("55i", "x"))
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].line != 6 || findings[0].match.label != "@UserDClick" {
+		t.Fatalf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].line != 7 || findings[1].match.label != "TIMERD" {
+		t.Fatalf("unexpected second finding: %+v", findings[1])
+	}
+}
+
+func TestMigrationGap(t *testing.T) {
+	if note := migrationGap(migrationRules("56c", "56d"), "56c", "56d"); note != "No migration rules are known for 0.56c -> 0.56d yet; the rule table is still incomplete, so review the server changelog by hand." {
+		t.Fatalf("expected the 56c -> 56d window to be reported as uncovered, got %q", note)
+	}
+	if note := migrationGap(migrationRules("55i", "56d"), "55i", "56d"); note != "" {
+		t.Fatalf("expected no note for a window with rules, got %q", note)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkDefnamePrefix reports a definition name that does not start with the
+// prefix configured for its section type in defnamePrefixes (SPEECH uses
+// speechPrefix). Numeric IDs and types without a prefix are skipped.
+func checkDefnamePrefix(defType, name, file string, lineNum int) []lintIssue {
+	prefix := config.DefnamePrefixes[defType]
+	if defType == "SPEECH" {
+		prefix = config.SpeechPrefix
+	}
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" || name == "" || !isIdentifier(name) || hasPrefixFold(name, prefix) {
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "NAMING", fmt.Sprintf("NAMING: %s '%s' does not use the %s prefix", defType, name, prefix))
+}
+
+// checkBuiltinName reports a definition or DEFNAME named after a built-in
+// command, property, object or <> function, which the built-in shadows or
+// is shadowed by depending on where the name is read.
+func checkBuiltinName(defType, name, file string, lineNum int) []lintIssue {
+	if !isIdentifier(name) {
+		return nil
+	}
+	upper := strings.ToUpper(name)
+	builtin := ""
+	switch {
+	case intrinsicFunctions[upper]:
+		builtin = "<> function"
+	case scriptObjects[upper] || variableNamespaces[upper] || intrinsicNamespaces[upper]:
+		builtin = "object"
+	case scriptCommands[upper]:
+		builtin = "command or property"
+	default:
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "NAMING", fmt.Sprintf("NAMING: %s '%s' has the name of the built-in %s %s", defType, name, builtin, upper))
+}
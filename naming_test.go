@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestLintDefnamePrefixes(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF 0eed]",
+		"DEFNAME=gold_coins",
+		"[ITEMDEF i_gold]",
+		"[CHARDEF ogre_lord]",
+		"[FUNCTION helper]",
+		"[SPEECH talk_guard]",
+		"[AREADEF a_britain]",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "naming.scp", content), "prefixes while the rule is off")
+	})
+
+	t.Run("DefaultPrefixes", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"defname-prefix"} })
+
+		errs := lintFromContent(t, "naming.scp", content)
+		assertHasMessage(t, errs, "NAMING: ITEMDEF 'gold_coins' does not use the i_ prefix")
+		assertHasMessage(t, errs, "NAMING: CHARDEF 'ogre_lord' does not use the c_ prefix")
+		assertHasMessage(t, errs, "NAMING: FUNCTION 'helper' does not use the f_ prefix")
+		assertHasMessage(t, errs, "NAMING: SPEECH 'talk_guard' does not use the spk_ prefix")
+		if len(errs) != 4 {
+			t.Fatalf("expected 4 naming warnings, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredPrefixes", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"defname-prefix"}
+			cfg.SpeechPrefix = "talk_"
+			cfg.DefnamePrefixes["AREADEF"] = "r_"
+			cfg.DefnamePrefixes["FUNCTION"] = ""
+		})
+
+		errs := lintFromContent(t, "naming.scp", content)
+		assertHasMessage(t, errs, "NAMING: AREADEF 'a_britain' does not use the r_ prefix")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 naming warnings, got %+v", errs)
+		}
+	})
+}
+
+func TestLintBuiltinNames(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION dialog]",
+		"SRC.SYSMESSAGE hi",
+		"[FUNCTION f_name]",
+		"SRC.SYSMESSAGE <SRC.NAME>",
+		"[FUNCTION rand]",
+		"RETURN 1",
+		"[ITEMDEF 0eed]",
+		"DEFNAME=amount",
+		"[DEFNAME misc]",
+		"src 1",
+		"gold_amount 100",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "builtin_names.scp", content)
+	assertHasMessage(t, errs, "NAMING: FUNCTION 'dialog' has the name of the built-in command or property DIALOG")
+	assertHasMessage(t, errs, "NAMING: FUNCTION 'rand' has the name of the built-in <> function RAND")
+	assertHasMessage(t, errs, "NAMING: DEFNAME 'amount' has the name of the built-in command or property AMOUNT")
+	assertHasMessage(t, errs, "NAMING: DEFNAME 'src' has the name of the built-in object SRC")
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 naming warnings, got %+v", errs)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// npcBrains are the NPC brain constants the server knows, by name and value.
+var npcBrains = map[string]int{
+	"BRAIN_NONE":           0,
+	"BRAIN_ANIMAL":         1,
+	"BRAIN_HUMAN":          2,
+	"BRAIN_HEALER":         3,
+	"BRAIN_GUARD":          4,
+	"BRAIN_BANKER":         5,
+	"BRAIN_VENDOR":         6,
+	"BRAIN_ANIMAL_TRAINER": 7,
+	"BRAIN_BEGGAR":         8,
+	"BRAIN_STABLE":         9,
+	"BRAIN_MONSTER":        10,
+	"BRAIN_BERSERK":        11,
+	"BRAIN_DRAGON":         12,
+	"BRAIN_VENDOR_OFFDUTY": 13,
+}
+
+// validateCharLine checks property lines of a CHARDEF.
+func validateCharLine(line, file string, lineNum int) []lintIssue {
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" || strings.ContainsAny(value, "<>") {
+		return nil
+	}
+	switch key {
+	case "BRAIN", "NPC":
+		return validateBrain(key, value, file, lineNum)
+	}
+	if r, ok := config.PropertyRanges[key]; ok {
+		return validateRange(key, value, r, file, lineNum)
+	}
+	if limit, ok := config.StatLimits[key]; ok && ruleEnabled("implausible-stats") {
+		return checkStatPlausibility(key, value, limit, file, lineNum)
+	}
+	return nil
+}
+
+// checkStatPlausibility warns about stats of 0 or above limit, which are
+// usually typos such as STR=4000 for STR=400.
+func checkStatPlausibility(key, value string, limit int, file string, lineNum int) []lintIssue {
+	for _, part := range strings.Split(value, ",") {
+		n, ok := parseSphereInt(strings.TrimSpace(part))
+		if !ok {
+			continue
+		}
+		if n == 0 {
+			return appendWarning(nil, file, lineNum, "NPC", fmt.Sprintf("NPC: %s=%s gives the creature no %s", key, value, key))
+		}
+		if n > limit {
+			return appendWarning(nil, file, lineNum, "NPC", fmt.Sprintf("NPC: %s=%s is implausibly high (limit %d)", key, value, limit))
+		}
+	}
+	return nil
+}
+
+// validateRange checks a number or min,max random range against the
+// configured propertyRanges entry for key.
+func validateRange(key, value string, r valueRange, file string, lineNum int) []lintIssue {
+	for _, part := range strings.Split(value, ",") {
+		n, ok := parseSphereInt(strings.TrimSpace(part))
+		if ok && (n < r.Min || n > r.Max) {
+			return appendError(nil, file, lineNum, "NPC", fmt.Sprintf("NPC: %s=%s is outside the allowed range %d..%d", key, value, r.Min, r.Max))
+		}
+	}
+	return nil
+}
+
+func validateBrain(key, value, file string, lineNum int) []lintIssue {
+	if n, ok := parseSphereInt(value); ok {
+		if n < 0 || n > len(npcBrains)-1 {
+			return appendError(nil, file, lineNum, "NPC", fmt.Sprintf("NPC: %s=%s is not a known brain (0-%d)", key, value, len(npcBrains)-1))
+		}
+		return nil
+	}
+	if _, ok := npcBrains[strings.ToUpper(value)]; !ok {
+		return appendError(nil, file, lineNum, "NPC", fmt.Sprintf("NPC: unknown brain '%s' in %s=; the NPC will stand inert", value, key))
+	}
+	return nil
+}
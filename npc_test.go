@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+func TestLintNPCBrains(t *testing.T) {
+	t.Run("KnownBrains", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_ogre]",
+			"NPC=brain_monster",
+			"[CHARDEF c_guard]",
+			"BRAIN=4",
+			"ON=@Create",
+			"NPC=<TAG.BRAIN>",
+			"[ITEMDEF i_test]",
+			"NPC=whatever",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "brains_ok.scp", content), "known brains")
+	})
+
+	t.Run("UnknownBrains", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_ogre]",
+			"NPC=brain_monstr",
+			"[CHARDEF c_guard]",
+			"BRAIN=020",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "brains_bad.scp", content)
+		assertHasMessage(t, errs, "NPC: unknown brain 'brain_monstr' in NPC=; the NPC will stand inert")
+		assertHasMessage(t, errs, "NPC: BRAIN=020 is not a known brain (0-13)")
+	})
+}
+
+func TestLintFameKarmaRanges(t *testing.T) {
+	content := joinLines(
+		"[CHARDEF c_ogre]",
+		"FAME=12000",
+		"KARMA=-10000,-12000",
+		"[CHARDEF c_guard]",
+		"FAME=0,10000",
+		"KARMA=5000",
+		"[EOF]",
+	)
+
+	t.Run("DefaultRanges", func(t *testing.T) {
+		errs := lintFromContent(t, "fame.scp", content)
+		assertHasMessage(t, errs, "NPC: FAME=12000 is outside the allowed range 0..10000")
+		assertHasMessage(t, errs, "NPC: KARMA=-10000,-12000 is outside the allowed range -10000..10000")
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 range errors, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredRanges", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.PropertyRanges["FAME"] = valueRange{Min: 0, Max: 20000}
+			cfg.PropertyRanges["KARMA"] = valueRange{Min: -20000, Max: 4000}
+		})
+
+		errs := lintFromContent(t, "fame.scp", content)
+		assertHasMessage(t, errs, "NPC: KARMA=5000 is outside the allowed range -20000..4000")
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 range error, got %+v", errs)
+		}
+	})
+}
+
+func TestLintImplausibleStats(t *testing.T) {
+	content := joinLines(
+		"[CHARDEF c_ogre]",
+		"STR=4000",
+		"DEX=0",
+		"INT=100,200",
+		"HITS=<EVAL <STR>*2>",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "stats.scp", content), "stats while the rule is off")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"implausible-stats"} })
+
+		errs := lintFromContent(t, "stats.scp", content)
+		assertHasMessage(t, errs, "NPC: STR=4000 is implausibly high (limit 1000)")
+		assertHasMessage(t, errs, "NPC: DEX=0 gives the creature no DEX")
+		if len(errs) != 2 || errs[0].severity != severityWarning {
+			t.Fatalf("expected 2 stat warnings, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredLimits", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"implausible-stats"}
+			cfg.StatLimits["STR"] = 5000
+			cfg.StatLimits["INT"] = 150
+		})
+
+		errs := lintFromContent(t, "stats.scp", content)
+		assertHasMessage(t, errs, "NPC: INT=100,200 is implausibly high (limit 150)")
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 stat warnings, got %+v", errs)
+		}
+	})
+}
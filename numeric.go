@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// numericReference is a bare item or character ID such as ITEM=0eed.
+type numericReference struct {
+	file    string
+	line    int
+	defType string
+	text    string
+	id      int
+}
+
+// collectNumericReferences records the numeric IDs of ITEM=/CONTAINER=
+// lines, ITEMDEF/CHARDEF ID= lines, SPAWN members and dialog tilepic art,
+// outside triggers. Values are read the way the server reads them: a
+// leading 0 means hex.
+func collectNumericReferences(defType string, dialog bool, line, file string, lineNum int, references *[]numericReference) {
+	add := func(refType, value string) {
+		value = strings.TrimSpace(value)
+		if value == "" || !isDigit(value[0]) {
+			return
+		}
+		if n, ok := parseSphereInt(value); ok && n > 0 {
+			*references = append(*references, numericReference{file: file, line: lineNum, defType: refType, text: value, id: n})
+		}
+	}
+	if dialog {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && (strings.EqualFold(fields[0], "tilepic") || strings.EqualFold(fields[0], "tilepichue")) {
+			add("ITEMDEF", fields[3])
+		}
+		return
+	}
+	if match := itemAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+		add("ITEMDEF", firstField(strings.Split(match[1], ",")[0]))
+		return
+	}
+	if match := containerAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+		add("ITEMDEF", firstField(strings.Split(match[1], ",")[0]))
+		return
+	}
+	key, value, ok := splitAssignment(line)
+	if !ok || key != "ID" {
+		return
+	}
+	switch defType {
+	case "ITEMDEF":
+		add("ITEMDEF", value)
+	case "CHARDEF", "SPAWN":
+		add("CHARDEF", value)
+	}
+}
+
+// findUndefinedNumericIDs reports numeric references with no ITEMDEF or
+// CHARDEF section of that ID. Section header IDs are always hex.
+func findUndefinedNumericIDs(index *lintIndex) []lintIssue {
+	defined := make(map[string]bool)
+	for key := range index.defs {
+		defType, id, _ := strings.Cut(key, " ")
+		if defType != "ITEMDEF" && defType != "CHARDEF" {
+			continue
+		}
+		if n, err := strconv.ParseInt(id, 16, 64); err == nil {
+			defined[fmt.Sprintf("%s %x", defType, n)] = true
+		}
+	}
+	var issues []lintIssue
+	for _, ref := range index.numericRefs {
+		if defined[fmt.Sprintf("%s %x", ref.defType, ref.id)] {
+			continue
+		}
+		issues = appendError(issues, ref.file, ref.line, "UNDECLARED", fmt.Sprintf("UNDECLARED: '%s' (0%x) not defined as %s", ref.text, ref.id, ref.defType))
+	}
+	return issues
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestLintNumericReferences(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF 0eed]",
+		"DEFNAME=i_gold",
+		"[ITEMDEF e75]",
+		"DEFNAME=i_backpack",
+		"[CHARDEF 0190]",
+		"DEFNAME=c_man",
+		"ITEM=0eed,50",
+		"ITEM=0f51",
+		"[ITEMDEF i_coin_pile]",
+		"ID=0eed",
+		"[CHARDEF c_guard]",
+		"ID=0191",
+		"CONTAINER=0e75",
+		"[TEMPLATE tm_loot]",
+		"ITEM=3821",
+		"ITEM=i_gold",
+		"[SPAWN spawn_guards]",
+		"ID=0190",
+		"[DIALOG d_coins]",
+		"0, 0",
+		"tilepic 10 10 0eed",
+		"tilepichue 40 10 0eee 021",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "numeric.scp", content), "numeric IDs without the numeric-references rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"numeric-references"}
+		})
+		errs := lintFromContent(t, "numeric.scp", content)
+		assertHasMessage(t, errs, "UNDECLARED: '0f51' (0f51) not defined as ITEMDEF")
+		assertHasMessage(t, errs, "UNDECLARED: '0191' (0191) not defined as CHARDEF")
+		assertHasMessage(t, errs, "UNDECLARED: '0eee' (0eee) not defined as ITEMDEF")
+		if len(errs) != 3 || errs[0].line != 8 || errs[1].line != 12 || errs[2].line != 22 {
+			t.Fatalf("expected errors on lines 8, 12 and 22, got %+v", errs)
+		}
+	})
+}
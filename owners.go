@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// codeOwnersPaths are where GitHub looks for a CODEOWNERS file, relative to
+// the repository root.
+var codeOwnersPaths = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"}
+
+// unownedGroup labels the issues of files no CODEOWNERS rule covers.
+const unownedGroup = "(unowned)"
+
+// ownerRule is one "pattern @owner..." line of a CODEOWNERS file.
+type ownerRule struct {
+	pattern string
+	owners  []string
+}
+
+// codeOwners maps files to their owners. As on GitHub, the last matching
+// rule wins.
+type codeOwners []ownerRule
+
+// loadCodeOwners reads the CODEOWNERS file at path, or the first of
+// codeOwnersPaths under the scripts root when path is empty.
+func loadCodeOwners(path string) (codeOwners, error) {
+	if path == "" {
+		for _, candidate := range codeOwnersPaths {
+			full := filepath.Join(scriptsRoot, filepath.FromSlash(candidate))
+			if _, err := os.Stat(full); err == nil {
+				path = full
+				break
+			}
+		}
+		if path == "" {
+			return nil, errors.New("no CODEOWNERS file found; pass -codeowners")
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseCodeOwners(file)
+}
+
+// parseCodeOwners reads CODEOWNERS rules, skipping comments and blank lines.
+func parseCodeOwners(r io.Reader) (codeOwners, error) {
+	var rules codeOwners
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		rules = append(rules, ownerRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules, scanner.Err()
+}
+
+// matchOwnerPattern reports whether a CODEOWNERS pattern covers file, a
+// slash-separated path relative to the root. Patterns follow the gitignore
+// rules GitHub uses: a leading or inner slash anchors the pattern to the
+// root, a trailing slash only matches directories, and a pattern matching a
+// directory covers everything below it, except for "dir/*".
+func matchOwnerPattern(pattern, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "**/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" || pattern == "*" && !dirOnly {
+		return true
+	}
+
+	parts := strings.Split(file, "/")
+	// "dir/*" only covers the files directly in dir.
+	shallow := strings.HasSuffix(pattern, "/*")
+	covers := func(end int) bool {
+		if shallow {
+			return end == len(parts)
+		}
+		return !dirOnly || end < len(parts)
+	}
+	if anchored {
+		for end := 1; end <= len(parts); end++ {
+			if ok, _ := path.Match(pattern, strings.Join(parts[:end], "/")); ok && covers(end) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, part := range parts {
+		if ok, _ := path.Match(pattern, part); ok && covers(i+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// owner returns the owners of file joined by spaces, or unownedGroup.
+func (c codeOwners) owner(file string) string {
+	for i := len(c) - 1; i >= 0; i-- {
+		if matchOwnerPattern(c[i].pattern, file) {
+			if len(c[i].owners) == 0 {
+				break
+			}
+			return strings.Join(c[i].owners, " ")
+		}
+	}
+	return unownedGroup
+}
+
+// groupIssuesByOwner splits issues by the owners of their file. The groups
+// are sorted by name, with the unowned issues last.
+func groupIssuesByOwner(issues []lintIssue, owners codeOwners) ([]string, map[string][]lintIssue) {
+	groups := make(map[string][]lintIssue)
+	for _, issue := range issues {
+		name := owners.owner(issue.file)
+		groups[name] = append(groups[name], issue)
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == unownedGroup) != (names[j] == unownedGroup) {
+			return names[j] == unownedGroup
+		}
+		return names[i] < names[j]
+	})
+	return names, groups
+}
+
+// printIssuesByOwner prints issues under one heading per owner; on GitHub
+// Actions the headings are collapsible log groups.
+func printIssuesByOwner(issues []lintIssue, owners codeOwners) {
+	names, groups := groupIssuesByOwner(issues, owners)
+	for _, name := range names {
+		heading := fmt.Sprintf("%s (%d issue(s))", name, len(groups[name]))
+		if isGitHubActions() {
+			fmt.Printf("::group::%s\n", heading)
+		} else {
+			fmt.Printf("== %s ==\n", heading)
+		}
+		for _, issue := range groups[name] {
+			printError(issue)
+		}
+		if isGitHubActions() {
+			fmt.Println("::endgroup::")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchOwnerPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"*", "items/swords.scp", true},
+		{"*.scp", "items/swords.scp", true},
+		{"*.ini", "items/swords.scp", false},
+		{"/items/", "items/swords.scp", true},
+		{"/items/", "npcs/items/x.scp", false},
+		{"items/", "npcs/items/x.scp", true},
+		{"items/", "items", false},
+		{"/npcs/*.scp", "npcs/dragon.scp", true},
+		{"/npcs/*.scp", "npcs/dragons/red.scp", false},
+		{"docs/*", "npcs/docs/x.scp", false},
+		{"docs/*", "docs/x.scp", true},
+		{"docs/*", "docs/old/x.scp", false},
+		{"**/dragons", "npcs/dragons/red.scp", true},
+		{"swords.scp", "items/swords.scp", true},
+	}
+	for _, c := range cases {
+		if got := matchOwnerPattern(c.pattern, c.file); got != c.want {
+			t.Errorf("matchOwnerPattern(%q, %q) = %v, want %v", c.pattern, c.file, got, c.want)
+		}
+	}
+}
+
+func TestGroupIssuesByOwner(t *testing.T) {
+	owners, err := parseCodeOwners(strings.NewReader(strings.Join([]string{
+		"# shard staff",
+		"*                 @lead",
+		"/items/           @items-team @smith   # weapons and armor",
+		"/npcs/dragons/",
+		"/npcs/            @spawn-team",
+		"/npcs/dragons/",
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	issues := []lintIssue{
+		{file: "items/swords.scp", line: 3},
+		{file: "npcs/orcs.scp", line: 1},
+		{file: "npcs/dragons/red.scp", line: 2},
+		{file: "sphere_defs.scp", line: 9},
+		{file: "items/armor.scp", line: 4},
+	}
+	names, groups := groupIssuesByOwner(issues, owners)
+	if want := []string{"@items-team @smith", "@lead", "@spawn-team", unownedGroup}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected groups %q, want %q", names, want)
+	}
+	if items := groups["@items-team @smith"]; len(items) != 2 || items[1].file != "items/armor.scp" {
+		t.Fatalf("expected both item files under the items team, got %+v", items)
+	}
+	if unowned := groups[unownedGroup]; len(unowned) != 1 || unowned[0].file != "npcs/dragons/red.scp" {
+		t.Fatalf("expected the dragons to be unowned after the ownerless rule, got %+v", unowned)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// privilegedCommands are the verbs and properties that should only run
+	// for staff, with the PLEVEL the server requires when a client types them
+	// (4 GM, 6 admin, 7 owner). Scripts run them with the script's own rights,
+	// so a player who reaches them unchecked gets the staff power. Commands on
+	// SRC may be written with or without the SRC. prefix.
+	privilegedCommands = map[string]int{
+		"ACCOUNT.PLEVEL":   7,
+		"NUKE":             4,
+		"NUKECHAR":         4,
+		"PLEVEL":           7,
+		"PRIVSET":          7,
+		"SERV.ACCOUNT":     7,
+		"SERV.BLOCKIP":     6,
+		"SERV.EXPORT":      6,
+		"SERV.IMPORT":      6,
+		"SERV.RESTORE":     7,
+		"SERV.RESYNC":      6,
+		"SERV.SAVE":        6,
+		"SERV.SAVESTATICS": 6,
+		"SERV.SHUTDOWN":    7,
+		"SERV.UNBLOCKIP":   6,
+		"SRC.REMOVE":       4,
+	}
+
+	// playerTriggers are the @triggers a player fires by acting on an object.
+	playerTriggers = map[string]bool{
+		"CLICK": true, "CONTEXTMENUREQUEST": true, "CONTEXTMENUSELECT": true,
+		"DCLICK": true, "DROPON_CHAR": true, "DROPON_GROUND": true,
+		"DROPON_ITEM": true, "DROPON_SELF": true, "EQUIP": true, "HEAR": true,
+		"ITEMCLICK": true, "ITEMDCLICK": true, "ITEMSTEP": true, "LOGIN": true,
+		"PICKUP_GROUND": true, "PICKUP_PACK": true, "PICKUP_SELF": true,
+		"SEEHEAR": true, "STEP": true, "TARGON_CHAR": true, "TARGON_GROUND": true,
+		"TARGON_ITEM": true, "UNEQUIP": true,
+	}
+
+	privilegeCommandPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*`)
+	plevelGuardPattern      = regexp.MustCompile(`(?i)\b(?:PLEVEL|ISGM)\b`)
+)
+
+// privilegeScope watches one FUNCTION or player-reachable trigger body for
+// privileged commands used before any PLEVEL check.
+type privilegeScope struct {
+	where   string
+	guarded bool
+}
+
+// newFunctionPrivilege watches a FUNCTION, which any script or a typed
+// command may call.
+func newFunctionPrivilege(name string) *privilegeScope {
+	return &privilegeScope{where: "FUNCTION " + name}
+}
+
+// newTriggerPrivilege watches the body of an ON= line when a player can
+// reach it: player @triggers, dialog buttons, menu options and speech
+// keywords. It returns nil for other bodies.
+func newTriggerPrivilege(section sectionState, line string) *privilegeScope {
+	if match := triggerNamePattern.FindStringSubmatch(line); len(match) == 2 {
+		if !playerTriggers[strings.ToUpper(match[1])] {
+			return nil
+		}
+		return &privilegeScope{where: "player trigger @" + match[1]}
+	}
+	switch {
+	case section.dialogButtons != nil:
+		return &privilegeScope{where: "dialog button " + strings.TrimSpace(line)}
+	case section.menu != nil:
+		return &privilegeScope{where: "menu option " + strings.TrimSpace(line)}
+	case section.defType == "SPEECH":
+		return &privilegeScope{where: "speech handler " + strings.TrimSpace(line)}
+	}
+	return nil
+}
+
+// check notes PLEVEL conditions and warns about privileged commands the body
+// runs before any of them.
+func (p *privilegeScope) check(token, line, file string, lineNum int) []lintIssue {
+	if p == nil || p.guarded {
+		return nil
+	}
+	if token == "IF" || token == "ELIF" || token == "ELSEIF" || token == "WHILE" {
+		if plevelGuardPattern.MatchString(line) {
+			p.guarded = true
+		}
+		return nil
+	}
+	command, level, ok := privilegedCommand(line)
+	if !ok {
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "SECURITY", fmt.Sprintf("SECURITY: %s needs PLEVEL %d but %s runs it without checking PLEVEL first.", command, level, p.where))
+}
+
+// privilegedCommand returns the privileged command a statement starts with
+// and the PLEVEL it needs.
+func privilegedCommand(line string) (string, int, bool) {
+	command := strings.ToUpper(privilegeCommandPattern.FindString(line))
+	level, ok := privilegedCommands[command]
+	if !ok {
+		level, ok = privilegedCommands[strings.TrimPrefix(command, "SRC.")]
+	}
+	return command, level, ok
+}
+
+// inGMScriptDir reports whether a script path lies in one of the configured
+// gmScriptDirs.
+func inGMScriptDir(file string) bool {
+	for _, dir := range config.GMScriptDirs {
+		if file == dir || strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDangerousCommand lists, for the dangerous-commands audit, every
+// privileged command a script body runs outside the gmScriptDirs, whether or
+// not it checks PLEVEL first.
+func checkDangerousCommand(line, file string, lineNum int) []lintIssue {
+	if !ruleEnabled("dangerous-commands") || inGMScriptDir(file) {
+		return nil
+	}
+	command, level, ok := privilegedCommand(line)
+	if !ok {
+		return nil
+	}
+	return appendWarning(nil, file, lineNum, "SECURITY", fmt.Sprintf("SECURITY: %s (PLEVEL %d) is used outside the GM script directories; move it there or review who can reach it.", command, level))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLintPrivilegedCommands(t *testing.T) {
+	t.Run("Reported", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_wipe]",
+			"SERV.ACCOUNT <ARGS> DELETE",
+			"[ITEMDEF i_gm_stone]",
+			"ID=0ed4",
+			"ON=@DClick",
+			"SRC.PLEVEL=7",
+			"ON=@Timer",
+			"SERV.SAVE",
+			"[DIALOG d_admin BUTTON]",
+			"ON=1",
+			"SRC.NUKE",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "privilege.scp", content)
+		assertHasMessage(t, errs, "SECURITY: SERV.ACCOUNT needs PLEVEL 7 but FUNCTION F_WIPE runs it without checking PLEVEL first.")
+		assertHasMessage(t, errs, "SECURITY: SRC.PLEVEL needs PLEVEL 7 but player trigger @DClick runs it without checking PLEVEL first.")
+		assertHasMessage(t, errs, "SECURITY: SRC.NUKE needs PLEVEL 4 but dialog button ON=1 runs it without checking PLEVEL first.")
+		var lines []int
+		for _, e := range errs {
+			if e.kind == "SECURITY" {
+				lines = append(lines, e.line)
+			}
+		}
+		if len(lines) != 3 || lines[0] != 2 || lines[1] != 6 || lines[2] != 11 {
+			t.Fatalf("expected SECURITY warnings at lines [2 6 11], got %+v", errs)
+		}
+	})
+
+	t.Run("Guarded", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_save]",
+			"IF (<SRC.PLEVEL> < 6)",
+			"  RETURN 1",
+			"ENDIF",
+			"SERV.SAVE",
+			"[ITEMDEF i_gm_stone]",
+			"ID=0ed4",
+			"ON=@DClick",
+			"IF <SRC.ISGM>",
+			"  SERV.SHUTDOWN",
+			"ENDIF",
+			"REMOVE",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "privilege_guarded.scp", content), "privileged commands behind a PLEVEL check")
+	})
+}
+
+func TestLintDangerousCommands(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_wipe_account]",
+		"IF <SRC.PLEVEL> < 7",
+		"  RETURN 1",
+		"ENDIF",
+		"SERV.ACCOUNT <ARGS> DELETE",
+		"SERV.SAVE",
+		"[EOF]",
+	)
+
+	lintTree := func(t *testing.T) []lintIssue {
+		dir := withTempScriptsDir(t)
+		if err := os.MkdirAll(filepath.Join(dir, "gm", "tools"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		errs := lintScriptFile(writeTempFile(t, dir, "custom.scp", content), newLintIndex())
+		return append(errs, lintScriptFile(writeTempFile(t, dir, filepath.Join("gm", "tools", "admin.scp"), content), newLintIndex())...)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintTree(t), "guarded commands without the dangerous-commands rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"dangerous-commands"}
+			cfg.GMScriptDirs = []string{"gm"}
+		})
+		errs := lintTree(t)
+		assertHasMessage(t, errs, "SECURITY: SERV.ACCOUNT (PLEVEL 7) is used outside the GM script directories; move it there or review who can reach it.")
+		assertHasMessage(t, errs, "SECURITY: SERV.SAVE (PLEVEL 6) is used outside the GM script directories")
+		if len(errs) != 2 || errs[0].file != "custom.scp" || errs[1].file != "custom.scp" {
+			t.Fatalf("expected 2 warnings in custom.scp only, got %+v", errs)
+		}
+	})
+}
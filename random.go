@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// randomExpressionPattern matches <R>, <Rmax> and <Rmin,max>. Arguments
+// holding nested <...> expressions are left to the server.
+var randomExpressionPattern = regexp.MustCompile(`(?i)<R([-+0-9,\s][^<>]*)?>`)
+
+// checkRandomExpressions validates the ranges of <R...> random expressions:
+// one or two numbers, the first no larger than the second. A bare <R> has no
+// range at all and always yields 0.
+func checkRandomExpressions(line, file string, lineNum int) []lintIssue {
+	var issues []lintIssue
+	for _, match := range randomExpressionPattern.FindAllStringSubmatch(line, -1) {
+		expr, args := match[0], strings.TrimSpace(match[1])
+		if args == "" {
+			issues = appendError(issues, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s has no range; use <Rmax> or <Rmin,max>", expr))
+			continue
+		}
+		parts := strings.Split(args, ",")
+		if len(parts) > 2 {
+			issues = appendError(issues, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s takes at most 2 arguments, got %d", expr, len(parts)))
+			continue
+		}
+		var bounds []int
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			n, ok := parseSphereInt(part)
+			if !ok {
+				issues = appendError(issues, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s argument '%s' is not a number", expr, part))
+				break
+			}
+			bounds = append(bounds, n)
+		}
+		if len(bounds) == 2 && bounds[0] > bounds[1] {
+			issues = appendError(issues, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: %s has its minimum %d above its maximum %d", expr, bounds[0], bounds[1]))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestLintRandomExpressions(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_roll]",
+		"LOCAL.A=<R5>",
+		"LOCAL.B=<R1,10> + <R 2, 4>",
+		"LOCAL.C=<R-5,5>",
+		"LOCAL.D=<R<LOCAL.A>>",
+		"SRC.SYSMESSAGE You rolled <R>.",
+		"LOCAL.E=<R10,1>",
+		"LOCAL.F=<R1,x5>",
+		"LOCAL.G=<R1,2,3>",
+		"LOCAL.H=<REGION.NAME>",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "random.scp", content)
+	assertHasMessage(t, errs, "SYNTAX: <R> has no range; use <Rmax> or <Rmin,max>")
+	assertHasMessage(t, errs, "LOGIC: <R10,1> has its minimum 10 above its maximum 1")
+	assertHasMessage(t, errs, "SYNTAX: <R1,x5> argument 'x5' is not a number")
+	assertHasMessage(t, errs, "SYNTAX: <R1,2,3> takes at most 2 arguments, got 3")
+	if len(errs) != 4 || errs[0].line != 6 || errs[1].line != 7 || errs[2].line != 8 || errs[3].line != 9 {
+		t.Fatalf("expected errors on lines 6-9, got %+v", errs)
+	}
+}
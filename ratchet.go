@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ruleCounts counts issues per rule (issue kind).
+func ruleCounts(issues []lintIssue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.kind]++
+	}
+	return counts
+}
+
+// readRatchet loads the allowed count of every rule. A missing file allows
+// nothing, so the first -ratchet-update run records the current counts.
+func readRatchet(path string) (map[string]int, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]int{}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	allowed := make(map[string]int)
+	if err := json.Unmarshal(data, &allowed); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return allowed, true, nil
+}
+
+// checkRatchet prints the rules whose count went above or below the allowed
+// one and reports whether any went above.
+func checkRatchet(w io.Writer, allowed, counts map[string]int, path string) bool {
+	rules := make(map[string]bool)
+	for rule := range allowed {
+		rules[rule] = true
+	}
+	for rule := range counts {
+		rules[rule] = true
+	}
+	names := make([]string, 0, len(rules))
+	for rule := range rules {
+		names = append(names, rule)
+	}
+	sort.Strings(names)
+
+	regressed := false
+	for _, rule := range names {
+		switch {
+		case counts[rule] > allowed[rule]:
+			regressed = true
+			fmt.Fprintf(w, "RATCHET: %s has %d issue(s), more than the %d allowed by %s.\n", rule, counts[rule], allowed[rule], path)
+		case counts[rule] < allowed[rule]:
+			fmt.Fprintf(w, "RATCHET: %s went down from %d to %d issue(s); run with -ratchet-update to lock it in.\n", rule, allowed[rule], counts[rule])
+		}
+	}
+	return regressed
+}
+
+// updateRatchet lowers the allowed counts to the current ones and never
+// raises them, except when the file does not exist yet and records the
+// starting point. Rules down to zero are dropped.
+func updateRatchet(path string, allowed, counts map[string]int, exists bool) error {
+	updated := make(map[string]int)
+	for rule, count := range counts {
+		if exists && count > allowed[rule] {
+			count = allowed[rule]
+		}
+		if count > 0 {
+			updated[rule] = count
+		}
+	}
+	data, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRatchet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counts.json")
+	issues := []lintIssue{
+		{file: "a.scp", kind: "UNDECLARED"},
+		{file: "a.scp", kind: "UNDECLARED"},
+		{file: "b.scp", kind: "LOGIC", severity: severityWarning},
+	}
+
+	allowed, exists, err := readRatchet(path)
+	if err != nil || exists || len(allowed) != 0 {
+		t.Fatalf("expected a missing file to allow nothing, got %v %v %v", allowed, exists, err)
+	}
+	if err := updateRatchet(path, allowed, ruleCounts(issues), exists); err != nil {
+		t.Fatalf("record ratchet: %v", err)
+	}
+	allowed, exists, err = readRatchet(path)
+	if err != nil || !exists || allowed["UNDECLARED"] != 2 || allowed["LOGIC"] != 1 {
+		t.Fatalf("expected the first update to record the current counts, got %v %v %v", allowed, exists, err)
+	}
+
+	// One UNDECLARED fixed, two new STYLE issues.
+	current := ruleCounts([]lintIssue{
+		{file: "a.scp", kind: "UNDECLARED"},
+		{file: "b.scp", kind: "LOGIC"},
+		{file: "c.scp", kind: "STYLE"},
+		{file: "c.scp", kind: "STYLE"},
+	})
+	var out bytes.Buffer
+	if !checkRatchet(&out, allowed, current, "counts.json") {
+		t.Fatal("expected the new STYLE issues to fail the ratchet")
+	}
+	want := joinLines(
+		"RATCHET: STYLE has 2 issue(s), more than the 0 allowed by counts.json.",
+		"RATCHET: UNDECLARED went down from 2 to 1 issue(s); run with -ratchet-update to lock it in.",
+	)
+	if out.String() != want {
+		t.Fatalf("unexpected ratchet report:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	if err := updateRatchet(path, allowed, current, exists); err != nil {
+		t.Fatalf("update ratchet: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var stored map[string]int
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(stored) != 2 || stored["UNDECLARED"] != 1 || stored["LOGIC"] != 1 {
+		t.Fatalf("expected the update to lower UNDECLARED and not admit STYLE, got %v", stored)
+	}
+}
+
+func TestRunLintRatchet(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "a.scp", joinLines("[FUNCTION f_test]", "SRC.NEWITEM i_missing", "[EOF]"))
+	path := filepath.Join(t.TempDir(), "counts.json")
+
+	if code := runLint([]string{"-ratchet", path}); code != 1 {
+		t.Fatalf("expected a missing ratchet file to allow nothing, got %d", code)
+	}
+	if code := runLint([]string{"-ratchet", path, "-ratchet-update"}); code != 0 {
+		t.Fatalf("expected recording the counts to pass, got %d", code)
+	}
+	if code := runLint([]string{"-ratchet", path}); code != 0 {
+		t.Fatalf("expected the existing error to be tolerated, got %d", code)
+	}
+	writeTempFile(t, dir, "b.scp", joinLines("[FUNCTION f_other]", "SRC.NEWITEM i_gone", "[EOF]"))
+	if code := runLint([]string{"-ratchet", path}); code != 1 {
+		t.Fatalf("expected a new error to fail, got %d", code)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// referencesTo returns the references to id, one per file:line, sorted by
+// file and line.
+func referencesTo(index *lintIndex, id string) []referenceUse {
+	id = strings.ToUpper(id)
+	var uses []referenceUse
+	seen := make(map[definitionLocation]bool)
+	for _, ref := range index.references {
+		loc := definitionLocation{file: ref.file, line: ref.line}
+		if ref.id != id || seen[loc] {
+			continue
+		}
+		seen[loc] = true
+		uses = append(uses, ref)
+	}
+	sort.SliceStable(uses, func(i, j int) bool {
+		if uses[i].file != uses[j].file {
+			return uses[i].file < uses[j].file
+		}
+		return uses[i].line < uses[j].line
+	})
+	return uses
+}
+
+// writeReferences prints one file:line per use, with the section that makes
+// it when known.
+func writeReferences(w io.Writer, uses []referenceUse) {
+	for _, use := range uses {
+		if use.from == "" {
+			fmt.Fprintf(w, "%s:%d\n", use.file, use.line)
+			continue
+		}
+		fmt.Fprintf(w, "%s:%d: %s\n", use.file, use.line, use.from)
+	}
+}
+
+// runRefs implements "sphere-lint refs ID": it prints every line that
+// references ID, to judge the impact of renaming or removing a definition.
+// Like grep, it exits 1 when nothing references ID.
+func runRefs(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint refs", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: sphere-lint refs [-config file] ID")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	uses := referencesTo(index, flags.Arg(0))
+	writeReferences(os.Stdout, uses)
+	if len(walkIssues) > 0 {
+		return 2
+	}
+	if len(uses) == 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReferencesTo(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	index := newLintIndex()
+	lintScriptFile(writeTempFile(t, dir, "b.scp", joinLines(
+		"[FUNCTION f_arm]",
+		"SRC.NEWITEM i_sword_long",
+		"SRC.NEWITEM i_sword_short",
+		"[EOF]",
+	)), index)
+	lintScriptFile(writeTempFile(t, dir, "a.scp", joinLines(
+		"[ITEMDEF i_sword_long]",
+		"ID=0f61",
+		"[CHARDEF c_guard]",
+		"ID=c_man",
+		"ITEMNEWBIE=I_Sword_Long",
+		"[EOF]",
+	)), index)
+
+	var out bytes.Buffer
+	writeReferences(&out, referencesTo(index, "i_sword_long"))
+	if want := "a.scp:5: CHARDEF C_GUARD\nb.scp:2: FUNCTION F_ARM\n"; out.String() != want {
+		t.Fatalf("unexpected references:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if uses := referencesTo(index, "i_sword_broad"); len(uses) != 0 {
+		t.Fatalf("expected no references, got %+v", uses)
+	}
+}
+
+func TestRunRefs(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "a.scp", joinLines("[FUNCTION f_test]", "SRC.NEWITEM i_gold", "[EOF]"))
+	if code := runRefs([]string{"i_gold"}); code != 0 {
+		t.Fatalf("expected a match to exit 0, got %d", code)
+	}
+	if code := runRefs([]string{"i_silver"}); code != 1 {
+		t.Fatalf("expected no match to exit 1, got %d", code)
+	}
+	if code := runRefs(nil); code != 2 {
+		t.Fatalf("expected a missing ID to exit 2, got %d", code)
+	}
+}
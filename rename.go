@@ -0,0 +1,252 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// renamePattern matches oldName as a whole word, in any letter case.
+func renamePattern(oldName string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(oldName) + `\b`)
+}
+
+// renameLines returns, per file, the lines that define or use name
+// according to the index: its header or DEFNAME line, every recorded
+// reference and every call, including <name> reads in IF and WHILE
+// conditions. Only those lines are rewritten by a rename.
+func renameLines(index *lintIndex, name string) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	add := func(file string, line int) {
+		if lines[file] == nil {
+			lines[file] = make(map[int]bool)
+		}
+		lines[file][line] = true
+	}
+	upper := strings.ToUpper(name)
+	if loc, ok := index.ids[upper]; ok {
+		add(loc.file, loc.line)
+	}
+	if loc, ok := index.defnames[upper]; ok {
+		add(loc.file, loc.line)
+	}
+	for _, ref := range index.references {
+		if ref.id == upper {
+			add(ref.file, ref.line)
+		}
+	}
+	for _, edges := range index.calls.edges {
+		for _, edge := range edges {
+			if edge.target == upper {
+				add(edge.file, edge.line)
+			}
+		}
+	}
+	return lines
+}
+
+// leftoverMatches returns the lines of content that still mention re in
+// code after a rename, outside comments and variable names: quoted or
+// message text the rename skipped, or a use the index did not record.
+func leftoverMatches(content string, re *regexp.Regexp) []int {
+	var leftover []int
+	for i, line := range strings.Split(content, "\n") {
+		if idx := commentIndex(line); idx >= 0 {
+			line = line[:idx]
+		}
+		for _, m := range re.FindAllStringIndex(line, -1) {
+			if !isVariableName(line[:m[0]]) {
+				leftover = append(leftover, i+1)
+				break
+			}
+		}
+	}
+	return leftover
+}
+
+// renameIdentifier replaces the matches of re with newName on the given
+// lines of content. Comments, quoted strings, the text of SAY-like verbs
+// and TAG/VAR/LOCAL variable names are left as they are: a renamed TAG
+// would orphan the values already saved on world objects.
+func renameIdentifier(content string, re *regexp.Regexp, newName string, lines map[int]bool) string {
+	split := strings.Split(content, "\n")
+	for i, line := range split {
+		if !lines[i+1] {
+			continue
+		}
+		code, comment := line, ""
+		if idx := commentIndex(line); idx >= 0 {
+			code, comment = line[:idx], line[idx:]
+		}
+		literal := literalText(code)
+		var b strings.Builder
+		last := 0
+		for _, m := range re.FindAllStringIndex(code, -1) {
+			if literal[m[0]] || isVariableName(code[:m[0]]) {
+				continue
+			}
+			b.WriteString(code[last:m[0]])
+			b.WriteString(newName)
+			last = m[1]
+		}
+		b.WriteString(code[last:])
+		split[i] = b.String() + comment
+	}
+	return strings.Join(split, "\n")
+}
+
+// literalText marks the bytes of line that are plain text rather than code:
+// quoted strings and, for SAY/SYSMESSAGE-like verbs, their message outside
+// <...> expressions.
+func literalText(line string) []bool {
+	literal := quotedText(line)
+	token := firstToken(line)
+	if !isTextKeyword(token) {
+		return literal
+	}
+	for i := strings.Index(line, token) + len(token); i < len(line); i++ {
+		if line[i] == '<' && i+1 < len(line) && isAngleTokenStart(line[i+1]) {
+			if end, ok := scanAngleExpression(line, i+1); ok {
+				i = end
+				continue
+			}
+		}
+		literal[i] = true
+	}
+	return literal
+}
+
+// isVariableName reports whether a name following prefix is the key of a
+// user variable, as in TAG.NAME or SRC.VAR0.NAME.
+func isVariableName(prefix string) bool {
+	if !strings.HasSuffix(prefix, ".") {
+		return false
+	}
+	start := len(prefix)
+	for start > 0 && (isAsciiLetter(prefix[start-1]) || isDigit(prefix[start-1]) || prefix[start-1] == '_' || prefix[start-1] == '.') {
+		start--
+	}
+	return inVariableNamespace(strings.ToUpper(prefix[start:]) + "NAME")
+}
+
+// writeLineDiff prints the lines that differ between before and after as a
+// unified diff; a rename never adds or removes lines.
+func writeLineDiff(w io.Writer, file, before, after string) {
+	oldLines, newLines := strings.Split(before, "\n"), strings.Split(after, "\n")
+	fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", file, file)
+	for i := range oldLines {
+		if i < len(newLines) && oldLines[i] != newLines[i] {
+			fmt.Fprintf(w, "@@ -%d +%d @@\n-%s\n+%s\n", i+1, i+1, strings.TrimSuffix(oldLines[i], "\r"), strings.TrimSuffix(newLines[i], "\r"))
+		}
+	}
+}
+
+// checkRename returns why oldName cannot be renamed to newName, or "".
+func checkRename(index *lintIndex, oldName, newName string) string {
+	if !isIdentifier(newName) {
+		return fmt.Sprintf("'%s' is not a valid name", newName)
+	}
+	defined := func(name string) (definitionLocation, bool) {
+		if loc, ok := index.ids[name]; ok {
+			return loc, true
+		}
+		loc, ok := index.defnames[name]
+		return loc, ok
+	}
+	if _, ok := defined(strings.ToUpper(oldName)); !ok {
+		return fmt.Sprintf("'%s' is not defined", oldName)
+	}
+	if strings.EqualFold(oldName, newName) {
+		return ""
+	}
+	if loc, ok := defined(strings.ToUpper(newName)); ok {
+		return fmt.Sprintf("'%s' already exists at %s:%d", newName, loc.file, loc.line)
+	}
+	return ""
+}
+
+// runRename implements "sphere-lint rename OLD NEW": it renames a
+// definition in its header or DEFNAME and in every reference of the tree.
+// With -dry-run it only prints the changes as a diff, ready for patch.
+func runRename(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint rename", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	dryRun := flags.Bool("dry-run", false, "print the changes as a diff without rewriting any file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: sphere-lint rename [-config file] [-dry-run] OLD_NAME NEW_NAME")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	oldName, newName := flags.Arg(0), flags.Arg(1)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+	if len(walkIssues) > 0 {
+		return 2
+	}
+	if reason := checkRename(index, oldName, newName); reason != "" {
+		fmt.Fprintf(os.Stderr, "rename: %s.\n", reason)
+		return 1
+	}
+
+	re := renamePattern(oldName)
+	lines := renameLines(index, oldName)
+	changed := 0
+	failed := false
+	var leftover []string
+	walkScripts(scriptsRoot, func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			printError(lintIssue{file: toRelative(path), line: 1, kind: "CRITICAL", msg: err.Error()})
+			failed = true
+			return
+		}
+		renamed := renameIdentifier(string(data), re, newName, lines[toRelative(path)])
+		for _, lineNum := range leftoverMatches(renamed, re) {
+			leftover = append(leftover, fmt.Sprintf("%s:%d", toRelative(path), lineNum))
+		}
+		if renamed == string(data) {
+			return
+		}
+		changed++
+		if *dryRun {
+			writeLineDiff(os.Stdout, toRelative(path), string(data), renamed)
+			return
+		}
+		if err := os.WriteFile(path, []byte(renamed), 0o644); err != nil {
+			printError(lintIssue{file: toRelative(path), line: 1, kind: "CRITICAL", msg: err.Error()})
+			failed = true
+			return
+		}
+		fmt.Printf("renamed: %s\n", toRelative(path))
+	})
+
+	for _, loc := range leftover {
+		fmt.Fprintf(os.Stderr, "rename: %s still mentions '%s' in text or an unrecognized use; check it by hand.\n", loc, oldName)
+	}
+	if !*dryRun {
+		fmt.Println("---------------------------------------------")
+		fmt.Printf("Files changed: %d\n", changed)
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameIdentifier(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_old_name]",
+		"ID=0f61",
+		"// i_old_name is kept in comments",
+		"[FUNCTION f_test]",
+		"SRC.NEWITEM I_Old_Name // i_old_name",
+		"SRC.NEWITEM i_old_name_2",
+		"[EOF]",
+	)
+	got := renameIdentifier(content, renamePattern("i_old_name"), "i_new_name", map[int]bool{1: true, 5: true, 6: true})
+	want := joinLines(
+		"[ITEMDEF i_new_name]",
+		"ID=0f61",
+		"// i_old_name is kept in comments",
+		"[FUNCTION f_test]",
+		"SRC.NEWITEM i_new_name // i_old_name",
+		"SRC.NEWITEM i_old_name_2",
+		"[EOF]",
+	)
+	if got != want {
+		t.Fatalf("unexpected rename:\n%s\nwant:\n%s", got, want)
+	}
+
+	var out bytes.Buffer
+	writeLineDiff(&out, "a.scp", content, got)
+	wantDiff := joinLines(
+		"--- a/a.scp",
+		"+++ b/a.scp",
+		"@@ -1 +1 @@",
+		"-[ITEMDEF i_old_name]",
+		"+[ITEMDEF i_new_name]",
+		"@@ -5 +5 @@",
+		"-SRC.NEWITEM I_Old_Name // i_old_name",
+		"+SRC.NEWITEM i_new_name // i_old_name",
+	)
+	if out.String() != wantDiff {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", out.String(), wantDiff)
+	}
+}
+
+func TestRunRename(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	items := writeTempFile(t, dir, "items.scp", joinLines(
+		"[ITEMDEF i_sword_old]",
+		"ID=0f61",
+		"[ITEMDEF i_sword_taken]",
+		"ID=0f61",
+		"[EOF]",
+	))
+	funcsBefore := joinLines(
+		"[FUNCTION f_test]",
+		"SRC.NEWITEM i_sword_old",
+		"SRC.TAG.i_sword_old=1",
+		"SAY i_sword_old is here",
+		"LOCAL.i_sword_old=<VAR.i_sword_old>",
+		"LOCAL.SWORD=i_sword_old",
+		`SYSMESSAGE "i_sword_old"`,
+		"[EOF]",
+	)
+	funcs := writeTempFile(t, dir, "funcs.scp", funcsBefore)
+
+	if code := runRename([]string{"i_sword_old", "i_sword_taken"}); code != 1 {
+		t.Fatalf("expected renaming onto an existing name to be refused, got %d", code)
+	}
+	if code := runRename([]string{"i_sword_gone", "i_sword_new"}); code != 1 {
+		t.Fatalf("expected renaming an undefined name to be refused, got %d", code)
+	}
+	if code := runRename([]string{"-dry-run", "i_sword_old", "i_sword_new"}); code != 0 {
+		t.Fatalf("expected the dry run to succeed, got %d", code)
+	}
+	if data, _ := os.ReadFile(funcs); string(data) != funcsBefore {
+		t.Fatalf("expected the dry run to leave files alone, got %q", data)
+	}
+	if code := runRename([]string{"i_sword_old", "i_sword_new"}); code != 0 {
+		t.Fatalf("expected the rename to succeed, got %d", code)
+	}
+	for path, want := range map[string]string{
+		items: joinLines("[ITEMDEF i_sword_new]", "ID=0f61", "[ITEMDEF i_sword_taken]", "ID=0f61", "[EOF]"),
+		funcs: joinLines(
+			"[FUNCTION f_test]",
+			"SRC.NEWITEM i_sword_new",
+			"SRC.TAG.i_sword_old=1",
+			"SAY i_sword_old is here",
+			"LOCAL.i_sword_old=<VAR.i_sword_old>",
+			"LOCAL.SWORD=i_sword_new",
+			`SYSMESSAGE "i_sword_old"`,
+			"[EOF]",
+		),
+	} {
+		if data, _ := os.ReadFile(path); string(data) != want {
+			t.Fatalf("unexpected %s after the rename: %q", filepath.Base(path), data)
+		}
+	}
+}
+
+func TestRunRenameCalls(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	path := writeTempFile(t, dir, "funcs.scp", joinLines(
+		"[FUNCTION f_old]",
+		"RETURN 1",
+		"[FUNCTION heal_me]",
+		"SRC.HITS=10",
+		"[FUNCTION f_test]",
+		"IF <f_old>",
+		"  heal_me",
+		"ENDIF",
+		"WHILE (<f_old> == 1)",
+		"  SRC.heal_me",
+		"ENDWHILE",
+		"SRC.SYSMESSAGE <f_old>",
+		"[EOF]",
+	))
+
+	if code := runRename([]string{"f_old", "f_new"}); code != 0 {
+		t.Fatalf("expected the rename to succeed, got %d", code)
+	}
+	if code := runRename([]string{"heal_me", "cure_me"}); code != 0 {
+		t.Fatalf("expected the rename to succeed, got %d", code)
+	}
+	want := joinLines(
+		"[FUNCTION f_new]",
+		"RETURN 1",
+		"[FUNCTION cure_me]",
+		"SRC.HITS=10",
+		"[FUNCTION f_test]",
+		"IF <f_new>",
+		"  cure_me",
+		"ENDIF",
+		"WHILE (<f_new> == 1)",
+		"  SRC.cure_me",
+		"ENDWHILE",
+		"SRC.SYSMESSAGE <f_new>",
+		"[EOF]",
+	)
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Fatalf("unexpected funcs.scp after the rename:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestLeftoverMatches(t *testing.T) {
+	content := joinLines(
+		"SAY f_old is gone",
+		"TAG.f_old=1 // f_old",
+		"SRC.f_old_2",
+	)
+	if got := leftoverMatches(content, renamePattern("f_old")); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected only line 1 to be left over, got %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// resourceIniName is the server config whose [RESOURCES] section is checked
+// when it sits in the scripts root.
+const resourceIniName = "sphere.ini"
+
+var resourcesHeaderPattern = regexp.MustCompile(`(?i)^\[RESOURCES\]$`)
+
+// resourceEntry is a script path listed in a [RESOURCES] section.
+type resourceEntry struct {
+	path string
+	file string
+	line int
+	dir  bool
+}
+
+// resourceList collects the [RESOURCES] entries of spheretables.scp and
+// sphere.ini, keyed by their normalized path.
+type resourceList struct {
+	entries map[string]resourceEntry
+}
+
+func newResourceList() *resourceList {
+	return &resourceList{entries: make(map[string]resourceEntry)}
+}
+
+// add records one [RESOURCES] line and reports it when the path does not
+// exist under the scripts root or was already listed.
+func (r *resourceList) add(line, file string, lineNum int) []lintIssue {
+	path := strings.ReplaceAll(strings.TrimSpace(line), "\\", "/")
+	if path == "" {
+		return nil
+	}
+	var issues []lintIssue
+	key := strings.ToLower(strings.TrimSuffix(path, "/"))
+	if prev, ok := r.entries[key]; ok {
+		return appendWarning(issues, file, lineNum, "DUPLICATE", fmt.Sprintf("DUPLICATE: resource '%s' is already listed at %s:%d.", path, prev.file, prev.line))
+	}
+	found, dir := resolveResource(path)
+	r.entries[key] = resourceEntry{path: path, file: file, line: lineNum, dir: dir}
+	if !found {
+		issues = appendError(issues, file, lineNum, "RESOURCE", fmt.Sprintf("RESOURCE: '%s' is listed in [RESOURCES] but does not exist under the scripts root.", path))
+	}
+	return issues
+}
+
+// resolveResource reports whether a [RESOURCES] path names a file or
+// directory under the scripts root, and which. The server adds the .scp
+// extension to paths that have none.
+func resolveResource(path string) (found, dir bool) {
+	full := filepath.Join(scriptsRoot, filepath.FromSlash(path))
+	if info, err := os.Stat(full); err == nil {
+		return true, info.IsDir()
+	}
+	if strings.HasSuffix(path, "/") || filepath.Ext(path) != "" {
+		return false, false
+	}
+	_, err := os.Stat(full + ".scp")
+	return err == nil, false
+}
+
+// loads reports whether the server loads the script at rel, a path relative
+// to the scripts root: it is listed itself, sits under a listed directory or
+// holds a [RESOURCES] section.
+func (r *resourceList) loads(rel string) bool {
+	key := strings.ToLower(rel)
+	if _, ok := r.entries[key]; ok {
+		return true
+	}
+	if _, ok := r.entries[strings.TrimSuffix(key, ".scp")]; ok {
+		return true
+	}
+	for entryKey, entry := range r.entries {
+		if entry.dir && strings.HasPrefix(key, entryKey+"/") {
+			return true
+		}
+		if strings.EqualFold(entry.file, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// findUnregisteredScripts warns about scanned scripts the [RESOURCES] list
+// does not reach, since the server silently never loads them. Nothing is
+// reported when no [RESOURCES] section was found.
+func findUnregisteredScripts(index *lintIndex) []lintIssue {
+	if len(index.resources.entries) == 0 {
+		return nil
+	}
+	var issues []lintIssue
+	for _, rel := range index.scripts {
+		if !index.resources.loads(rel) {
+			issues = appendWarning(issues, rel, 1, "UNREGISTERED", "UNREGISTERED: script is not listed in [RESOURCES], directly or through a directory, so the server never loads it.")
+		}
+	}
+	return issues
+}
+
+// lintResourceIni checks the [RESOURCES] section of the sphere.ini in the
+// scripts root, if there is one.
+func lintResourceIni(index *lintIndex) []lintIssue {
+	path := filepath.Join(scriptsRoot, resourceIniName)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	rel := toRelative(path)
+	var issues []lintIssue
+	inResources := false
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		cleaned := cleanLine(scanner.Text())
+		if cleaned == "" {
+			continue
+		}
+		if strings.HasPrefix(cleaned, "[") {
+			inResources = resourcesHeaderPattern.MatchString(cleaned)
+			continue
+		}
+		if inResources {
+			issues = append(issues, index.resources.add(cleaned, rel, lineNum)...)
+		}
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		issues = appendError(issues, rel, lineNum, "CRITICAL", scanErr.Error())
+	}
+	return issues
+}
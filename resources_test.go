@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLintResources(t *testing.T) {
+	t.Run("Spheretables", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		if err := os.MkdirAll(filepath.Join(dir, "items"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		writeTempFile(t, dir, "sphere_defs.scp", "[EOF]\n")
+		writeTempFile(t, filepath.Join(dir, "items"), "weapons.scp", "[EOF]\n")
+		path := writeTempFile(t, dir, "spheretables.scp", joinLines(
+			"[RESOURCES]",
+			"sphere_defs.scp",
+			"items\\weapons",
+			"items/",
+			"items/armor.scp // not written yet",
+			"SPHERE_DEFS.scp",
+			"[EOF]",
+		))
+
+		errs := lintScriptFile(path, newLintIndex())
+		assertHasMessage(t, errs, "RESOURCE: 'items/armor.scp' is listed in [RESOURCES] but does not exist under the scripts root.")
+		assertHasMessage(t, errs, "DUPLICATE: resource 'SPHERE_DEFS.scp' is already listed at spheretables.scp:2.")
+		if len(errs) != 2 || errs[0].line != 5 || errs[1].line != 6 || errs[1].severity != severityWarning {
+			t.Fatalf("expected a missing resource at line 5 and a duplicate warning at line 6, got %+v", errs)
+		}
+	})
+
+	t.Run("SphereIni", func(t *testing.T) {
+		dir := withTempScriptsDir(t)
+		writeTempFile(t, dir, "sphere_defs.scp", "[EOF]\n")
+		writeTempFile(t, dir, resourceIniName, joinLines(
+			"[SPHERE]",
+			"ServName=Test",
+			"[RESOURCES]",
+			"sphere_defs.scp",
+			"missing.scp",
+		))
+		index := newLintIndex()
+		index.resources.add("sphere_defs.scp", "spheretables.scp", 2)
+
+		errs := lintResourceIni(index)
+		assertHasMessage(t, errs, "DUPLICATE: resource 'sphere_defs.scp' is already listed at spheretables.scp:2.")
+		assertHasMessage(t, errs, "RESOURCE: 'missing.scp' is listed in [RESOURCES]")
+		if len(errs) != 2 || errs[0].file != resourceIniName || errs[1].line != 5 {
+			t.Fatalf("expected two sphere.ini issues, got %+v", errs)
+		}
+	})
+
+	t.Run("NoIni", func(t *testing.T) {
+		withTempScriptsDir(t)
+		assertNoErrors(t, lintResourceIni(newLintIndex()), "a scripts root without sphere.ini")
+	})
+}
+
+func TestLintUnregisteredScripts(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "items", "weapons"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeTempFile(t, dir, "spheretables.scp", joinLines(
+		"[RESOURCES]",
+		"sphere_defs",
+		"items/",
+		"[EOF]",
+	))
+	writeTempFile(t, dir, "sphere_defs.scp", "[EOF]\n")
+	writeTempFile(t, dir, "old_defs.scp", "[EOF]\n")
+	writeTempFile(t, filepath.Join(dir, "items", "weapons"), "swords.scp", "[EOF]\n")
+
+	index := newLintIndex()
+	errs := walkScripts(dir, func(path string) {
+		lintScriptFile(path, index)
+	})
+	errs = append(errs, lintIndexIssues(index)...)
+	assertHasMessage(t, errs, "UNREGISTERED: script is not listed in [RESOURCES]")
+	if len(errs) != 1 || errs[0].file != "old_defs.scp" || errs[0].severity != severityWarning {
+		t.Fatalf("expected only old_defs.scp to be unregistered, got %+v", errs)
+	}
+
+	t.Run("NoResources", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "lonely.scp", "[EOF]\n"), "a tree without [RESOURCES]")
+	})
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// triggerlessSections are the sections the server reads as plain lists
+	// or settings; an ON= line there is never run as a trigger.
+	triggerlessSections = map[string]bool{
+		"DEFMESSAGE": true, "DEFNAME": true, "DEFNAMES": true, "FAME": true,
+		"KARMA": true, "MOONGATES": true, "NOTOTITLES": true, "OBSCENE": true,
+		"PLEVEL": true, "RESDEFNAME": true, "RESOURCES": true, "RES_RESDEFNAME": true,
+		"RUNES": true, "SERVERS": true, "SPAWN": true, "SPHERE": true,
+		"STARTS": true, "TELEPORTERS": true, "TEMPLATE": true,
+	}
+
+	// bareHeaderPattern matches headers without an ID, such as [SPHERE].
+	bareHeaderPattern = regexp.MustCompile(`^\[(\w+)\]$`)
+)
+
+// sectionState holds the validators that follow one [SECTION] header until
+// the next header or the end of the file.
+type sectionState struct {
+	defType       string
+	geometry      *regionGeometry
+	dialogLayout  *dialogInfo
+	dialogText    *dialogInfo
+	dialogButtons *dialogInfo
+	menu          *menuState
+	trigger       *triggerContext
+	locals        *localScope
+	owner         *triggerOwner
+	body          *triggerBody
+	template      string
+	label         string
+	caller        string
+	events        map[string]int
+	skill         *skillState
+	privilege     *privilegeScope
+	typeData      *typeDataState
+	typeDef       *typeDefContent
+	stray         *strayLines
+	name          *sectionName
+	symbol        *symbolInfo
+	copy          *sectionBody
+	indent        byte
+}
+
+// preambleLines counts the statements of a file before its first section
+// header, which the server skips.
+type preambleLines struct {
+	closed bool
+	first  int
+	count  int
+}
+
+func (p *preambleLines) add(lineNum int) {
+	if p.first == 0 {
+		p.first = lineNum
+	}
+	p.count++
+}
+
+// finish warns once per file, at the first ignored line. A file that never
+// reaches a header is an error: the server ignores all of it.
+func (p *preambleLines) finish(file string) []lintIssue {
+	if p.count == 0 {
+		return nil
+	}
+	if !p.closed {
+		return appendError(nil, file, p.first, "LOGIC", fmt.Sprintf("LOGIC: file has %d statement(s) but no [SECTION] header; the server ignores the whole file.", p.count))
+	}
+	return appendWarning(nil, file, p.first, "LOGIC", fmt.Sprintf("LOGIC: %d line(s) before the first [SECTION] header; the server ignores them.", p.count))
+}
+
+// checkMisplacedTrigger reports an ON= line in a section that cannot hold
+// triggers; the lines below it are dead code.
+func checkMisplacedTrigger(section sectionState, line, file string, lineNum int) []lintIssue {
+	if !triggerlessSections[section.defType] {
+		return nil
+	}
+	return appendError(nil, file, lineNum, "LOGIC", fmt.Sprintf("LOGIC: '%s' is under [%s], which cannot hold triggers; the code below it never runs.", strings.TrimSpace(line), section.defType))
+}
+
+// triggerBody counts the statements of the current ON=@ trigger.
+type triggerBody struct {
+	name       string
+	line       int
+	statements int
+}
+
+func beginSection(index *lintIndex, defType, defArgs, file string, lineNum int) sectionState {
+	section := sectionState{defType: defType, owner: index.triggerOwner(defType, defArgs), label: defType + " " + strings.ToUpper(firstField(defArgs))}
+	section.symbol = index.newSymbol(defType, defArgs, file, lineNum)
+	if ruleEnabled("duplicate-sections") {
+		section.copy = index.bodies.begin(section.label, file, lineNum)
+	}
+	switch defType {
+	case "CHARDEF", "ITEMDEF":
+		section.events = make(map[string]int)
+		if ruleEnabled("missing-name") {
+			section.name = newSectionName(section.label, defArgs, lineNum)
+		}
+		if defType == "ITEMDEF" {
+			section.typeData = newTypeDataState(&index.references)
+		}
+	case "AREADEF", "ROOMDEF":
+		section.geometry = newRegionGeometry(defType, defArgs, file, lineNum)
+		index.regions.add(section.geometry)
+	case "FUNCTION":
+		section.locals = newLocalScope()
+		section.caller = strings.ToUpper(firstField(defArgs))
+		section.privilege = newFunctionPrivilege(section.caller)
+		index.calls.addNode(section.caller, file, lineNum)
+	case "EVENTS":
+		section.stray = &strayLines{section: "EVENTS " + firstField(defArgs)}
+	case "TYPEDEF":
+		section.typeDef = &typeDefContent{id: firstField(defArgs), line: lineNum}
+	case "SKILL":
+		section.skill = newSkillState(defArgs)
+	case "TEMPLATE":
+		section.template = strings.ToUpper(firstField(defArgs))
+		index.templates.addNode(section.template)
+	case "MENU":
+		section.menu = newMenuState(defArgs, lineNum)
+	case "DIALOG":
+		dialogID, kind := dialogSection(defArgs)
+		if dialogID == "" {
+			break
+		}
+		switch kind {
+		case "":
+			section.dialogLayout = index.dialog(dialogID)
+		case "TEXT":
+			section.dialogText = index.dialog(dialogID)
+			section.dialogText.text = &definitionLocation{file: file, line: lineNum}
+			section.dialogText.textLines = 0
+		case "BUTTON":
+			section.dialogButtons = index.dialog(dialogID)
+			section.dialogButtons.buttons = &definitionLocation{file: file, line: lineNum}
+		}
+	}
+	return section
+}
+
+// finish runs the checks that need the whole section.
+func (s sectionState) finish(file string) []lintIssue {
+	var issues []lintIssue
+	issues = append(issues, s.body.finish(file)...)
+	issues = append(issues, s.geometry.finish(file)...)
+	issues = append(issues, s.menu.finish(file)...)
+	issues = append(issues, s.locals.finish(file)...)
+	issues = append(issues, s.skill.finish(file)...)
+	issues = append(issues, s.typeData.finish(file)...)
+	issues = append(issues, s.typeDef.finish(file)...)
+	issues = append(issues, s.stray.finish(file)...)
+	issues = append(issues, s.name.finish(file)...)
+	issues = append(issues, s.copy.finish()...)
+	return issues
+}
+
+// endSection finishes s, which ends at lastLine, and tags the references
+// collected since the previous section with its label, so they can be traced
+// back to the definition that makes them.
+func (idx *lintIndex) endSection(s sectionState, file string, lastLine int) []lintIssue {
+	issues := s.finish(file)
+	if s.symbol != nil {
+		s.symbol.end = lastLine
+	}
+	for i := idx.labelled; i < len(idx.references); i++ {
+		idx.references[i].from = s.label
+	}
+	idx.labelled = len(idx.references)
+	return issues
+}
+
+// typeDefContent counts what a [TYPEDEF] section gives its items: ON=
+// triggers or TERRAIN= properties.
+type typeDefContent struct {
+	id      string
+	line    int
+	entries int
+}
+
+// addLine counts an ON= trigger or TERRAIN= line of the section.
+func (t *typeDefContent) addLine(line string) {
+	if t == nil {
+		return
+	}
+	if key, _, ok := splitAssignment(line); ok && (key == "ON" || key == "TERRAIN") {
+		t.entries++
+	}
+}
+
+// finish warns about a TYPEDEF that gives its items nothing; its triggers
+// usually ended up under another section by mistake.
+func (t *typeDefContent) finish(file string) []lintIssue {
+	if t == nil || t.entries > 0 {
+		return nil
+	}
+	return appendWarning(nil, file, t.line, "LOGIC", fmt.Sprintf("LOGIC: TYPEDEF %s has no ON= triggers or TERRAIN= lines; were its triggers put under another section?", t.id))
+}
+
+// strayLines counts the lines of an [EVENTS] section outside any ON=
+// trigger; the server only reads triggers there and ignores them.
+type strayLines struct {
+	section string
+	first   int
+	count   int
+}
+
+func (s *strayLines) add(lineNum int) {
+	if s == nil {
+		return
+	}
+	if s.first == 0 {
+		s.first = lineNum
+	}
+	s.count++
+}
+
+// finish warns once per section, at the first ignored line.
+func (s *strayLines) finish(file string) []lintIssue {
+	if s == nil || s.count == 0 {
+		return nil
+	}
+	return appendWarning(nil, file, s.first, "LOGIC", fmt.Sprintf("LOGIC: %s has %d line(s) outside any ON= trigger; the server ignores them.", s.section, s.count))
+}
+
+// sectionName tracks whether an ITEMDEF or CHARDEF gives its object a name,
+// either with NAME= or by inheriting one through ID= another defname.
+type sectionName struct {
+	label string
+	line  int
+	named bool
+}
+
+// newSectionName returns nil for hex IDs: those base definitions take their
+// name from the client files.
+func newSectionName(label, defArgs string, lineNum int) *sectionName {
+	if isHexDigits(firstField(defArgs)) {
+		return nil
+	}
+	return &sectionName{label: label, line: lineNum}
+}
+
+func (n *sectionName) addLine(line string) {
+	if n == nil {
+		return
+	}
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" {
+		return
+	}
+	if _, numeric := parseSphereInt(value); key == "NAME" || (key == "ID" && !numeric) {
+		n.named = true
+	}
+}
+
+// finish warns about an unnamed object; players see it as a raw defname or
+// a client name with a literal %s in it.
+func (n *sectionName) finish(file string) []lintIssue {
+	if n == nil || n.named {
+		return nil
+	}
+	return appendWarning(nil, file, n.line, "NAME", fmt.Sprintf("NAME: %s has no NAME= and does not inherit one through ID=; players see a raw defname or \"%%s\" in game.", n.label))
+}
+
+// newTriggerBody starts counting the body of an ON=@ trigger line. Other ON=
+// lines (menu options, dialog buttons) may legitimately be empty.
+func newTriggerBody(line string, lineNum int) *triggerBody {
+	match := triggerNamePattern.FindStringSubmatch(line)
+	if len(match) != 2 {
+		return nil
+	}
+	return &triggerBody{name: match[1], line: lineNum}
+}
+
+func (b *triggerBody) addStatement() {
+	if b != nil {
+		b.statements++
+	}
+}
+
+// finish reports a trigger that ended without any statement.
+func (b *triggerBody) finish(file string) []lintIssue {
+	if b == nil || b.statements > 0 {
+		return nil
+	}
+	return appendWarning(nil, file, b.line, "LOGIC", fmt.Sprintf("LOGIC: trigger @%s has no statements.", b.name))
+}
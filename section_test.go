@@ -0,0 +1,174 @@
+package main
+
+import "testing"
+
+func TestLintEmptyTriggers(t *testing.T) {
+	t.Run("EmptyBodies", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"ON=@Step",
+			"// nothing yet",
+			"[ITEMDEF i_other]",
+			"ON=@Click",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "empty_triggers.scp", content)
+		assertHasMessage(t, errs, "LOGIC: trigger @DClick has no statements.")
+		assertHasMessage(t, errs, "LOGIC: trigger @Step has no statements.")
+		assertHasMessage(t, errs, "LOGIC: trigger @Click has no statements.")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 empty trigger warnings, got %+v", errs)
+		}
+	})
+
+	t.Run("NonEmptyBodies", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[DIALOG d_test BUTTON]",
+			"ON=0",
+			"[MENU m_test]",
+			"Pick one",
+			"ON=0 Nothing",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "empty_triggers_ok.scp", content), "non-empty triggers")
+	})
+}
+
+func TestLintEmptyTypedefs(t *testing.T) {
+	content := joinLines(
+		"[TYPEDEF t_altar]",
+		"[ITEMDEF i_altar]",
+		"ID=02d0",
+		"TYPE=t_altar",
+		"ON=@DClick",
+		"SRC.SYSMESSAGE You pray.",
+		"[TYPEDEF t_swamp]",
+		"TERRAIN=03d 03e",
+		"[TYPEDEF t_shrine]",
+		"ON=@Step",
+		"SRC.SYSMESSAGE Blessed.",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "typedefs.scp", content)
+	assertHasMessage(t, errs, "LOGIC: TYPEDEF t_altar has no ON= triggers or TERRAIN= lines; were its triggers put under another section?")
+	if len(errs) != 1 || errs[0].line != 1 || errs[0].severity != severityWarning {
+		t.Fatalf("expected one warning at line 1, got %+v", errs)
+	}
+}
+
+func TestLintEventsOutsideTriggers(t *testing.T) {
+	content := joinLines(
+		"[EVENTS e_guard]",
+		"NAME=Guard events",
+		"TAG.ALERT=1",
+		"ON=@Hear",
+		"SAY Halt!",
+		"[EVENTS e_shopkeeper]",
+		"ON=@NPCRestock",
+		"RETURN 0",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "events_stray.scp", content)
+	assertHasMessage(t, errs, "LOGIC: EVENTS e_guard has 2 line(s) outside any ON= trigger; the server ignores them.")
+	if len(errs) != 1 || errs[0].line != 2 {
+		t.Fatalf("expected one warning at line 2, got %+v", errs)
+	}
+}
+
+func TestLintMissingNames(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_plain_box]",
+		"ID=09a8",
+		"[ITEMDEF i_named_box]",
+		"ID=09a8",
+		"NAME=wooden box",
+		"[ITEMDEF i_fancy_box]",
+		"ID=i_named_box",
+		"[ITEMDEF 0e75]",
+		"WEIGHT=5",
+		"[CHARDEF c_guard_captain]",
+		"ID=0190",
+		"ON=@Create",
+		"NAME=Captain",
+		"[CHARDEF c_drifter]",
+		"ID=0190",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "names.scp", content), "unnamed definitions without the missing-name rule")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.Enable = []string{"missing-name"}
+		})
+		errs := lintFromContent(t, "names.scp", content)
+		assertHasMessage(t, errs, `NAME: ITEMDEF I_PLAIN_BOX has no NAME= and does not inherit one through ID=; players see a raw defname or "%s" in game.`)
+		assertHasMessage(t, errs, `NAME: CHARDEF C_DRIFTER has no NAME= and does not inherit one through ID=; players see a raw defname or "%s" in game.`)
+		if len(errs) != 2 || errs[0].line != 1 || errs[1].line != 14 {
+			t.Fatalf("expected warnings on lines 1 and 14, got %+v", errs)
+		}
+	})
+}
+
+func TestLintMisplacedTriggers(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_lamp]",
+		"ID=0a22",
+		"ON=@DClick",
+		"SRC.SYSMESSAGE Lit.",
+		"[DEFNAME lamp_colors]",
+		"lamp_red 021",
+		"ON=@Create",
+		"COLOR=lamp_red",
+		"[SPHERE]",
+		"ON=@Login",
+		"SRC.SYSMESSAGE Welcome",
+		"[RESOURCES]",
+		"ON=@Logout",
+		"SRC.SYSMESSAGE Bye",
+		"[SPEECH spk_lamp]",
+		"ON=*lamp*",
+		"SAY Lamps!",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "misplaced.scp", content)
+	assertHasMessage(t, errs, "LOGIC: 'ON=@Create' is under [DEFNAME], which cannot hold triggers; the code below it never runs.")
+	assertHasMessage(t, errs, "LOGIC: 'ON=@Login' is under [SPHERE], which cannot hold triggers")
+	assertHasMessage(t, errs, "LOGIC: 'ON=@Logout' is under [RESOURCES], which cannot hold triggers")
+	if len(errs) != 3 || errs[0].line != 7 || errs[1].line != 10 || errs[2].line != 13 {
+		t.Fatalf("expected errors on lines 7, 10 and 13, got %+v", errs)
+	}
+}
+
+func TestLintPreambleLines(t *testing.T) {
+	content := joinLines(
+		"// Lamp scripts",
+		"SRC.SYSMESSAGE loaded",
+		"",
+		"ON=@Create",
+		"[ITEMDEF i_lamp]",
+		"ID=0a22",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "preamble.scp", content)
+	assertHasMessage(t, errs, "LOGIC: 2 line(s) before the first [SECTION] header; the server ignores them.")
+	if len(errs) != 1 || errs[0].line != 2 || errs[0].severity != severityWarning {
+		t.Fatalf("expected one warning at line 2, got %+v", errs)
+	}
+
+	t.Run("CommentOnly", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "preamble.scp", joinLines("// header comment", "[ITEMDEF i_lamp]", "ID=0a22", "[EOF]")), "comments before the first header")
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// skillBonusKeys split a skill's stat gain between the three stats, in
+// percent.
+var skillBonusKeys = []string{"BONUS_STR", "BONUS_DEX", "BONUS_INT"}
+
+// skillState collects the properties of one [SKILL n] section that are
+// checked together once the section ends.
+type skillState struct {
+	id        string
+	bonuses   map[string]int
+	bonusLine int
+}
+
+func newSkillState(args string) *skillState {
+	return &skillState{id: firstField(args), bonuses: make(map[string]int)}
+}
+
+// checkSkillHeader reports skill indexes outside the configured skill table.
+func checkSkillHeader(args, file string, lineNum int) []lintIssue {
+	id := firstField(args)
+	n, ok := parseSphereInt(id)
+	if !ok {
+		return nil
+	}
+	if n < 0 || n >= config.MaxSkills {
+		return appendError(nil, file, lineNum, "SKILL", fmt.Sprintf("SKILL: skill index %s is outside the skill table (0-%d); raise maxSkills if the server is built with more", id, config.MaxSkills-1))
+	}
+	return nil
+}
+
+// addLine checks a property line of the section outside its triggers. KEY
+// names must be unique across every file, since the server looks skills up by
+// KEY when it loads saves.
+func (s *skillState) addLine(line string, keys map[string]definitionLocation, file string, lineNum int) []lintIssue {
+	if s == nil {
+		return nil
+	}
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" || strings.ContainsAny(value, "<>") {
+		return nil
+	}
+	switch key {
+	case "KEY":
+		name := strings.ToUpper(value)
+		if prev, ok := keys[name]; ok {
+			return appendError(nil, file, lineNum, "DUPLICATE", fmt.Sprintf("DUPLICATE: skill KEY '%s' already defined at %s:%d.", value, prev.file, prev.line))
+		}
+		keys[name] = definitionLocation{file: file, line: lineNum}
+	case "ADV_RATE":
+		parts := strings.Split(value, ",")
+		if len(parts) != 3 || !allDecimal(parts) {
+			return appendError(nil, file, lineNum, "SKILL", fmt.Sprintf("SKILL: ADV_RATE '%s' must be three numbers (low,mid,high)", value))
+		}
+	case "DELAY":
+		parts := strings.Split(value, ",")
+		if len(parts) > 2 || !allDecimal(parts) {
+			return appendError(nil, file, lineNum, "SKILL", fmt.Sprintf("SKILL: DELAY '%s' must be a number or min,max range", value))
+		}
+	case "BONUS_STATS":
+		if n, ok := parseSphereInt(value); !ok || n < 0 || n > 100 {
+			return appendError(nil, file, lineNum, "SKILL", fmt.Sprintf("SKILL: BONUS_STATS '%s' must be a percentage (0-100)", value))
+		}
+	case "BONUS_STR", "BONUS_DEX", "BONUS_INT":
+		n, ok := parseSphereInt(value)
+		if !ok {
+			return appendError(nil, file, lineNum, "SKILL", fmt.Sprintf("SKILL: %s '%s' is not a number", key, value))
+		}
+		s.bonuses[key] = n
+		if s.bonusLine == 0 {
+			s.bonusLine = lineNum
+		}
+	}
+	return nil
+}
+
+// finish reports BONUS_STR/DEX/INT values that do not add up to 100.
+func (s *skillState) finish(file string) []lintIssue {
+	if s == nil || len(s.bonuses) == 0 {
+		return nil
+	}
+	sum := 0
+	parts := make([]string, 0, len(skillBonusKeys))
+	for _, key := range skillBonusKeys {
+		sum += s.bonuses[key]
+		parts = append(parts, fmt.Sprintf("%s=%d", key, s.bonuses[key]))
+	}
+	if sum == 100 {
+		return nil
+	}
+	return appendError(nil, file, s.bonusLine, "SKILL", fmt.Sprintf("SKILL: %s bonuses add up to %d (%s); they split the stat gain and must total 100", s.id, sum, strings.Join(parts, ", ")))
+}
+
+func allDecimal(parts []string) bool {
+	for _, part := range parts {
+		if !isDecimalNumber(strings.TrimSpace(part)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestLintSkillSections(t *testing.T) {
+	t.Run("ValidSkill", func(t *testing.T) {
+		content := joinLines(
+			"[SKILL 0]",
+			"DEFNAME=SKILL_ALCHEMY",
+			"KEY=Alchemy",
+			"TITLE=Alchemist",
+			"DELAY=1.0,3.0",
+			"ADV_RATE=10.0,200.0,800.0",
+			"BONUS_STATS=10",
+			"BONUS_STR=0",
+			"BONUS_DEX=50",
+			"BONUS_INT=50",
+			"ON=@Success",
+			"SRC.SYSMESSAGE done",
+			"[SKILL 1]",
+			"KEY=Anatomy",
+			"DELAY=2",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "skill_valid.scp", content), "valid skills")
+	})
+
+	t.Run("Reported", func(t *testing.T) {
+		content := joinLines(
+			"[SKILL 2]",
+			"KEY=Alchemy",
+			"ADV_RATE=10.0,200.0",
+			"DELAY=fast",
+			"BONUS_STATS=150",
+			"BONUS_STR=40",
+			"BONUS_DEX=40",
+			"[SKILL 3]",
+			"KEY=ALCHEMY",
+			"[SKILL 58]",
+			"KEY=Extra",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "skill_errors.scp", content)
+		assertHasMessage(t, errs, "SKILL: ADV_RATE '10.0,200.0' must be three numbers (low,mid,high)")
+		assertHasMessage(t, errs, "SKILL: DELAY 'fast' must be a number or min,max range")
+		assertHasMessage(t, errs, "SKILL: BONUS_STATS '150' must be a percentage (0-100)")
+		assertHasMessage(t, errs, "SKILL: 2 bonuses add up to 80 (BONUS_STR=40, BONUS_DEX=40, BONUS_INT=0); they split the stat gain and must total 100")
+		assertHasMessage(t, errs, "DUPLICATE: skill KEY 'ALCHEMY' already defined at skill_errors.scp:2.")
+		assertHasMessage(t, errs, "SKILL: skill index 58 is outside the skill table (0-57)")
+		if len(errs) != 6 {
+			t.Fatalf("expected 6 errors, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredMaxSkills", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.MaxSkills = 60
+		})
+		content := joinLines(
+			"[SKILL 58]",
+			"KEY=Extra",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "skill_config.scp", content), "a skill within maxSkills")
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// soundCallPattern matches SOUND and ANIM as properties (SOUND=0x51) and as
+// verbs (SRC.SOUND 0x51,1), on any object.
+var soundCallPattern = regexp.MustCompile(`(?i)^\s*((?:[a-z0-9_.]+\.)?)(SOUND|ANIM)(?:\s*=\s*|\s+)(.*)$`)
+
+// checkSoundCall validates the ID of SOUND and ANIM lines. Only the first
+// argument is the ID; SOUND takes a repeat count after it.
+func checkSoundCall(line, file string, lineNum int) []lintIssue {
+	match := soundCallPattern.FindStringSubmatch(line)
+	if len(match) != 4 {
+		return nil
+	}
+	if inVariableNamespace(strings.ToUpper(match[1] + match[2])) {
+		return nil
+	}
+	value, _, _ := strings.Cut(match[3], ",")
+	return checkIDRange(strings.ToUpper(match[2]), strings.TrimSpace(value), file, lineNum)
+}
+
+// checkIDRange checks a SOUND or ANIM ID against its configured idRanges
+// entry. Sound IDs past what the client knows crash older clients, and
+// unknown animations freeze the character. Defnames and <...> expressions
+// are left alone.
+func checkIDRange(name, value, file string, lineNum int) []lintIssue {
+	if value == "" || isIdentifier(value) || strings.ContainsAny(value, "<>{}") {
+		return nil
+	}
+	if problem := literalProblem(value); problem != "" {
+		return appendError(nil, file, lineNum, "ID", fmt.Sprintf("ID: %s %s", name, problem))
+	}
+	n, ok := parseSphereInt(value)
+	if !ok {
+		return appendError(nil, file, lineNum, "ID", fmt.Sprintf("ID: %s '%s' is not a number", name, value))
+	}
+	if r, ok := config.IDRanges[name]; ok && (n < r.Min || n > r.Max) {
+		return appendError(nil, file, lineNum, "ID", fmt.Sprintf("ID: %s %s is outside the valid range 0%x..0%x", name, value, r.Min, r.Max))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestLintSoundAndAnimIDs(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_chime]",
+		"ID=0e75",
+		"ON=@DClick",
+		"SRC.SOUND 0x51,1",
+		"SOUND=snd_chime",
+		"SOUND <MORE1>",
+		"ANIM 012",
+		"SOUND 09000",
+		"SRC.ANIM=0300",
+		"SOUND 51g",
+		"LOCAL.SOUND=09000",
+		"[SPELL 1]",
+		"DEFNAME=s_chime",
+		"SOUND=0900",
+		"[EOF]",
+	)
+
+	t.Run("Default", func(t *testing.T) {
+		errs := lintFromContent(t, "sounds.scp", content)
+		assertHasMessage(t, errs, "ID: SOUND 09000 is outside the valid range 00..07ff")
+		assertHasMessage(t, errs, "ID: ANIM 0300 is outside the valid range 00..0ff")
+		assertHasMessage(t, errs, "ID: SOUND '51g' is not a valid number")
+		assertHasMessage(t, errs, "ID: SOUND 0900 is outside the valid range 00..07ff")
+		if len(errs) != 4 || errs[0].line != 8 || errs[1].line != 9 || errs[2].line != 10 || errs[3].line != 14 {
+			t.Fatalf("expected errors on lines 8, 9, 10 and 14, got %+v", errs)
+		}
+	})
+
+	t.Run("ConfiguredRanges", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.IDRanges = map[string]valueRange{"SOUND": {Min: 0, Max: 0xFFFF}}
+		})
+		errs := lintFromContent(t, "sounds.scp", content)
+		if len(errs) != 1 || errs[0].line != 10 {
+			t.Fatalf("expected only the malformed SOUND on line 10, got %+v", errs)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	spellProperties = map[string]bool{
+		"CAST_TIME":   true,
+		"DEFNAME":     true,
+		"DEFNAME2":    true,
+		"DURATION":    true,
+		"EFFECT":      true,
+		"EFFECT_ID":   true,
+		"FLAGS":       true,
+		"GROUP":       true,
+		"INTERRUPT":   true,
+		"LAYER":       true,
+		"MANAUSE":     true,
+		"NAME":        true,
+		"PROMPT_MSG":  true,
+		"RESOURCES":   true,
+		"RUNE_ITEM":   true,
+		"RUNES":       true,
+		"SCROLL_ITEM": true,
+		"SKILLREQ":    true,
+		"SOUND":       true,
+		"TITHINGUSE":  true,
+	}
+
+	spellFlagBits = map[string]int64{
+		"SPELLFLAG_DIR_ANIM":       0x00000001,
+		"SPELLFLAG_TARG_ITEM":      0x00000002,
+		"SPELLFLAG_TARG_CHAR":      0x00000004,
+		"SPELLFLAG_TARG_OBJ":       0x00000006,
+		"SPELLFLAG_TARG_XYZ":       0x00000008,
+		"SPELLFLAG_HARM":           0x00000010,
+		"SPELLFLAG_FX_BOLT":        0x00000020,
+		"SPELLFLAG_FIELD":          0x00000040,
+		"SPELLFLAG_SUMMON":         0x00000080,
+		"SPELLFLAG_GOOD":           0x00000100,
+		"SPELLFLAG_RESIST":         0x00000200,
+		"SPELLFLAG_TARG_NOSELF":    0x00000400,
+		"SPELLFLAG_FREEZEONCAST":   0x00000800,
+		"SPELLFLAG_DISABLED":       0x00008000,
+		"SPELLFLAG_SCRIPTED":       0x00010000,
+		"SPELLFLAG_PLAYERONLY":     0x00020000,
+		"SPELLFLAG_NOUNPARALYZE":   0x00040000,
+		"SPELLFLAG_NO_CASTANIM":    0x00080000,
+		"SPELLFLAG_TARG_NO_PLAYER": 0x00100000,
+		"SPELLFLAG_TARG_NO_NPC":    0x00200000,
+		"SPELLFLAG_NOPRECAST":      0x00400000,
+		"SPELLFLAG_NOFREEZEONCAST": 0x00800000,
+		"SPELLFLAG_AREA":           0x01000000,
+		"SPELLFLAG_POLY":           0x02000000,
+		"SPELLFLAG_TARG_DEAD":      0x04000000,
+		"SPELLFLAG_DAMAGE":         0x08000000,
+		"SPELLFLAG_BLESS":          0x10000000,
+		"SPELLFLAG_CURSE":          0x20000000,
+		"SPELLFLAG_HEAL":           0x40000000,
+		"SPELLFLAG_TICK":           0x80000000,
+	}
+
+	spellFlagMask = func() int64 {
+		var mask int64
+		for _, bit := range spellFlagBits {
+			mask |= bit
+		}
+		return mask
+	}()
+)
+
+// validateSpellLine checks a property line of a [SPELL] section (outside its
+// triggers).
+func validateSpellLine(line, file string, lineNum int) []lintIssue {
+	key, value, ok := splitAssignment(line)
+	if !ok {
+		return nil
+	}
+	base := key
+	if idx := strings.IndexByte(base, '.'); idx >= 0 {
+		base = base[:idx]
+	}
+	if base == "TAG" || base == "TAG0" {
+		return nil
+	}
+	if !spellProperties[key] {
+		return appendError(nil, file, lineNum, "SPELL", fmt.Sprintf("SPELL: unknown spell property '%s'", key))
+	}
+	if value == "" || strings.ContainsAny(value, "<>") {
+		return nil
+	}
+	switch key {
+	case "FLAGS":
+		return validateSpellFlags(value, file, lineNum)
+	case "SOUND":
+		if _, ok := parseSphereInt(value); !ok && !isIdentifier(value) {
+			return appendError(nil, file, lineNum, "SPELL", fmt.Sprintf("SPELL: SOUND '%s' is not a sound ID", value))
+		}
+		return checkIDRange(key, value, file, lineNum)
+	case "RUNES":
+		for i := 0; i < len(value); i++ {
+			if !isAsciiLetter(value[i]) {
+				return appendError(nil, file, lineNum, "SPELL", fmt.Sprintf("SPELL: RUNES '%s' must contain rune letters only", value))
+			}
+		}
+	case "CAST_TIME":
+		for _, part := range strings.Split(value, ",") {
+			if !isDecimalNumber(strings.TrimSpace(part)) {
+				return appendError(nil, file, lineNum, "SPELL", fmt.Sprintf("SPELL: CAST_TIME '%s' must be a number or min,max range", value))
+			}
+		}
+	}
+	return nil
+}
+
+func validateSpellFlags(value, file string, lineNum int) []lintIssue {
+	var issues []lintIssue
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == '+' || r == ' ' || r == '\t' }) {
+		upper := strings.ToUpper(part)
+		if n, ok := parseSphereInt(part); ok {
+			if extra := int64(uint32(n)) &^ spellFlagMask; extra != 0 {
+				issues = appendError(issues, file, lineNum, "SPELL", fmt.Sprintf("SPELL: FLAGS value %s sets unknown spell flag bits 0%x", part, extra))
+			}
+			continue
+		}
+		if strings.HasPrefix(upper, "SPELLFLAG_") {
+			if _, ok := spellFlagBits[upper]; !ok {
+				issues = appendError(issues, file, lineNum, "SPELL", fmt.Sprintf("SPELL: unknown spell flag '%s'", part))
+			}
+			continue
+		}
+		if !isIdentifier(part) {
+			issues = appendError(issues, file, lineNum, "SPELL", fmt.Sprintf("SPELL: malformed FLAGS value '%s'", part))
+		}
+	}
+	return issues
+}
+
+func isIdentifier(value string) bool {
+	return value != "" && templateIdentPattern.FindString(value) == value
+}
+
+func isAsciiLetter(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
+
+// isDecimalNumber accepts plain and fractional decimal numbers such as "15"
+// or "1.5".
+func isDecimalNumber(value string) bool {
+	if value == "" {
+		return false
+	}
+	dot := false
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] >= '0' && value[i] <= '9':
+		case value[i] == '.' && !dot && i > 0 && i < len(value)-1:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return true
+}
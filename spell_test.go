@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func TestLintSpellSections(t *testing.T) {
+	t.Run("ValidSpell", func(t *testing.T) {
+		content := joinLines(
+			"[SPELL 18]",
+			"DEFNAME=s_fireball",
+			"NAME=Fireball",
+			"SOUND=015e",
+			"RUNES=VF",
+			"CAST_TIME=1.5",
+			"INTERRUPT=100,100",
+			"FLAGS=SPELLFLAG_DIR_ANIM | SPELLFLAG_TARG_CHAR | SPELLFLAG_HARM | 020",
+			"RESOURCES=i_reag_black_pearl",
+			"TAG.SCHOOL=fire",
+			"ON=@Effect",
+			"SRC.DAMAGE 10",
+			"[ITEMDEF i_reag_black_pearl]",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "spell_valid.scp", content), "valid spell")
+	})
+
+	t.Run("UnknownFlags", func(t *testing.T) {
+		content := joinLines(
+			"[SPELL 18]",
+			"FLAGS=SPELLFLAG_TARG_CHARS|01000|spell-flag",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "spell_flags.scp", content)
+		assertHasMessage(t, errs, "SPELL: unknown spell flag 'SPELLFLAG_TARG_CHARS'")
+		assertHasMessage(t, errs, "SPELL: FLAGS value 01000 sets unknown spell flag bits 01000")
+		assertHasMessage(t, errs, "SPELL: malformed FLAGS value 'spell-flag'")
+	})
+
+	t.Run("PropertyFormats", func(t *testing.T) {
+		content := joinLines(
+			"[SPELL 18]",
+			"SOUND=0x15e!",
+			"RUNES=V F",
+			"CAST_TIME=fast",
+			"CASTTIME=15",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "spell_formats.scp", content)
+		assertHasMessage(t, errs, "SPELL: SOUND '0x15e!' is not a sound ID")
+		assertHasMessage(t, errs, "SPELL: RUNES 'V F' must contain rune letters only")
+		assertHasMessage(t, errs, "SPELL: CAST_TIME 'fast' must be a number or min,max range")
+		assertHasMessage(t, errs, "SPELL: unknown spell property 'CASTTIME'")
+	})
+}
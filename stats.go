@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// countEntry is one row of a "sphere-lint stats" table.
+type countEntry struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// packStats is what "sphere-lint stats" reports about a pack.
+type packStats struct {
+	Definitions     []countEntry `json:"definitions"`
+	Triggers        []countEntry `json:"triggers"`
+	Resolved        int          `json:"resolvedReferences"`
+	Unresolved      int          `json:"unresolvedReferences"`
+	Directories     []countEntry `json:"directories"`
+	LargestFiles    []countEntry `json:"largestFiles"`
+	LargestSections []countEntry `json:"largestSections"`
+}
+
+// sortedCounts turns counts into rows, the largest first, keeping at most
+// limit rows when limit is positive.
+func sortedCounts(counts map[string]int, limit int) []countEntry {
+	rows := make([]countEntry, 0, len(counts))
+	for name, count := range counts {
+		rows = append(rows, countEntry{Name: name, Count: count})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// collectStats summarises index; fileLines holds the line count of every
+// script and top bounds the largest file and section lists.
+func collectStats(index *lintIndex, fileLines map[string]int, top int) packStats {
+	var stats packStats
+
+	definitions := make(map[string]int)
+	sections := make(map[string]int)
+	for _, s := range index.symbols {
+		definitions[s.Type]++
+		if s.end >= s.Line {
+			sections[fmt.Sprintf("%s:%d %s %s", s.File, s.Line, s.Type, s.ID)] = s.end - s.Line + 1
+		}
+	}
+	stats.Definitions = sortedCounts(definitions, 0)
+	stats.LargestSections = sortedCounts(sections, top)
+
+	triggers := make(map[string]int)
+	for key, owner := range index.owners {
+		defType, _, _ := strings.Cut(key, " ")
+		triggers[defType] += len(owner.triggers)
+	}
+	stats.Triggers = sortedCounts(triggers, 0)
+
+	counted := make(map[string]bool)
+	for _, ref := range index.references {
+		key := fmt.Sprintf("%s:%d:%s", ref.file, ref.line, ref.id)
+		if counted[key] {
+			continue
+		}
+		counted[key] = true
+		if referenceResolved(ref, index.defs, index.defnames, index.ids) {
+			stats.Resolved++
+		} else {
+			stats.Unresolved++
+		}
+	}
+
+	directories := make(map[string]int)
+	for _, file := range index.scripts {
+		directories[path.Dir(file)]++
+	}
+	stats.Directories = sortedCounts(directories, 0)
+	stats.LargestFiles = sortedCounts(fileLines, top)
+	return stats
+}
+
+// writeStatsText prints stats as aligned tables.
+func writeStatsText(w io.Writer, stats packStats) {
+	table := func(title string, rows []countEntry, unit string) {
+		fmt.Fprintf(w, "%s:\n", title)
+		width := 0
+		for _, row := range rows {
+			width = max(width, len(row.Name))
+		}
+		for _, row := range rows {
+			fmt.Fprintf(w, "  %-*s  %d%s\n", width, row.Name, row.Count, unit)
+		}
+	}
+	table("Definitions per type", stats.Definitions, "")
+	table("Triggers per type", stats.Triggers, "")
+	fmt.Fprintf(w, "References: %d resolved, %d unresolved\n", stats.Resolved, stats.Unresolved)
+	table("Files per directory", stats.Directories, "")
+	table("Largest files", stats.LargestFiles, " lines")
+	table("Largest sections", stats.LargestSections, " lines")
+}
+
+// countLines returns the number of lines of data, with or without a final
+// newline.
+func countLines(data []byte) int {
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		lines++
+	}
+	return lines
+}
+
+// runStats implements "sphere-lint stats": it prints counts that show how a
+// pack grows over time, as text or JSON.
+func runStats(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint stats", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	format := flags.String("format", "text", "output format: text or json")
+	top := flags.Int("top", 10, "number of largest files and sections to list")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "stats: unknown -format %q (use text or json)\n", *format)
+		return 2
+	}
+
+	index := newLintIndex()
+	fileLines := make(map[string]int)
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		if data, err := os.ReadFile(path); err == nil {
+			fileLines[toRelative(path)] = countLines(data)
+		}
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	stats := collectStats(index, fileLines, *top)
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(stats); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	} else {
+		writeStatsText(os.Stdout, stats)
+	}
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
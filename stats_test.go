@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollectStats(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	if err := os.Mkdir(filepath.Join(dir, "npcs"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	index := newLintIndex()
+	files := map[string]string{
+		"items.scp": joinLines(
+			"[ITEMDEF i_sword_fire]",
+			"ID=0f61",
+			"ON=@Equip",
+			"SRC.NEWITEM i_ember",
+			"ON=@Unequip",
+			"SRC.NEWITEM i_sword_fire",
+			"[EOF]",
+		),
+		"npcs/dragon.scp": joinLines(
+			"[CHARDEF c_dragon_fire]",
+			"ID=c_dragon",
+			"ON=@Death",
+			"SERV.LOG dead",
+			"[EOF]",
+		),
+	}
+	fileLines := make(map[string]int)
+	for name, content := range files {
+		lintScriptFile(writeTempFile(t, dir, name, content), index)
+		fileLines[name] = countLines([]byte(content))
+	}
+
+	stats := collectStats(index, fileLines, 1)
+	if stats.Resolved != 1 || stats.Unresolved != 2 {
+		t.Fatalf("expected i_sword_fire resolved and i_ember and c_dragon unresolved, got %+v", stats)
+	}
+	if len(stats.LargestFiles) != 1 || stats.LargestFiles[0] != (countEntry{Name: "items.scp", Count: 7}) {
+		t.Fatalf("expected items.scp as the largest file, got %+v", stats.LargestFiles)
+	}
+	if len(stats.LargestSections) != 1 || stats.LargestSections[0] != (countEntry{Name: "items.scp:1 ITEMDEF i_sword_fire", Count: 6}) {
+		t.Fatalf("expected i_sword_fire as the largest section, got %+v", stats.LargestSections)
+	}
+
+	var out bytes.Buffer
+	writeStatsText(&out, stats)
+	for _, want := range []string{
+		"Definitions per type:\n  CHARDEF  1\n  ITEMDEF  1\n",
+		"Triggers per type:\n  ITEMDEF  2\n  CHARDEF  1\n",
+		"References: 1 resolved, 2 unresolved\n",
+		"Files per directory:\n  .     1\n  npcs  1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in the statistics:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// stringFunctionArgs holds the minimum number of comma-separated
+	// arguments of the string functions. The last argument takes the rest of
+	// the text, so only a lower bound can be checked.
+	stringFunctionArgs = map[string]int{
+		"ASC":        1,
+		"ASCPAD":     2,
+		"STRARG":     1,
+		"STRCMP":     2,
+		"STRCMPI":    2,
+		"STREAT":     1,
+		"STRINDEXOF": 2,
+		"STRLEN":     1,
+		"STRMATCH":   2,
+		"STRPOS":     3,
+		"STRREGEX":   2,
+		"STRREVERSE": 1,
+		"STRSUB":     3,
+		"STRTOLOWER": 1,
+		"STRTOUPPER": 1,
+		"STRTRIM":    1,
+	}
+
+	stringFunctionPattern = regexp.MustCompile(`(?i)\b(ASC|ASCPAD|STRARG|STRCMP|STRCMPI|STREAT|STRINDEXOF|STRLEN|STRMATCH|STRPOS|STRREGEX|STRREVERSE|STRSUB|STRTOLOWER|STRTOUPPER|STRTRIM)\s*\(`)
+)
+
+// checkStringFunctions validates the argument count of string function
+// calls such as <STRCMP(<SRC.NAME>,Bob)>. A missing comma makes the call
+// compare against an empty string, which is always false.
+func checkStringFunctions(line, file string, lineNum int) []lintIssue {
+	var issues []lintIssue
+	for _, loc := range stringFunctionPattern.FindAllStringSubmatchIndex(line, -1) {
+		if loc[0] > 0 && (line[loc[0]-1] == '.' || line[loc[0]-1] == '_') {
+			continue
+		}
+		name := strings.ToUpper(line[loc[2]:loc[3]])
+		args, ok := functionArguments(line, loc[1])
+		if !ok {
+			continue
+		}
+		want := stringFunctionArgs[name]
+		got := len(args)
+		if got == 1 && strings.TrimSpace(args[0]) == "" {
+			got = 0
+		}
+		if got < want {
+			issues = appendError(issues, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s expects at least %d comma-separated argument(s), got %d", name, want, got))
+		}
+	}
+	return issues
+}
+
+// functionArguments splits the text after an opening parenthesis into its
+// top-level comma-separated arguments, up to the matching ')'. Commas inside
+// nested (...) and <...> expressions do not split arguments.
+func functionArguments(line string, start int) ([]string, bool) {
+	var args []string
+	parenDepth := 0
+	angleDepth := 0
+	argStart := start
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '(':
+			parenDepth++
+		case ')':
+			if parenDepth == 0 && angleDepth == 0 {
+				return append(args, line[argStart:i]), true
+			}
+			if parenDepth > 0 {
+				parenDepth--
+			}
+		case '<':
+			if i+1 < len(line) && isAngleTokenStart(line[i+1]) {
+				angleDepth++
+			}
+		case '>':
+			if angleDepth > 0 {
+				angleDepth--
+			}
+		case ',':
+			if parenDepth == 0 && angleDepth == 0 {
+				args = append(args, line[argStart:i])
+				argStart = i + 1
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestLintStringFunctions(t *testing.T) {
+	t.Run("ValidCalls", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"IF (<STRCMPI(<SRC.NAME>,Bob)> == 0)",
+			"  SRC.SYSMESSAGE <STRSUB(0,3,<SRC.NAME>)>",
+			"ENDIF",
+			"LOCAL.LEN=<STRLEN(<ARGS>)>",
+			"LOCAL.POS=<STRPOS(0,44,<STRARG(a, b, c)>)>",
+			"IF (<STRMATCH(*dragon*,<SRC.NAME>)>)",
+			"ENDIF",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "strfunc_valid.scp", content), "valid string function calls")
+	})
+
+	t.Run("MissingCommas", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"IF (<STRCMP(<SRC.NAME> Bob)> == 0)",
+			"ENDIF",
+			"SRC.SYSMESSAGE <STRSUB(0 3,<SRC.NAME>)>",
+			"LOCAL.LEN=<STRLEN()>",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "strfunc_commas.scp", content)
+		assertHasMessage(t, errs, "SYNTAX: STRCMP expects at least 2 comma-separated argument(s), got 1")
+		assertHasMessage(t, errs, "SYNTAX: STRSUB expects at least 3 comma-separated argument(s), got 2")
+		assertHasMessage(t, errs, "SYNTAX: STRLEN expects at least 1 comma-separated argument(s), got 0")
+	})
+
+	t.Run("PropertyNamesAreNotCalls", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.STRLEN(1)",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "strfunc_props.scp", content), "property names")
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import "strings"
+
+// formatTabWidth is the number of spaces a tab stands for when the
+// formatter converts indentation between tabs and spaces.
+const formatTabWidth = 4
+
+// checkTrailingWhitespace reports spaces or tabs at the end of a raw line,
+// at the column where they start.
+func checkTrailingWhitespace(raw, file string, lineNum int) []lintIssue {
+	trimmed := strings.TrimRight(raw, " \t")
+	if len(trimmed) == len(raw) {
+		return nil
+	}
+	return []lintIssue{{file: file, line: lineNum, col: len(trimmed) + 1, kind: "STYLE", msg: "STYLE: trailing whitespace", severity: severityWarning}}
+}
+
+// checkIndentStyle reports indentation that uses a different character
+// than the first indented line of the current block (section or trigger
+// body). style holds the block's indent character, 0 until one is seen.
+func checkIndentStyle(style *byte, raw, file string, lineNum int) []lintIssue {
+	indent := raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))]
+	if indent == "" {
+		return nil
+	}
+	if *style == 0 {
+		*style = indent[0]
+	}
+	idx := strings.IndexFunc(indent, func(r rune) bool { return byte(r) != *style })
+	if idx < 0 {
+		return nil
+	}
+	uses := "spaces"
+	if *style == '\t' {
+		uses = "tabs"
+	}
+	return []lintIssue{{file: file, line: lineNum, col: idx + 1, kind: "STYLE", msg: "STYLE: indentation mixes tabs and spaces (this block is indented with " + uses + ")", severity: severityWarning}}
+}
+
+// formatScript strips a UTF-8 byte order mark and trailing whitespace and
+// rewrites the indentation of every block in the style of its first indented
+// line. BOOK and COMMENT text keeps its indentation.
+func formatScript(content string) string {
+	content = strings.TrimPrefix(content, "\ufeff")
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var style byte
+	inText := false
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		cleaned := cleanLine(line)
+		if defMatch := defHeaderPattern.FindStringSubmatch(cleaned); len(defMatch) == 3 {
+			defType := strings.ToUpper(defMatch[1])
+			inText = defType == "BOOK" || defType == "COMMENT"
+			style = 0
+		} else if commentHeaderPattern.MatchString(cleaned) {
+			inText = true
+		} else if !inText && triggerPattern.MatchString(cleaned) {
+			style = 0
+		}
+		body := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(body)]
+		if indent != "" && !inText {
+			if style == 0 {
+				style = indent[0]
+			}
+			line = reindent(indent, style) + body
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, newline)
+}
+
+// reindent converts indent to the given style, keeping its visual width
+// (tabs count as formatTabWidth spaces; partial tabs round up).
+func reindent(indent string, style byte) string {
+	width := 0
+	for i := 0; i < len(indent); i++ {
+		if indent[i] == '\t' {
+			width += formatTabWidth
+		} else {
+			width++
+		}
+	}
+	if style == '\t' {
+		return strings.Repeat("\t", (width+formatTabWidth-1)/formatTabWidth)
+	}
+	return strings.Repeat(" ", width)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLintStyleRules(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_test] ",
+		"IF <SRC.ISPLAYER>",
+		"\tSRC.SYSMESSAGE hi",
+		"    SRC.SYSMESSAGE there\t",
+		"ENDIF",
+		"[FUNCTION f_other]",
+		"IF <SRC.ISPLAYER>",
+		"    SRC.SYSMESSAGE spaces",
+		"ENDIF",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "style.scp", content), "style while the rules are off")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"trailing-whitespace", "mixed-indent"} })
+
+		errs := lintFromContent(t, "style.scp", content)
+		assertHasMessage(t, errs, "STYLE: trailing whitespace")
+		assertHasMessage(t, errs, "STYLE: indentation mixes tabs and spaces (this block is indented with tabs)")
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 style warnings, got %+v", errs)
+		}
+		want := [][2]int{{1, 18}, {4, 25}, {4, 1}}
+		for i, e := range errs {
+			if e.line != want[i][0] || e.col != want[i][1] {
+				t.Fatalf("expected issue %d at %d:%d, got %+v", i, want[i][0], want[i][1], e)
+			}
+		}
+	})
+}
+
+func TestFormatScript(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_test] ",
+		"IF <SRC.ISPLAYER>",
+		"\tSRC.SYSMESSAGE hi",
+		"      SRC.SYSMESSAGE there\t",
+		"ENDIF",
+		"[BOOK b_test]",
+		"  keep  ",
+		"[EOF]",
+	)
+	want := joinLines(
+		"[FUNCTION f_test]",
+		"IF <SRC.ISPLAYER>",
+		"\tSRC.SYSMESSAGE hi",
+		"\t\tSRC.SYSMESSAGE there",
+		"ENDIF",
+		"[BOOK b_test]",
+		"  keep",
+		"[EOF]",
+	)
+
+	if got := formatScript(content); got != want {
+		t.Fatalf("unexpected formatting:\n%q\nwant:\n%q", got, want)
+	}
+	if got := formatScript(want); got != want {
+		t.Fatalf("formatting is not idempotent:\n%q", got)
+	}
+	if got := formatScript("[EOF] \r\n"); got != "[EOF]\r\n" {
+		t.Fatalf("expected CRLF line endings to be kept, got %q", got)
+	}
+}
+
+func TestRunFormat(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	path := writeTempFile(t, dir, "format.scp", joinLines("[FUNCTION f_test]  ", "[EOF]"))
+
+	if code := runFormat([]string{"-check"}); code != 1 {
+		t.Fatalf("expected -check to fail on unformatted files, got %d", code)
+	}
+	if code := runFormat(nil); code != 0 {
+		t.Fatalf("expected formatting to succeed, got %d", code)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(data) != joinLines("[FUNCTION f_test]", "[EOF]") {
+		t.Fatalf("unexpected formatted file: %q", data)
+	}
+	if code := runFormat([]string{"-check"}); code != 0 {
+		t.Fatalf("expected -check to pass after formatting, got %d", code)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// symbolProperties are the properties of a definition worth exporting for
+// wikis and item browsers.
+var symbolProperties = map[string]bool{
+	"BRAIN": true, "CATEGORY": true, "DESCRIPTION": true, "DISPID": true,
+	"ID": true, "NAME": true, "RESOURCES": true, "SUBSECTION": true,
+	"TYPE": true, "VALUE": true, "WEIGHT": true,
+}
+
+// symbolInfo is one tracked definition as "sphere-lint symbols" exports it.
+type symbolInfo struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Defnames   []string          `json:"defnames"`
+	File       string            `json:"file"`
+	Line       int               `json:"line"`
+	Properties map[string]string `json:"properties"`
+	// end is the last line of the section.
+	end int
+}
+
+// newSymbol starts the export entry of a tracked definition and adds it to
+// the index; it returns nil for other sections.
+func (idx *lintIndex) newSymbol(defType, defArgs, file string, lineNum int) *symbolInfo {
+	id := firstField(defArgs)
+	if !trackDefTypes[defType] || id == "" {
+		return nil
+	}
+	symbol := &symbolInfo{Type: defType, ID: id, Defnames: []string{}, File: file, Line: lineNum, Properties: map[string]string{}}
+	idx.symbols = append(idx.symbols, symbol)
+	return symbol
+}
+
+// addLine records DEFNAME= names and the exported properties of a line
+// outside triggers. The first value of a property wins.
+func (s *symbolInfo) addLine(line string) {
+	if s == nil {
+		return
+	}
+	if name := parseDefnameAssignment(line); name != "" {
+		s.Defnames = append(s.Defnames, name)
+		return
+	}
+	key, value, ok := splitAssignment(line)
+	if !ok || !symbolProperties[key] {
+		return
+	}
+	if _, seen := s.Properties[key]; !seen {
+		s.Properties[key] = value
+	}
+}
+
+// sortedSymbols returns the definitions of index sorted by type and ID.
+func sortedSymbols(index *lintIndex) []*symbolInfo {
+	symbols := append([]*symbolInfo{}, index.symbols...)
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].Type != symbols[j].Type {
+			return symbols[i].Type < symbols[j].Type
+		}
+		return strings.ToUpper(symbols[i].ID) < strings.ToUpper(symbols[j].ID)
+	})
+	return symbols
+}
+
+// writeSymbolsJSON writes the definitions of index sorted by type and ID.
+func writeSymbolsJSON(w io.Writer, index *lintIndex) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sortedSymbols(index))
+}
+
+// runSymbols implements "sphere-lint symbols": it exports every tracked
+// definition with its DEFNAMEs, location and key properties, for tools such
+// as wiki generators and item browsers.
+func runSymbols(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint symbols", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	format := flags.String("format", "json", "output format: json")
+	outPath := flags.String("out", "", "write the symbols to this file instead of stdout")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *format != "json" {
+		fmt.Fprintf(os.Stderr, "symbols: unknown -format %q (use json)\n", *format)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeSymbolsJSON(out, index); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSymbols(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "items.scp", joinLines(
+		"[ITEMDEF i_sword_fire]",
+		"DEFNAME=i_sword_flame",
+		"ID=0f61",
+		"NAME=Fire Sword",
+		"TYPE=t_weapon_sword",
+		"VALUE=500",
+		"ON=@Equip",
+		"NAME=Burning Sword",
+		"[CHARDEF c_dragon_fire]",
+		"ID=c_dragon",
+		"NAME=Fire Dragon",
+		"[FUNCTION f_test]",
+		"SERV.LOG hi",
+		"[EOF]",
+	))
+	outPath := filepath.Join(t.TempDir(), "symbols.json")
+	if code := runSymbols([]string{"-format", "json", "-out", outPath}); code != 0 {
+		t.Fatalf("expected symbols to succeed, got %d", code)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read symbols: %v", err)
+	}
+	var symbols []symbolInfo
+	if err := json.Unmarshal(data, &symbols); err != nil {
+		t.Fatalf("decode symbols: %v\n%s", err, data)
+	}
+	if len(symbols) != 3 {
+		t.Fatalf("expected 3 symbols, got %+v", symbols)
+	}
+	dragon, sword := symbols[0], symbols[2]
+	if dragon.Type != "CHARDEF" || dragon.Properties["ID"] != "c_dragon" || dragon.Line != 9 {
+		t.Fatalf("unexpected dragon symbol %+v", dragon)
+	}
+	if sword.Type != "ITEMDEF" || sword.File != "items.scp" || sword.Line != 1 {
+		t.Fatalf("unexpected sword symbol %+v", sword)
+	}
+	if len(sword.Defnames) != 1 || sword.Defnames[0] != "i_sword_flame" {
+		t.Fatalf("expected DEFNAME i_sword_flame, got %+v", sword.Defnames)
+	}
+	if sword.Properties["NAME"] != "Fire Sword" || sword.Properties["VALUE"] != "500" {
+		t.Fatalf("expected the properties above the triggers, got %+v", sword.Properties)
+	}
+	if code := runSymbols([]string{"-format", "xml"}); code != 2 {
+		t.Fatalf("expected an unknown format to fail, got %d", code)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// symbolIndexVersion is bumped whenever the saved index format changes.
+const symbolIndexVersion = 2
+
+// symbolIndexJSON is the saved form of the definitions of a script pack,
+// written by "sphere-lint index" and read back with -use-index.
+// TemplateAliases maps the DEFNAME= of a template onto its section ID.
+type symbolIndexJSON struct {
+	Version         int                `json:"version"`
+	Definitions     []symbolEntryJSON  `json:"definitions"`
+	Defnames        []symbolEntryJSON  `json:"defnames"`
+	IDs             []symbolEntryJSON  `json:"ids"`
+	Owners          []triggerOwnerJSON `json:"owners"`
+	Templates       []string           `json:"templates"`
+	TemplateAliases map[string]string  `json:"templateAliases"`
+}
+
+type symbolEntryJSON struct {
+	Key  string `json:"key"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+type triggerOwnerJSON struct {
+	Key      string   `json:"key"`
+	Triggers []string `json:"triggers"`
+	Attached []string `json:"attached,omitempty"`
+}
+
+func symbolEntries(locations map[string]definitionLocation) []symbolEntryJSON {
+	entries := []symbolEntryJSON{}
+	for key, loc := range locations {
+		entries = append(entries, symbolEntryJSON{Key: key, File: loc.file, Line: loc.line})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func symbolLocations(entries []symbolEntryJSON) map[string]definitionLocation {
+	locations := make(map[string]definitionLocation, len(entries))
+	for _, entry := range entries {
+		locations[entry.Key] = definitionLocation{file: entry.File, line: entry.Line}
+	}
+	return locations
+}
+
+// writeSymbolIndex saves the definitions of index as JSON.
+func writeSymbolIndex(w io.Writer, index *lintIndex) error {
+	out := symbolIndexJSON{
+		Version:     symbolIndexVersion,
+		Definitions: symbolEntries(index.defs),
+		Defnames:    symbolEntries(index.defnames),
+		IDs:         symbolEntries(index.ids),
+		Owners:      []triggerOwnerJSON{},
+	}
+	for _, owner := range index.owners {
+		entry := triggerOwnerJSON{Key: owner.key, Triggers: []string{}, Attached: owner.attached}
+		for name := range owner.triggers {
+			entry.Triggers = append(entry.Triggers, name)
+		}
+		sort.Strings(entry.Triggers)
+		out.Owners = append(out.Owners, entry)
+	}
+	sort.Slice(out.Owners, func(i, j int) bool { return out.Owners[i].Key < out.Owners[j].Key })
+	out.Templates = []string{}
+	for id := range index.templates.edges {
+		out.Templates = append(out.Templates, id)
+	}
+	sort.Strings(out.Templates)
+	out.TemplateAliases = index.templates.aliases
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// readSymbolIndex loads a saved index into a new lintIndex that holds only
+// definitions and template names, ready for addLibrary. Template contents
+// are not saved, so the templates of a base pack are not checked again.
+func readSymbolIndex(path string) (*lintIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var saved symbolIndexJSON
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if saved.Version != symbolIndexVersion {
+		return nil, fmt.Errorf("%s: index version %d is not supported (expected %d); rebuild it with sphere-lint index", path, saved.Version, symbolIndexVersion)
+	}
+	index := newLintIndex()
+	index.defs = symbolLocations(saved.Definitions)
+	index.defnames = symbolLocations(saved.Defnames)
+	index.ids = symbolLocations(saved.IDs)
+	for _, entry := range saved.Owners {
+		owner := &triggerOwner{key: entry.Key, triggers: make(map[string]bool), attached: entry.Attached}
+		for _, name := range entry.Triggers {
+			owner.triggers[name] = true
+		}
+		index.owners[entry.Key] = owner
+	}
+	for _, id := range saved.Templates {
+		index.templates.addNode(id)
+	}
+	for name, id := range saved.TemplateAliases {
+		index.templates.addAlias(name, id)
+	}
+	return index, nil
+}
+
+// runIndex implements "sphere-lint index": it scans the scripts and saves
+// their definitions, so jobs that lint a plugin pack against a large base
+// pack can load them with -use-index instead of scanning the base pack.
+func runIndex(args []string) int {
+	flags := flag.NewFlagSet("sphere-lint index", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a JSON config file (default: "+defaultConfigName+" in the scripts root)")
+	outPath := flags.String("out", "", "write the index to this file instead of stdout")
+	flags.Parse(args)
+
+	if err := setupConfig(*configPath, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	index := newLintIndex()
+	walkIssues := walkScripts(scriptsRoot, func(path string) {
+		lintScriptFile(path, index)
+	})
+	for _, issue := range walkIssues {
+		printError(issue)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeSymbolIndex(out, index); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(walkIssues) > 0 {
+		return 1
+	}
+	return 0
+}
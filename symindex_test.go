@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSymbolIndexRoundTrip(t *testing.T) {
+	dir := withTempScriptsDir(t)
+	writeTempFile(t, dir, "base.scp", joinLines(
+		"[ITEMDEF i_gold]",
+		"ID=0eed",
+		"EVENTS=e_coin",
+		"[EVENTS e_coin]",
+		"ON=@DClick",
+		"SRC.SYSMESSAGE Shiny",
+		"[DEFNAME coins]",
+		"coin_value 1",
+		"[TEMPLATE 100]",
+		"DEFNAME=vendor_s_banker",
+		"ITEM=i_gold",
+		"[EOF]",
+	))
+	outPath := filepath.Join(t.TempDir(), "defs.idx")
+	if code := runIndex([]string{"-out", outPath}); code != 0 {
+		t.Fatalf("expected index to succeed, got %d", code)
+	}
+
+	lib, err := readSymbolIndex(outPath)
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if loc := lib.defs["ITEMDEF I_GOLD"]; loc.file != "base.scp" || loc.line != 1 {
+		t.Fatalf("expected i_gold at base.scp:1, got %+v", loc)
+	}
+	if _, ok := lib.defnames["COIN_VALUE"]; !ok {
+		t.Fatalf("expected defname coin_value in the index, got %+v", lib.defnames)
+	}
+	if owner := lib.owners["EVENTS E_COIN"]; owner == nil || !owner.triggers["DCLICK"] {
+		t.Fatalf("expected e_coin to keep its @DClick handler, got %+v", owner)
+	}
+
+	if !lib.templates.isTemplate("VENDOR_S_BANKER") {
+		t.Fatalf("expected template vendor_s_banker in the index, got %+v", lib.templates)
+	}
+
+	plugin := withTempScriptsDir(t)
+	index := newLintIndex()
+	errs := lintScriptFile(writeTempFile(t, plugin, "plugin.scp", joinLines(
+		"[FUNCTION f_pay]",
+		"SRC.NEWITEM i_gold",
+		"LOCAL.VALUE=<coin_value>",
+		"SRC.NEWITEM i_silver",
+		"[CHARDEF c_banker]",
+		"NAME=banker",
+		"SELL=vendor_s_banker",
+		"[EOF]",
+	)), index)
+	index.addLibrary(lib)
+	errs = append(errs, lintIndexIssues(index)...)
+	assertHasMessage(t, errs, "UNDECLARED: 'I_SILVER'")
+	if len(errs) != 1 {
+		t.Fatalf("expected only the undeclared i_silver, got %+v", errs)
+	}
+}
+
+func TestReadSymbolIndexVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defs.idx")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	if _, err := readSymbolIndex(path); err == nil {
+		t.Fatalf("expected an error for an unsupported index version")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tagCommonUses is how often a TAG name must appear before a near miss of it
+// is reported as a likely typo.
+const tagCommonUses = 3
+
+var tagNamePattern = regexp.MustCompile(`(?i)(?:^|[^A-Za-z0-9_])TAG0?\.([A-Za-z0-9_]+)(<?)`)
+
+// tagUsage counts the reads and writes of one TAG name across all files.
+type tagUsage struct {
+	reads  int
+	writes int
+	first  definitionLocation
+}
+
+// recordTagUses adds the TAG./TAG0. names a line uses to the inventory. A
+// name inside the line's leading statement key (TAG.X=1, SRC.TAG.X 1) is a
+// write; every other use is a read.
+func recordTagUses(tags map[string]*tagUsage, line, file string, lineNum int) {
+	keyEnd := 0
+	if loc := statementKeyPattern.FindStringSubmatchIndex(line); loc != nil {
+		keyEnd = loc[3]
+	}
+	for _, loc := range tagNamePattern.FindAllStringSubmatchIndex(line, -1) {
+		if loc[5] > loc[4] {
+			continue
+		}
+		name := strings.ToUpper(line[loc[2]:loc[3]])
+		usage := tags[name]
+		if usage == nil {
+			usage = &tagUsage{first: definitionLocation{file: file, line: lineNum}}
+			tags[name] = usage
+		}
+		if loc[3] <= keyEnd {
+			usage.writes++
+		} else {
+			usage.reads++
+		}
+	}
+}
+
+// findTagTypos warns about tags that are read in a single place, never set,
+// and within a small edit distance of a tag used at least tagCommonUses
+// times.
+func findTagTypos(tags map[string]*tagUsage) []lintIssue {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []lintIssue
+	for _, name := range names {
+		usage := tags[name]
+		if usage.reads != 1 || usage.writes != 0 {
+			continue
+		}
+		maxDistance := 1
+		if len(name) >= 6 {
+			maxDistance = 2
+		}
+		best, bestDistance, bestUses := "", 0, 0
+		for _, other := range names {
+			uses := tags[other].reads + tags[other].writes
+			if other == name || uses < tagCommonUses {
+				continue
+			}
+			distance := editDistance(name, other)
+			if distance > maxDistance {
+				continue
+			}
+			if best == "" || distance < bestDistance || (distance == bestDistance && uses > bestUses) {
+				best, bestDistance, bestUses = other, distance, uses
+			}
+		}
+		if best == "" {
+			continue
+		}
+		issues = appendWarning(issues, usage.first.file, usage.first.line, "TAG", fmt.Sprintf("TAG: 'TAG.%s' is read here but never set; did you mean 'TAG.%s' (used %d times)?", name, best, bestUses))
+	}
+	return issues
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestLintTagTypos(t *testing.T) {
+	content := joinLines(
+		"[FUNCTION f_quest]",
+		"SRC.TAG.QUESTSTEP=1",
+		"IF (<SRC.TAG.QUESTSTEP> == 1)",
+		"  SRC.TAG0.QUESTSTEP=2",
+		"ENDIF",
+		"IF (<SRC.TAG.QUSTSTEP> == 2)",
+		"  SRC.SYSMESSAGE <SRC.TAG.RARE>",
+		"ENDIF",
+		"SRC.CTAG.QUESTSTAP=1",
+		"[EOF]",
+	)
+
+	t.Run("Disabled", func(t *testing.T) {
+		assertNoErrors(t, lintFromContent(t, "tags.scp", content), "tag typos while the rule is off")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Enable = []string{"tag-typos"} })
+
+		errs := lintFromContent(t, "tags.scp", content)
+		assertHasMessage(t, errs, "TAG: 'TAG.QUSTSTEP' is read here but never set; did you mean 'TAG.QUESTSTEP' (used 3 times)?")
+		if len(errs) != 1 || errs[0].line != 6 || errs[0].severity != severityWarning {
+			t.Fatalf("expected a single warning on line 6, got %+v", errs)
+		}
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"QUESTSTEP", "QUESTSTEP", 0},
+		{"QUSTSTEP", "QUESTSTEP", 1},
+		{"KILLS", "KILSL", 2},
+		{"", "ABC", 3},
+	}
+	for _, c := range cases {
+		if got := editDistance(c.a, c.b); got != c.want {
+			t.Fatalf("editDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// templateEdge is an ITEM= or CONTAINER= line of a template that names
+// another definition, possibly another template.
+type templateEdge struct {
+	target    string
+	container bool
+	file      string
+	line      int
+}
+
+// templateGraph links templates to the definitions they instantiate, so
+// templates that end up instantiating themselves can be found.
+type templateGraph struct {
+	edges   map[string][]templateEdge
+	aliases map[string]string
+}
+
+func newTemplateGraph() *templateGraph {
+	return &templateGraph{edges: make(map[string][]templateEdge), aliases: make(map[string]string)}
+}
+
+// addNode registers a [TEMPLATE id] section, which may have no edges.
+func (g *templateGraph) addNode(id string) {
+	if _, ok := g.edges[id]; !ok && id != "" {
+		g.edges[id] = nil
+	}
+}
+
+// addAlias maps a DEFNAME= of a template onto its section ID.
+func (g *templateGraph) addAlias(name, id string) {
+	if name != id {
+		g.aliases[name] = id
+	}
+}
+
+// addLine records the targets of an ITEM= or CONTAINER= line of template id.
+func (g *templateGraph) addLine(id, line, file string, lineNum int) {
+	if id == "" {
+		return
+	}
+	var value string
+	container := false
+	if match := itemAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+		value = match[1]
+	} else if match := containerAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+		value, container = match[1], true
+	} else {
+		return
+	}
+	for _, ident := range extractTemplateIdentifiers(value) {
+		g.edges[id] = append(g.edges[id], templateEdge{target: strings.ToUpper(ident), container: container, file: file, line: lineNum})
+	}
+}
+
+func (g *templateGraph) resolve(name string) string {
+	if id, ok := g.aliases[name]; ok {
+		return id
+	}
+	return name
+}
+
+// isTemplate reports whether name is a template ID or DEFNAME.
+func (g *templateGraph) isTemplate(name string) bool {
+	_, ok := g.edges[g.resolve(name)]
+	return ok
+}
+
+// findCycles reports each template cycle once, at the line that closes it.
+func (g *templateGraph) findCycles() []lintIssue {
+	ids := make([]string, 0, len(g.edges))
+	for id := range g.edges {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var issues []lintIssue
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = active
+		path = append(path, id)
+		for _, edge := range g.edges[id] {
+			target := g.resolve(edge.target)
+			if _, ok := g.edges[target]; !ok {
+				continue
+			}
+			switch state[target] {
+			case unvisited:
+				visit(target)
+			case active:
+				if target == id {
+					issues = appendError(issues, edge.file, edge.line, "TEMPLATE", fmt.Sprintf("TEMPLATE: %s references itself", id))
+					continue
+				}
+				start := len(path) - 1
+				for path[start] != target {
+					start--
+				}
+				cycle := append(append([]string{}, path[start:]...), target)
+				issues = appendError(issues, edge.file, edge.line, "TEMPLATE", fmt.Sprintf("TEMPLATE: template cycle %s", strings.Join(cycle, " -> ")))
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return issues
+}
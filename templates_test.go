@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestLintTemplateCycles(t *testing.T) {
+	t.Run("Cycles", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_gold]",
+			"[TEMPLATE tm_self]",
+			"ITEM=tm_self",
+			"[TEMPLATE tm_a]",
+			"ITEM=i_gold",
+			"ITEM=tm_b",
+			"[TEMPLATE 01000]",
+			"DEFNAME=tm_b",
+			"CONTAINER=i_gold",
+			"ITEM={ tm_a 1 i_gold 1 }",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "template_cycles.scp", content)
+		assertHasMessage(t, errs, "TEMPLATE: TM_SELF references itself")
+		assertHasMessage(t, errs, "TEMPLATE: template cycle 01000 -> TM_A -> 01000")
+		if len(errs) != 2 || errs[0].line != 6 || errs[1].line != 3 {
+			t.Fatalf("expected 2 cycle errors on lines 6 and 3, got %+v", errs)
+		}
+	})
+
+	t.Run("Acyclic", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_gold]",
+			"[TEMPLATE tm_small]",
+			"ITEM=i_gold",
+			"[TEMPLATE tm_big]",
+			"ITEM=tm_small",
+			"ITEM=tm_small",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "template_acyclic.scp", content), "acyclic templates")
+	})
+}
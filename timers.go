@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var timerCallPattern = regexp.MustCompile(`(?i)^\s*(?:[a-z0-9_.]+\.)?(TIMERF|TIMERFMS)(?:\s*=\s*|\s+)(.*)$`)
+
+// checkTimerCall validates TIMERF/TIMERFMS <delay>,<function> calls and
+// records the scheduled function as a FUNCTION reference, so a misspelled
+// callback is caught even without the f_ prefix. CLEAR and STOP forms and
+// built-in or configured verbs (TIMERF 1,REMOVE) are skipped.
+func checkTimerCall(line, file string, lineNum int, references *[]referenceUse) []lintIssue {
+	match := timerCallPattern.FindStringSubmatch(line)
+	if len(match) != 3 {
+		return nil
+	}
+	verb := strings.ToUpper(match[1])
+	args := strings.TrimSpace(match[2])
+	switch strings.ToUpper(firstField(args)) {
+	case "", "CLEAR", "STOP":
+		return nil
+	}
+	comma := strings.IndexByte(args, ',')
+	if comma < 0 {
+		return appendError(nil, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s expects <delay>,<function> (missing comma)", verb))
+	}
+	name := firstField(args[comma+1:])
+	if name == "" {
+		return appendError(nil, file, lineNum, "SYNTAX", fmt.Sprintf("SYNTAX: %s is missing the function to call", verb))
+	}
+	if !isIdentifier(name) || isScriptVerb(name) || hasReferencePrefix(name) {
+		return nil
+	}
+	*references = append(*references, referenceUse{
+		file:     file,
+		line:     lineNum,
+		defTypes: []string{"FUNCTION"},
+		id:       strings.ToUpper(name),
+	})
+	return nil
+}
+
+// timerProperty describes how the server reads a timer-like property.
+type timerProperty struct {
+	unit string
+	// off reports whether -1 is accepted, which stops the timer.
+	off bool
+	// fractions is the first server version that reads decimal values; the
+	// property takes whole numbers everywhere when it is empty.
+	fractions string
+}
+
+var (
+	timerProperties = map[string]timerProperty{
+		"TIMER":  {unit: "seconds", off: true, fractions: "x"},
+		"TIMERD": {unit: "tenths of a second", off: true},
+		"DECAY":  {unit: "seconds"},
+	}
+
+	timerDecimalPattern = regexp.MustCompile(`^-?[0-9]+\.[0-9]+$`)
+)
+
+// checkTimerValue validates TIMER=, TIMERD= and DECAY= assignments: values
+// must not be negative unless -1 stops the timer, and must be whole numbers
+// where the target server version counts in ticks. Malformed literals are
+// reported by checkNumericLiteral; defnames, expressions, <...> reads and
+// {min max} ranges are left to the server.
+func checkTimerValue(line, file string, lineNum int) []lintIssue {
+	key, value, ok := splitAssignment(line)
+	if !ok || value == "" {
+		return nil
+	}
+	if inVariableNamespace(key) {
+		return nil
+	}
+	segments := strings.Split(key, ".")
+	property := segments[len(segments)-1]
+	spec, ok := timerProperties[property]
+	if !ok || strings.ContainsAny(value, "<{(+*/ \t") {
+		return nil
+	}
+	if timerDecimalPattern.MatchString(value) {
+		if spec.fractions != "" && versionIndex(config.TargetVersion) >= versionIndex(spec.fractions) {
+			return nil
+		}
+		return appendError(nil, file, lineNum, "TIMER", fmt.Sprintf("TIMER: %s=%s is not a whole number; %s %s counts in whole %s.", property, value, formatVersion(config.TargetVersion), property, spec.unit))
+	}
+	n, ok := parseSphereInt(value)
+	if !ok {
+		return nil
+	}
+	if n < 0 && !(spec.off && n == -1) {
+		if spec.off {
+			return appendError(nil, file, lineNum, "TIMER", fmt.Sprintf("TIMER: %s=%s is negative; use -1 to stop the timer.", property, value))
+		}
+		return appendError(nil, file, lineNum, "TIMER", fmt.Sprintf("TIMER: %s=%s is negative.", property, value))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import "testing"
+
+func TestLintTimerCalls(t *testing.T) {
+	t.Run("DefinedTargets", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_expire]",
+			"REMOVE",
+			"[FUNCTION expire_later]",
+			"REMOVE",
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"TIMERF=60,f_expire",
+			"TIMERF 60, expire_later 1",
+			"SRC.TIMERFMS 500,REMOVE",
+			"TIMERF <EVAL <MORE1>*60>,SAY bye",
+			"TIMERF CLEAR",
+			"TIMERF 1,EFFECT 3,0x376a,9,10",
+			"TIMERF 2,CONSUME 1",
+			"TIMERF 3,GO 1000,1000",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "timers_ok.scp", content), "defined timer targets")
+	})
+
+	t.Run("ConfiguredVerb", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.Verbs = []string{"HealParty"} })
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@DClick",
+			"TIMERF 5,HEALPARTY",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "timers_verb.scp", content), "a configured verb as timer target")
+	})
+
+	t.Run("MissingTargets", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION expire_later]",
+			"REMOVE",
+			"[ITEMDEF i_test]",
+			"ON=@Create",
+			"TIMERF 60,expire_latr",
+			"TIMERF 60 expire_later",
+			"TIMERF=60,",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "timers_bad.scp", content)
+		assertHasMessage(t, errs, "EXPIRE_LATR")
+		assertHasMessage(t, errs, "SYNTAX: TIMERF expects <delay>,<function> (missing comma)")
+		assertHasMessage(t, errs, "SYNTAX: TIMERF is missing the function to call")
+	})
+}
+
+func TestLintTimerValues(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_torch_lit]",
+		"ID=0a12",
+		"DECAY=600",
+		"ON=@Create",
+		"TIMER=-1",
+		"TIMERD=50",
+		"LINK.TIMER=<MORE1>",
+		"TIMER={5 10}",
+		"TIMER=-5",
+		"DECAY=-1",
+		"TIMERD=2.5",
+		"TIMER=1.5",
+		"TIMERD=5s",
+		"LOCAL.TIMER=-3",
+		"[EOF]",
+	)
+
+	t.Run("Default", func(t *testing.T) {
+		errs := lintFromContent(t, "timer_values.scp", content)
+		assertHasMessage(t, errs, "TIMER: TIMER=-5 is negative; use -1 to stop the timer.")
+		assertHasMessage(t, errs, "TIMER: DECAY=-1 is negative.")
+		assertHasMessage(t, errs, "TIMER: TIMERD=2.5 is not a whole number; 0.56d TIMERD counts in whole tenths of a second.")
+		assertHasMessage(t, errs, "TIMER: TIMER=1.5 is not a whole number; 0.56d TIMER counts in whole seconds.")
+		assertHasMessage(t, errs, "SYNTAX: TIMERD=5s: '5s' is not a valid number")
+		if len(errs) != 5 {
+			t.Fatalf("expected 5 errors, got %+v", errs)
+		}
+	})
+
+	t.Run("SphereX", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) {
+			cfg.TargetVersion = "x"
+		})
+		errs := lintFromContent(t, "timer_values.scp", content)
+		for _, err := range errs {
+			if err.line == 12 {
+				t.Fatalf("expected X to accept decimal TIMER values, got %+v", err)
+			}
+		}
+		assertHasMessage(t, errs, "TIMER: TIMERD=2.5 is not a whole number; X TIMERD counts in whole tenths of a second.")
+	})
+}
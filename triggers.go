@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	triggerCallPattern = regexp.MustCompile(`(?i)^\s*TRIGGER\s+@([A-Za-z0-9_]+)(<?)`)
+
+	// triggerOwnerTypes are the sections whose ON=@ handlers can be fired
+	// with TRIGGER @name on the object itself.
+	triggerOwnerTypes = map[string]bool{
+		"AREADEF": true, "CHARDEF": true, "EVENTS": true, "ITEMDEF": true,
+		"REGIONTYPE": true, "ROOMDEF": true, "TYPEDEF": true,
+	}
+)
+
+// triggerOwner is a definition that declares ON=@ handlers, together with
+// the EVENTS, TEVENTS and TYPE= attachments whose handlers it also runs.
+type triggerOwner struct {
+	key      string
+	triggers map[string]bool
+	attached []string
+}
+
+// triggerCall is a TRIGGER @name statement waiting to be resolved once
+// every file is indexed. owner is empty outside ITEMDEF/CHARDEF-like
+// sections, where the target object is not known statically.
+type triggerCall struct {
+	file  string
+	line  int
+	owner string
+	name  string
+}
+
+func (idx *lintIndex) triggerOwner(defType, args string) *triggerOwner {
+	id := strings.ToUpper(firstField(args))
+	if !triggerOwnerTypes[defType] || id == "" {
+		return nil
+	}
+	key := defType + " " + id
+	owner := idx.owners[key]
+	if owner == nil {
+		owner = &triggerOwner{key: key, triggers: make(map[string]bool)}
+		idx.owners[key] = owner
+	}
+	return owner
+}
+
+// addTrigger records an ON=@name handler of the owner.
+func (o *triggerOwner) addTrigger(line string) {
+	if o == nil {
+		return
+	}
+	if match := triggerNamePattern.FindStringSubmatch(line); len(match) == 2 {
+		o.triggers[strings.ToUpper(match[1])] = true
+	}
+}
+
+// addAttachments records EVENTS=, TEVENTS= and TYPE= attachments, including
+// the EVENTS +e_x form used inside triggers. Attachments to other objects
+// (SRC.EVENTS +e_x) are ignored.
+func (o *triggerOwner) addAttachments(line string) {
+	if o == nil {
+		return
+	}
+	key, value, ok := attachmentLine(line)
+	if !ok {
+		return
+	}
+	defType := ""
+	switch key {
+	case "EVENTS", "TEVENTS":
+		defType = "EVENTS"
+	case "TYPE":
+		defType = "TYPEDEF"
+	default:
+		return
+	}
+	for _, entry := range strings.Split(value, ",") {
+		id := firstField(strings.TrimLeft(strings.TrimSpace(entry), "+-"))
+		if isIdentifier(id) {
+			o.attached = append(o.attached, defType+" "+strings.ToUpper(id))
+		}
+	}
+}
+
+// attachmentLine splits KEY=value and the KEY value form of verbs such as
+// EVENTS +e_x.
+func attachmentLine(line string) (string, string, bool) {
+	if key, value, ok := splitAssignment(line); ok {
+		return key, value, true
+	}
+	fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	return strings.ToUpper(fields[0]), fields[1], true
+}
+
+// checkDuplicateEvents warns when an ITEMDEF or CHARDEF attaches an events
+// block it already attached; on some server versions its triggers then fire
+// twice. attached maps EVENTS/TEVENTS keys to the line that attached them,
+// and -e_x removals or an empty assignment forget them again.
+func checkDuplicateEvents(attached map[string]int, line, file string, lineNum int) []lintIssue {
+	if attached == nil {
+		return nil
+	}
+	key, value, ok := attachmentLine(line)
+	if !ok || (key != "EVENTS" && key != "TEVENTS") {
+		return nil
+	}
+	if strings.TrimSpace(value) == "" {
+		for name := range attached {
+			if strings.HasPrefix(name, key+" ") {
+				delete(attached, name)
+			}
+		}
+		return nil
+	}
+	var issues []lintIssue
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		id := firstField(strings.TrimLeft(entry, "+-"))
+		if !isIdentifier(id) {
+			continue
+		}
+		name := key + " " + strings.ToUpper(id)
+		if strings.HasPrefix(entry, "-") {
+			delete(attached, name)
+			continue
+		}
+		if first, ok := attached[name]; ok {
+			issues = appendWarning(issues, file, lineNum, "DUPLICATE", fmt.Sprintf("DUPLICATE: %s %s is already attached at line %d; its triggers may fire twice.", key, id, first))
+			continue
+		}
+		attached[name] = lineNum
+	}
+	return issues
+}
+
+// recordTriggerCall queues a bare TRIGGER @name statement for resolution.
+func (idx *lintIndex) recordTriggerCall(owner *triggerOwner, line, file string, lineNum int) {
+	match := triggerCallPattern.FindStringSubmatch(line)
+	if len(match) != 3 || match[2] != "" {
+		return
+	}
+	call := triggerCall{file: file, line: lineNum, name: strings.ToUpper(match[1])}
+	if owner != nil && !strings.HasPrefix(owner.key, "EVENTS ") && !strings.HasPrefix(owner.key, "TYPEDEF ") {
+		call.owner = owner.key
+	}
+	idx.triggerCalls = append(idx.triggerCalls, call)
+}
+
+// findTriggerCallIssues reports TRIGGER @name calls with no matching
+// handler. Calls from a definition must be handled by the definition itself
+// or something attached to it; calls from EVENTS, TYPEDEF and FUNCTION
+// sections only need a handler somewhere. Built-in triggers and the
+// configured sharedTriggers are always accepted.
+func findTriggerCallIssues(owners map[string]*triggerOwner, calls []triggerCall) []lintIssue {
+	shared := make(map[string]bool)
+	for _, name := range config.SharedTriggers {
+		shared[strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(name), "@"))] = true
+	}
+	anywhere := make(map[string]bool)
+	for _, owner := range owners {
+		for name := range owner.triggers {
+			anywhere[name] = true
+		}
+	}
+
+	var issues []lintIssue
+	for _, call := range calls {
+		if shared[call.name] {
+			continue
+		}
+		if _, builtin := triggerContexts[call.name]; builtin {
+			continue
+		}
+		if call.owner == "" {
+			if !anywhere[call.name] {
+				issues = appendError(issues, call.file, call.line, "TRIGGER", fmt.Sprintf("TRIGGER: no ON=@%s handler is defined for TRIGGER @%s", call.name, call.name))
+			}
+			continue
+		}
+		if !ownerHandles(owners, call.owner, call.name) {
+			issues = appendError(issues, call.file, call.line, "TRIGGER", fmt.Sprintf("TRIGGER: %s has no ON=@%s handler, and none of its EVENTS/TYPEDEF attachments define one", call.owner, call.name))
+		}
+	}
+	return issues
+}
+
+func ownerHandles(owners map[string]*triggerOwner, key, name string) bool {
+	owner := owners[key]
+	if owner == nil {
+		return false
+	}
+	if owner.triggers[name] {
+		return true
+	}
+	for _, attached := range owner.attached {
+		if other := owners[attached]; other != nil && other.triggers[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package main
+
+import "testing"
+
+func TestLintCustomTriggerCalls(t *testing.T) {
+	t.Run("ResolvedHandlers", func(t *testing.T) {
+		content := joinLines(
+			"[EVENTS e_quest]",
+			"ON=@QuestDone",
+			"SRC.SYSMESSAGE done",
+			"[TYPEDEF t_lever]",
+			"ON=@Pull",
+			"TRIGGER @QuestDone",
+			"[ITEMDEF i_lever]",
+			"TYPE=t_lever",
+			"ON=@DClick",
+			"TRIGGER @Pull",
+			"TRIGGER @Reset",
+			"TRIGGER @Create",
+			"ON=@Reset",
+			"REMOVE",
+			"[CHARDEF c_hero]",
+			"ON=@Login",
+			"EVENTS +e_quest",
+			"TRIGGER @QuestDone",
+			"TRIGGER @<TAG.NEXTSTEP>",
+			"[FUNCTION f_done]",
+			"TRIGGER @QuestDone",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "triggers_ok.scp", content), "resolved custom triggers")
+	})
+
+	t.Run("MissingHandlers", func(t *testing.T) {
+		content := joinLines(
+			"[EVENTS e_quest]",
+			"ON=@QuestDone",
+			"SRC.SYSMESSAGE done",
+			"[ITEMDEF i_lever]",
+			"ON=@DClick",
+			"TRIGGER @QuestDone",
+			"[FUNCTION f_done]",
+			"TRIGGER @QuestDoen",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "triggers_bad.scp", content)
+		assertHasMessage(t, errs, "TRIGGER: ITEMDEF I_LEVER has no ON=@QUESTDONE handler, and none of its EVENTS/TYPEDEF attachments define one")
+		assertHasMessage(t, errs, "TRIGGER: no ON=@QUESTDOEN handler is defined for TRIGGER @QUESTDOEN")
+	})
+
+	t.Run("SharedTriggers", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.SharedTriggers = []string{"@QuestDone"} })
+		content := joinLines(
+			"[ITEMDEF i_lever]",
+			"ON=@DClick",
+			"TRIGGER @QuestDone",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "triggers_shared.scp", content), "shared triggers")
+	})
+}
+
+func TestLintDuplicateEvents(t *testing.T) {
+	content := joinLines(
+		"[EVENTS e_guard]",
+		"[EVENTS e_loot]",
+		"[CHARDEF c_guard]",
+		"EVENTS=e_guard,e_loot,e_guard",
+		"TEVENTS=e_guard",
+		"ON=@NPCRestock",
+		"  EVENTS +e_loot",
+		"  EVENTS -e_guard",
+		"  EVENTS +e_guard",
+		"  SRC.EVENTS +e_loot",
+		"[ITEMDEF i_chest]",
+		"EVENTS=e_loot",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "events.scp", content)
+	assertHasMessage(t, errs, "DUPLICATE: EVENTS e_guard is already attached at line 4; its triggers may fire twice.")
+	assertHasMessage(t, errs, "DUPLICATE: EVENTS e_loot is already attached at line 4; its triggers may fire twice.")
+	if len(errs) != 2 || errs[0].line != 4 || errs[1].line != 7 || errs[0].severity != severityWarning {
+		t.Fatalf("expected duplicate EVENTS warnings on lines 4 and 7, got %+v", errs)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typeDataField is what one TDATA property of an item TYPE holds.
+type typeDataField struct {
+	field    string
+	what     string
+	item     bool
+	max      int
+	required bool
+}
+
+// typeDataSpecs lists, per item TYPE, the TDATA fields the server reads. An
+// item field takes an ITEMDEF defname or a hex item ID; the others take a
+// number up to max.
+var typeDataSpecs = map[string][]typeDataField{
+	"T_DOOR":             {{field: "TDATA1", what: "the item ID the door turns into when opened", item: true, required: true}},
+	"T_DOOR_LOCKED":      {{field: "TDATA1", what: "the item ID the door turns into when opened", item: true, required: true}},
+	"T_KEY":              {{field: "TDATA1", what: "the key item it is linked to", item: true}},
+	"T_CONTAINER":        {{field: "TDATA2", what: "the container gump ID", max: 0xFFFF}},
+	"T_CONTAINER_LOCKED": {{field: "TDATA2", what: "the container gump ID", max: 0xFFFF}},
+	"T_MUSICAL": {
+		{field: "TDATA1", what: "the sound played when the instrument is played well", max: 0xFFFF},
+		{field: "TDATA2", what: "the sound played when the instrument is played badly", max: 0xFFFF},
+	},
+	"T_WEAPON_BOW": {
+		{field: "TDATA3", what: "the ammunition item", item: true},
+		{field: "TDATA4", what: "the ammunition animation ID", max: 0xFFFF},
+	},
+	"T_WEAPON_XBOW": {
+		{field: "TDATA3", what: "the ammunition item", item: true},
+		{field: "TDATA4", what: "the ammunition animation ID", max: 0xFFFF},
+	},
+}
+
+// typeDataState collects the TYPE= and TDATA1-4 lines of an ITEMDEF so
+// they can be checked together once the section ends.
+type typeDataState struct {
+	typ        string
+	typeLine   int
+	values     map[string]string
+	lines      map[string]int
+	references *[]referenceUse
+}
+
+func newTypeDataState(references *[]referenceUse) *typeDataState {
+	return &typeDataState{values: make(map[string]string), lines: make(map[string]int), references: references}
+}
+
+// addLine records TYPE= and TDATAn= assignments outside triggers.
+func (s *typeDataState) addLine(line string, lineNum int) {
+	if s == nil {
+		return
+	}
+	key, value, ok := splitAssignment(line)
+	if !ok {
+		return
+	}
+	switch key {
+	case "TYPE":
+		s.typ, s.typeLine = strings.ToUpper(value), lineNum
+	case "TDATA1", "TDATA2", "TDATA3", "TDATA4":
+		s.values[key], s.lines[key] = value, lineNum
+	}
+}
+
+// finish checks the TDATA fields against what the item's TYPE expects.
+// Item defnames without one of the usual prefixes are queued as references
+// so they are resolved against every file.
+func (s *typeDataState) finish(file string) []lintIssue {
+	if s == nil {
+		return nil
+	}
+	var issues []lintIssue
+	for _, spec := range typeDataSpecs[s.typ] {
+		value, ok := s.values[spec.field]
+		if !ok || value == "" {
+			if spec.required {
+				issues = appendError(issues, file, s.typeLine, "TDATA", fmt.Sprintf("TDATA: TYPE=%s needs %s (%s).", strings.ToLower(s.typ), spec.field, spec.what))
+			}
+			continue
+		}
+		if strings.ContainsAny(value, "<>") {
+			continue
+		}
+		lineNum := s.lines[spec.field]
+		if n, ok := parseSphereInt(value); ok {
+			max := spec.max
+			if spec.item {
+				max = 0xFFFF
+			}
+			if n < 0 || n > max || (spec.item && n == 0) {
+				issues = appendError(issues, file, lineNum, "TDATA", fmt.Sprintf("TDATA: %s=%s is out of range for %s (0-0%x).", spec.field, value, spec.what, max))
+			}
+			continue
+		}
+		if !spec.item || !isIdentifier(value) {
+			issues = appendError(issues, file, lineNum, "TDATA", fmt.Sprintf("TDATA: %s='%s' of TYPE=%s is not %s.", spec.field, value, strings.ToLower(s.typ), spec.what))
+			continue
+		}
+		if !hasReferencePrefix(value) {
+			*s.references = append(*s.references, referenceUse{file: file, line: lineNum, defTypes: []string{"ITEMDEF"}, id: strings.ToUpper(value)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+func TestLintTypeData(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_door_wood]",
+			"ID=0675",
+			"TYPE=t_door",
+			"TDATA1=i_door_wood_open",
+			"[ITEMDEF i_door_wood_open]",
+			"ID=0676",
+			"TYPE=t_door",
+			"TDATA1=0675",
+			"[ITEMDEF i_crate]",
+			"ID=0e3c",
+			"TYPE=t_container",
+			"TDATA2=044",
+			"[ITEMDEF i_longbow]",
+			"ID=013b2",
+			"TYPE=t_weapon_bow",
+			"TDATA3=arrow_ammo",
+			"TDATA4=0f42",
+			"[ITEMDEF 0f3f]",
+			"DEFNAME=arrow_ammo",
+			"[TYPEDEF t_door]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[TYPEDEF t_container]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[TYPEDEF t_weapon_bow]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "tdata_valid.scp", content), "TDATA matching the item TYPE")
+	})
+
+	t.Run("Reported", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_door_iron]",
+			"ID=0675",
+			"TYPE=t_door",
+			"[ITEMDEF i_chest]",
+			"ID=0e40",
+			"TYPE=t_container",
+			"TDATA2=0123456",
+			"[ITEMDEF i_lute]",
+			"ID=0eb3",
+			"TYPE=t_musical",
+			"TDATA1=loud noise",
+			"[ITEMDEF i_xbow]",
+			"ID=0f4f",
+			"TYPE=t_weapon_xbow",
+			"TDATA3=bolt_ammo",
+			"[TYPEDEF t_door]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[TYPEDEF t_container]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[TYPEDEF t_musical]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[TYPEDEF t_weapon_xbow]",
+			"ON=@DClick",
+			"RETURN 1",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "tdata_errors.scp", content)
+		assertHasMessage(t, errs, "TDATA: TYPE=t_door needs TDATA1 (the item ID the door turns into when opened).")
+		assertHasMessage(t, errs, "TDATA: TDATA2=0123456 is out of range for the container gump ID (0-0ffff).")
+		assertHasMessage(t, errs, "TDATA: TDATA1='loud noise' of TYPE=t_musical is not the sound played when the instrument is played well.")
+		assertHasMessage(t, errs, "UNDECLARED: 'BOLT_AMMO' not defined as ITEMDEF")
+		if len(errs) != 4 || errs[0].line != 3 || errs[3].line != 15 {
+			t.Fatalf("expected 4 errors, got %+v", errs)
+		}
+	})
+}
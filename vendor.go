@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var vendorAssignPattern = regexp.MustCompile(`(?i)^\s*(SELL|BUY)\s*=\s*(.*)$`)
+
+// vendorUse is a CHARDEF line that stocks an NPC from a template: SELL= and
+// BUY= vendor lists, or ITEM= entries that name a template.
+type vendorUse struct {
+	file     string
+	line     int
+	verb     string
+	template string
+}
+
+// recordVendorLine collects the templates a CHARDEF stocks its NPC with.
+func (idx *lintIndex) recordVendorLine(defType, line, file string, lineNum int) {
+	if defType != "CHARDEF" {
+		return
+	}
+	verb, value := "", ""
+	if match := vendorAssignPattern.FindStringSubmatch(line); len(match) == 3 {
+		verb, value = strings.ToUpper(match[1]), match[2]
+	} else if match := itemAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+		verb, value = "ITEM", match[1]
+	} else {
+		return
+	}
+	idents := extractTemplateIdentifiers(value)
+	if verb != "ITEM" {
+		if len(idents) == 0 || strings.ContainsAny(value, "<>") {
+			return
+		}
+		idents = idents[:1]
+	}
+	for _, ident := range idents {
+		idx.vendorUses = append(idx.vendorUses, vendorUse{file: file, line: lineNum, verb: verb, template: strings.ToUpper(ident)})
+	}
+}
+
+// findVendorTemplateIssues checks that SELL=/BUY= name templates, and that
+// the templates a CHARDEF stocks (and the templates they nest) only list
+// ITEMDEF and TEMPLATE entries.
+func findVendorTemplateIssues(index *lintIndex) []lintIssue {
+	var otherTypes []string
+	for defType := range trackDefTypes {
+		if defType != "ITEMDEF" && defType != "TEMPLATE" {
+			otherTypes = append(otherTypes, defType)
+		}
+	}
+	sort.Strings(otherTypes)
+
+	var issues []lintIssue
+	checked := make(map[string]bool)
+	var check func(id string)
+	check = func(id string) {
+		if checked[id] {
+			return
+		}
+		checked[id] = true
+		for _, edge := range index.templates.edges[id] {
+			if edge.container {
+				continue
+			}
+			if index.templates.isTemplate(edge.target) {
+				check(index.templates.resolve(edge.target))
+				continue
+			}
+			for _, defType := range otherTypes {
+				if _, ok := index.defs[defType+" "+edge.target]; ok {
+					issues = appendError(issues, edge.file, edge.line, "VENDOR", fmt.Sprintf("VENDOR: template %s stocks %s, which is a %s; only ITEMDEF and TEMPLATE entries can be stocked", id, edge.target, defType))
+					break
+				}
+			}
+		}
+	}
+
+	for _, use := range index.vendorUses {
+		if !index.templates.isTemplate(use.template) {
+			if use.verb != "ITEM" {
+				issues = appendError(issues, use.file, use.line, "VENDOR", fmt.Sprintf("VENDOR: %s=%s does not name a TEMPLATE", use.verb, use.template))
+			}
+			continue
+		}
+		check(index.templates.resolve(use.template))
+	}
+	return issues
+}
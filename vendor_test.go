@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestLintVendorTemplates(t *testing.T) {
+	t.Run("ValidStock", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_sword]",
+			"[ITEMDEF 0eed]",
+			"DEFNAME=i_gold",
+			"[TEMPLATE vendor_s_weapons]",
+			"ITEM=i_sword,R5",
+			"ITEM=vendor_extras",
+			"[TEMPLATE 01000]",
+			"DEFNAME=vendor_extras",
+			"ITEM=i_gold",
+			"[CHARDEF c_smith]",
+			"ON=@NPCRestock",
+			"SELL=vendor_s_weapons",
+			"BUY=vendor_s_weapons",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "vendor_ok.scp", content), "valid vendor templates")
+	})
+
+	t.Run("InvalidStock", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_sword]",
+			"[CHARDEF c_ogre]",
+			"[TEMPLATE vendor_s_weapons]",
+			"ITEM=i_sword",
+			"ITEM=vendor_nested",
+			"[TEMPLATE vendor_nested]",
+			"ITEM=c_ogre",
+			"[CHARDEF c_smith]",
+			"ON=@NPCRestock",
+			"SELL=vendor_s_weapons",
+			"BUY=i_sword",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "vendor_bad.scp", content)
+		assertHasMessage(t, errs, "VENDOR: template VENDOR_NESTED stocks C_OGRE, which is a CHARDEF; only ITEMDEF and TEMPLATE entries can be stocked")
+		assertHasMessage(t, errs, "VENDOR: BUY=I_SWORD does not name a TEMPLATE")
+	})
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// serverVersions lists the supported target versions from oldest to newest.
+var serverVersions = []string{"55i", "56a", "56b", "56c", "56d", "x"}
+
+// deprecation describes a keyword or trigger that stopped working (or was
+// renamed) in a given server version. Entries marked changed still exist but
+// behave differently; they are only reported by the migrate command.
+type deprecation struct {
+	name        string
+	trigger     bool
+	removed     string
+	replacement string
+	changed     bool
+	note        string
+}
+
+type deprecationMatch struct {
+	label string
+	rule  deprecation
+}
+
+var (
+	// deprecations are reported as warnings, since valid scripts are
+	// checked against this table by default; only add an entry once a
+	// server changelog confirms the removal.
+	deprecations = []deprecation{
+		{name: "USERDCLICK", trigger: true, removed: "56a", replacement: "@DClick"},
+		{name: "USERCLICK", trigger: true, removed: "56a", replacement: "@Click"},
+		{name: "NEWDUPE", removed: "56c", replacement: "DUPE"},
+		{name: "TIMERD", removed: "x", changed: true, note: "timers run on millisecond ticks; re-check TIMERD arithmetic"},
+	}
+
+	statementKeyPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_.]*)`)
+	angleKeyPattern     = regexp.MustCompile(`<([A-Za-z_][A-Za-z0-9_.]*)`)
+	triggerNamePattern  = regexp.MustCompile(`(?i)^\s*ON\s*=\s*@([a-z0-9_]+)`)
+)
+
+// normalizeVersion maps "0.56d", "56D" and "X" style versions onto the
+// serverVersions spelling.
+func normalizeVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	version = strings.TrimPrefix(version, "v")
+	version = strings.TrimPrefix(version, "0.")
+	if strings.HasPrefix(version, "x") {
+		return "x"
+	}
+	return version
+}
+
+func versionIndex(version string) int {
+	version = normalizeVersion(version)
+	for i, v := range serverVersions {
+		if v == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func isKnownVersion(version string) bool {
+	return versionIndex(version) >= 0
+}
+
+func formatVersion(version string) string {
+	version = normalizeVersion(version)
+	if version == "x" {
+		return "X"
+	}
+	return "0." + version
+}
+
+// activeDeprecations returns the removed keywords and triggers that apply
+// to the target version.
+func activeDeprecations(target string) map[string]deprecation {
+	active := make(map[string]deprecation)
+	targetIdx := versionIndex(target)
+	if targetIdx < 0 {
+		return active
+	}
+	for _, d := range deprecations {
+		if !d.changed && versionIndex(d.removed) <= targetIdx {
+			active[deprecationKey(d.name, d.trigger)] = d
+		}
+	}
+	return active
+}
+
+// migrationRules returns every removal or behaviour change introduced after
+// from and up to (and including) to.
+func migrationRules(from, to string) map[string]deprecation {
+	rules := make(map[string]deprecation)
+	fromIdx, toIdx := versionIndex(from), versionIndex(to)
+	for _, d := range deprecations {
+		idx := versionIndex(d.removed)
+		if idx > fromIdx && idx <= toIdx {
+			rules[deprecationKey(d.name, d.trigger)] = d
+		}
+	}
+	return rules
+}
+
+func deprecationKey(name string, trigger bool) string {
+	if trigger {
+		return "@" + strings.ToUpper(name)
+	}
+	return strings.ToUpper(name)
+}
+
+func deprecationMessage(d deprecation, label string) string {
+	if d.changed {
+		return fmt.Sprintf("DEPRECATED: '%s' changed in %s: %s.", label, formatVersion(d.removed), d.note)
+	}
+	return fmt.Sprintf("DEPRECATED: '%s' was removed in %s; use %s instead.", label, formatVersion(d.removed), d.replacement)
+}
+
+// findDeprecatedTrigger matches ON=@Name lines whose trigger no longer fires
+// on the target version.
+func findDeprecatedTrigger(line string, active map[string]deprecation) []deprecationMatch {
+	match := triggerNamePattern.FindStringSubmatch(line)
+	if len(match) != 2 {
+		return nil
+	}
+	d, ok := active[deprecationKey(match[1], true)]
+	if !ok {
+		return nil
+	}
+	return []deprecationMatch{{label: "@" + match[1], rule: d}}
+}
+
+// statementKeys returns the keywords a line uses: its leading verb or
+// assignment target plus the names read through <...> expressions.
+func statementKeys(line string) []string {
+	var keys []string
+	if match := statementKeyPattern.FindStringSubmatch(line); len(match) == 2 {
+		keys = append(keys, match[1])
+	}
+	for _, match := range angleKeyPattern.FindAllStringSubmatch(line, -1) {
+		keys = append(keys, match[1])
+	}
+	return keys
+}
+
+// findDeprecatedKeywords matches verbs and properties that no longer exist
+// on the target version. Children of variable namespaces are skipped.
+func findDeprecatedKeywords(line string, active map[string]deprecation) []deprecationMatch {
+	if len(active) == 0 {
+		return nil
+	}
+	var matches []deprecationMatch
+	for _, token := range statementKeys(line) {
+		segments := strings.Split(strings.ToUpper(token), ".")
+		name := segments[len(segments)-1]
+		d, ok := active[name]
+		if !ok {
+			continue
+		}
+		if inVariableNamespace(strings.ToUpper(token)) {
+			continue
+		}
+		matches = append(matches, deprecationMatch{label: token, rule: d})
+	}
+	return matches
+}
+
+func appendDeprecationIssues(issues []lintIssue, file string, lineNum int, matches []deprecationMatch) []lintIssue {
+	for _, m := range matches {
+		issues = appendWarning(issues, file, lineNum, "DEPRECATED", deprecationMessage(m.rule, m.label))
+	}
+	return issues
+}
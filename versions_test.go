@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestLintDeprecatedKeywords(t *testing.T) {
+	t.Run("RemovedOnTargetVersion", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@UserDClick",
+			"SRC.NEWDUPE",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "deprecated.scp", content)
+		assertHasMessage(t, errs, "DEPRECATED: '@UserDClick' was removed in 0.56a; use @DClick instead.")
+		assertHasMessage(t, errs, "DEPRECATED: 'SRC.NEWDUPE' was removed in 0.56c; use DUPE instead.")
+		for _, e := range errs {
+			if e.severity != severityWarning {
+				t.Fatalf("expected deprecations to be warnings, got %+v", e)
+			}
+		}
+	})
+
+	t.Run("CurrentKeywords", func(t *testing.T) {
+		content := joinLines(
+			"[CHARDEF c_test]",
+			"ON=@ItemStep",
+			"SRC.SYSMESSAGEF %s,hello",
+			"ACT.DCLICK",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "deprecated_current.scp", content), "keywords the server still supports")
+	})
+
+	t.Run("OlderTargetVersion", func(t *testing.T) {
+		withConfig(t, func(cfg *lintConfig) { cfg.TargetVersion = "0.55i" })
+		content := joinLines(
+			"[ITEMDEF i_test]",
+			"ON=@UserDClick",
+			"SRC.NEWDUPE",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "deprecated_55i.scp", content), "55i target")
+	})
+
+	t.Run("UserVariablesAndText", func(t *testing.T) {
+		content := joinLines(
+			"[FUNCTION f_test]",
+			"LOCAL.NEWDUPE=1",
+			"SRC.SYSMESSAGE use SAFE mode",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "deprecated_vars.scp", content), "variables and text")
+	})
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	cases := map[string]string{
+		"0.56d":  "56d",
+		"56D":    "56d",
+		"v0.55i": "55i",
+		"X1":     "x",
+	}
+	for in, want := range cases {
+		if got := normalizeVersion(in); got != want {
+			t.Fatalf("normalizeVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if isKnownVersion("57a") {
+		t.Fatalf("expected 57a to be unknown")
+	}
+}
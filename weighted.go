@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkWeightedListLine validates { entry weight ... } random lists outside
+// TEMPLATE sections (which go through validateTemplateLine): ITEM= and
+// CONTAINER= lines, SPAWN members and [DEFNAME] random lists.
+func checkWeightedListLine(defType, line, file string, lineNum int) []lintIssue {
+	value := ""
+	switch {
+	case defType == "TEMPLATE":
+		return nil
+	case isDefnameSection(defType):
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) == 2 {
+			value = fields[1]
+		}
+	case defType == "SPAWN":
+		_, value, _ = splitAssignment(line)
+	default:
+		if match := itemAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+			value = match[1]
+		} else if match := containerAssignPattern.FindStringSubmatch(line); len(match) == 2 {
+			value = match[1]
+		}
+	}
+	var issues []lintIssue
+	for _, msg := range validateWeightedLists(value) {
+		issues = appendError(issues, file, lineNum, "SYNTAX", msg)
+	}
+	return issues
+}
+
+// validateWeightedLists checks every {...} group of value. Numeric groups
+// are {low high} ranges; any other group must hold entry/weight pairs, where
+// entries may be nested groups and weights are positive integers or
+// <expressions>.
+func validateWeightedLists(value string) []string {
+	var errors []string
+	for _, group := range braceGroups(value) {
+		tokens := weightedListTokens(group)
+		if len(tokens) == 0 {
+			continue
+		}
+		allNumeric := true
+		for _, token := range tokens {
+			if !isAllDigits(strings.TrimPrefix(token, "-")) {
+				allNumeric = false
+				break
+			}
+		}
+		if allNumeric {
+			if msg := rangeOrderProblem(group, tokens); msg != "" {
+				errors = append(errors, msg)
+			}
+			continue
+		}
+		if len(tokens)%2 != 0 {
+			errors = append(errors, fmt.Sprintf("SYNTAX: weighted list '{%s}' needs entry/weight pairs, got %d item(s)", group, len(tokens)))
+			continue
+		}
+		for i := 1; i < len(tokens); i += 2 {
+			weight := tokens[i]
+			if strings.HasPrefix(weight, "<") {
+				continue
+			}
+			if n, ok := parseSphereInt(weight); !ok || n <= 0 || !isAllDigits(weight) {
+				errors = append(errors, fmt.Sprintf("SYNTAX: weighted list weight '%s' after '%s' must be a positive integer or <expression>", weight, tokens[i-1]))
+			}
+		}
+	}
+	return errors
+}
+
+// rangeOrderProblem checks a {low high} numeric range: neither bound may be
+// negative and low may not exceed high.
+func rangeOrderProblem(group string, tokens []string) string {
+	if len(tokens) != 2 {
+		return ""
+	}
+	low, okLow := parseSphereInt(tokens[0])
+	high, okHigh := parseSphereInt(tokens[1])
+	if !okLow || !okHigh {
+		return ""
+	}
+	if low < 0 || high < 0 {
+		return fmt.Sprintf("SYNTAX: range {%s} has a negative bound", group)
+	}
+	if low > high {
+		return fmt.Sprintf("SYNTAX: range {%s} has its low bound above its high bound", group)
+	}
+	return ""
+}
+
+// braceGroups returns the contents of every {...} group in value, outer
+// groups before the groups nested in them.
+func braceGroups(value string) []string {
+	var groups []string
+	var starts []int
+	type span struct{ start, end int }
+	var spans []span
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			starts = append(starts, i)
+		case '}':
+			if len(starts) == 0 {
+				continue
+			}
+			start := starts[len(starts)-1]
+			starts = starts[:len(starts)-1]
+			spans = append(spans, span{start, i})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	for _, s := range spans {
+		groups = append(groups, value[s.start+1:s.end])
+	}
+	return groups
+}
+
+// weightedListTokens splits the inside of a {...} group into whitespace
+// separated tokens, keeping nested {...} groups and <...> expressions whole.
+func weightedListTokens(group string) []string {
+	var tokens []string
+	braces, angles := 0, 0
+	start := -1
+	for i := 0; i <= len(group); i++ {
+		if i == len(group) || ((group[i] == ' ' || group[i] == '\t' || group[i] == ',') && braces == 0 && angles == 0) {
+			if start >= 0 {
+				tokens = append(tokens, group[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		switch group[i] {
+		case '{':
+			braces++
+		case '}':
+			if braces > 0 {
+				braces--
+			}
+		case '<':
+			angles++
+		case '>':
+			if angles > 0 {
+				angles--
+			}
+		}
+	}
+	return tokens
+}
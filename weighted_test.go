@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestLintWeightedLists(t *testing.T) {
+	t.Run("Malformed", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_a]",
+			"[ITEMDEF i_b]",
+			"[TEMPLATE tm_loot]",
+			"ITEM={ i_a 1 i_b }",
+			"[DEFNAME random_gems]",
+			"gem_list { i_a 0 i_b x }",
+			"[CHARDEF c_test]",
+			"ON=@Create",
+			"ITEM={ i_a 1 { i_a 1 i_b } 2 }",
+			"[EOF]",
+		)
+
+		errs := lintFromContent(t, "weighted_bad.scp", content)
+		assertHasMessage(t, errs, "SYNTAX: weighted list '{ i_a 1 i_b }' needs entry/weight pairs, got 3 item(s)")
+		assertHasMessage(t, errs, "SYNTAX: weighted list weight '0' after 'i_a' must be a positive integer or <expression>")
+		assertHasMessage(t, errs, "SYNTAX: weighted list weight 'x' after 'i_b' must be a positive integer or <expression>")
+		assertHasMessage(t, errs, "SYNTAX: weighted list '{ i_a 1 i_b }' needs entry/weight pairs")
+		if len(errs) != 4 {
+			t.Fatalf("expected 4 weighted list errors, got %+v", errs)
+		}
+	})
+
+	t.Run("WellFormed", func(t *testing.T) {
+		content := joinLines(
+			"[ITEMDEF i_a]",
+			"[ITEMDEF i_b]",
+			"[TEMPLATE tm_loot]",
+			"ITEM={ i_a 1 i_b 3 }",
+			"ITEM=i_a,{1 5}",
+			"ITEM={ { i_a 1 i_b 1 } 5 i_b 2 }",
+			"[DEFNAME random_gems]",
+			"gem_list { i_a <VAR.GEMWEIGHT> i_b 2 }",
+			"[SPAWN spawn_orcs]",
+			"ID={ i_a 2 i_b 1 }",
+			"[EOF]",
+		)
+
+		assertNoErrors(t, lintFromContent(t, "weighted_ok.scp", content), "well-formed weighted lists")
+	})
+}
+
+func TestLintRangeOrdering(t *testing.T) {
+	content := joinLines(
+		"[ITEMDEF i_gold]",
+		"[TEMPLATE tm_loot]",
+		"ITEM=i_gold,{5 1}",
+		"ITEM=i_gold,{-1 5}",
+		"ITEM=i_gold,{1 5}",
+		"ITEM=i_gold,{05 010}",
+		"[CHARDEF c_test]",
+		"ON=@Create",
+		"ITEM=i_gold,{20 10}",
+		"[EOF]",
+	)
+
+	errs := lintFromContent(t, "ranges.scp", content)
+	assertHasMessage(t, errs, "SYNTAX: range {5 1} has its low bound above its high bound")
+	assertHasMessage(t, errs, "SYNTAX: range {-1 5} has a negative bound")
+	assertHasMessage(t, errs, "SYNTAX: range {20 10} has its low bound above its high bound")
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 range errors, got %+v", errs)
+	}
+}